@@ -0,0 +1,49 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package adc defines the interface shared by the ADC drivers, so
+// applications can use any of them interchangeably.
+package adc
+
+import (
+	"errors"
+)
+
+// Reader reads the channels of an ADC.
+type Reader interface {
+	// Read returns the value of a single channel, from 0 to 2^Bits()-1.
+	Read(ch int) (uint16, error)
+
+	// Bits returns the resolution of the ADC, in bits.
+	Bits() int
+
+	// Channels returns the number of channels of the ADC.
+	Channels() int
+}
+
+// Volts converts a value read from the ADC to the voltage at the input, given
+// the reference voltage.
+func Volts(r Reader, v uint16, vref float64) float64 {
+	return float64(v) * vref / float64(uint32(1)<<uint(r.Bits()))
+}
+
+// ReadVolts returns the voltage at the input of a single channel, given the
+// reference voltage.
+func ReadVolts(r Reader, ch int, vref float64) (float64, error) {
+	v, err := r.Read(ch)
+	if err != nil {
+		return 0, err
+	}
+	return Volts(r, v, vref), nil
+}
+
+var (
+	// ErrInvalidChannel indicates the channel is not supported by the ADC.
+	ErrInvalidChannel = errors.New("invalid channel")
+
+	// ErrNoDevice indicates the ADC did not respond as expected, so is
+	// missing or miswired, or its data out line is stuck high or low.
+	ErrNoDevice = errors.New("no device")
+)
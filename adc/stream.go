@@ -0,0 +1,62 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package adc
+
+import (
+	"sync"
+	"time"
+)
+
+// Sample is a value read from an ADC channel.
+type Sample struct {
+	// The time the conversion started.
+	Time time.Time
+
+	// The value read.
+	Value uint16
+
+	// The error returned by the read, in which case Value is invalid.
+	Err error
+}
+
+// Stream reads the channel every period, and sends the samples to the
+// returned channel, which has capacity for buffer samples.
+//
+// The conversions are paced by a dedicated goroutine, and samples are
+// discarded if the channel is full.  The stop function stops the stream and
+// closes the channel, and may be called more than once.
+func Stream(r Reader, ch int, period time.Duration, buffer int) (<-chan Sample, func()) {
+	samples := make(chan Sample, buffer)
+	stopCh := make(chan struct{})
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		defer close(samples)
+		ticker := time.NewTicker(period)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s := Sample{Time: time.Now()}
+				s.Value, s.Err = r.Read(ch)
+				select {
+				case samples <- s:
+				default:
+				}
+			case <-stopCh:
+				return
+			}
+		}
+	}()
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			close(stopCh)
+			<-doneCh
+		})
+	}
+	return samples, stop
+}
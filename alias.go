@@ -0,0 +1,67 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package gpio
+
+import (
+	"sort"
+	"sync"
+)
+
+var (
+	// Guards aliases.
+	aliasLock sync.Mutex
+	// Map from name to BCM GPIO number.
+	aliases = make(map[string]int)
+)
+
+// Alias registers a name for a pin, so application code can refer to pins by
+// their role rather than their BCM GPIO number.
+//
+// Registering a name again replaces the previous pin, so a default mapping
+// can be overridden, such as by ApplyConfig.
+func Alias(name string, pin int) {
+	aliasLock.Lock()
+	aliases[name] = pin
+	aliasLock.Unlock()
+}
+
+// Unalias removes the name.
+func Unalias(name string) {
+	aliasLock.Lock()
+	delete(aliases, name)
+	aliasLock.Unlock()
+}
+
+// LookupAlias returns the BCM GPIO number of the named pin.
+func LookupAlias(name string) (int, bool) {
+	aliasLock.Lock()
+	defer aliasLock.Unlock()
+	pin, ok := aliases[name]
+	return pin, ok
+}
+
+// Aliases returns the registered names, in sorted order.
+func Aliases() []string {
+	aliasLock.Lock()
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	aliasLock.Unlock()
+	sort.Strings(names)
+	return names
+}
+
+// PinByName creates a new pin object for the named pin.
+//
+// Returns nil if the name is not registered or the pin is invalid.
+func PinByName(name string) *Pin {
+	pin, ok := LookupAlias(name)
+	if !ok {
+		return nil
+	}
+	return NewPin(pin)
+}
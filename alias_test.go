@@ -0,0 +1,52 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && hardware
+// +build linux,hardware
+
+// Test suite for alias module.
+//
+// Tests use J8 pins 7 and 15.
+package gpio_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warthog618/gpio"
+)
+
+func TestAlias(t *testing.T) {
+	setupDIO(t)
+	defer teardownDIO()
+	assert.Nil(t, gpio.PinByName("heater"))
+	gpio.Alias("heater", gpio.J8p7)
+	defer gpio.Unalias("heater")
+	pin, ok := gpio.LookupAlias("heater")
+	assert.True(t, ok)
+	assert.Equal(t, gpio.J8p7, pin)
+	p := gpio.PinByName("heater")
+	assert.NotNil(t, p)
+	assert.Equal(t, gpio.J8p7, p.Pin())
+	// override
+	gpio.Alias("heater", gpio.J8p15)
+	assert.Equal(t, gpio.J8p15, gpio.PinByName("heater").Pin())
+	assert.Contains(t, gpio.Aliases(), "heater")
+	gpio.Alias("broken", gpio.MaxGPIOPin)
+	assert.Nil(t, gpio.PinByName("broken"))
+	gpio.Unalias("broken")
+	assert.NotContains(t, gpio.Aliases(), "broken")
+}
+
+func TestApplyConfigAlias(t *testing.T) {
+	setupDIO(t)
+	defer teardownDIO()
+	gpio.Alias("button", gpio.J8p15)
+	defer gpio.Unalias("button")
+	cfg := &gpio.Config{Pins: []gpio.PinConfig{{Name: "button", Pin: gpio.J8p7}}}
+	_, err := gpio.ApplyConfig(cfg)
+	assert.Nil(t, err)
+	assert.Equal(t, gpio.J8p7, gpio.PinByName("button").Pin())
+}
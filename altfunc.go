@@ -0,0 +1,112 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Alternate function names.
+
+package gpio
+
+// AltFunc returns the name of the peripheral function selected by the mode on
+// the pin, e.g. "TXD0" for GPIO14 in Alt0.
+//
+// The names are those of the chipset identified by Open, or the BCM2835 if
+// the GPIO is not open.
+// Returns an empty string if the mode is not an alternate function, or the
+// function is reserved, or the pin is not one of GPIO0 to GPIO27.
+func AltFunc(pin int, mode Mode) string {
+	alt := altIndex(mode)
+	if alt < 0 || pin < 0 || pin >= len(bcm2835AltFuncs) {
+		return ""
+	}
+	if chipset == BCM2711 {
+		return bcm2711AltFuncs[pin][alt]
+	}
+	return bcm2835AltFuncs[pin][alt]
+}
+
+// altIndex returns the number of the alternate function for the mode, or -1
+// if the mode is not an alternate function.
+func altIndex(mode Mode) int {
+	switch mode {
+	case Alt0:
+		return 0
+	case Alt1:
+		return 1
+	case Alt2:
+		return 2
+	case Alt3:
+		return 3
+	case Alt4:
+		return 4
+	case Alt5:
+		return 5
+	}
+	return -1
+}
+
+// bcm2835AltFuncs are the BCM2835 alternate functions, Alt0 to Alt5, of
+// GPIO0 to GPIO27.
+var bcm2835AltFuncs = [28][6]string{
+	{"SDA0", "SA5"},
+	{"SCL0", "SA4"},
+	{"SDA1", "SA3"},
+	{"SCL1", "SA2"},
+	{"GPCLK0", "SA1", "", "", "", "ARM_TDI"},
+	{"GPCLK1", "SA0", "", "", "", "ARM_TDO"},
+	{"GPCLK2", "SOE_N", "", "", "", "ARM_RTCK"},
+	{"SPI0_CE1_N", "SWE_N"},
+	{"SPI0_CE0_N", "SD0"},
+	{"SPI0_MISO", "SD1"},
+	{"SPI0_MOSI", "SD2"},
+	{"SPI0_SCLK", "SD3"},
+	{"PWM0", "SD4", "", "", "", "ARM_TMS"},
+	{"PWM1", "SD5", "", "", "", "ARM_TCK"},
+	{"TXD0", "SD6", "", "", "", "TXD1"},
+	{"RXD0", "SD7", "", "", "", "RXD1"},
+	{"", "SD8", "", "CTS0", "SPI1_CE2_N", "CTS1"},
+	{"", "SD9", "", "RTS0", "SPI1_CE1_N", "RTS1"},
+	{"PCM_CLK", "SD10", "", "BSCSL_SDA", "SPI1_CE0_N", "PWM0"},
+	{"PCM_FS", "SD11", "", "BSCSL_SCL", "SPI1_MISO", "PWM1"},
+	{"PCM_DIN", "SD12", "", "BSCSL_MISO", "SPI1_MOSI", "GPCLK0"},
+	{"PCM_DOUT", "SD13", "", "BSCSL_CE", "SPI1_SCLK", "GPCLK1"},
+	{"", "SD14", "", "SD1_CLK", "ARM_TRST"},
+	{"", "SD15", "", "SD1_CMD", "ARM_RTCK"},
+	{"", "SD16", "", "SD1_DAT0", "ARM_TDO"},
+	{"", "SD17", "", "SD1_DAT1", "ARM_TCK"},
+	{"", "", "", "SD1_DAT2", "ARM_TDI"},
+	{"", "", "", "SD1_DAT3", "ARM_TMS"},
+}
+
+// bcm2711AltFuncs are the BCM2711 alternate functions, Alt0 to Alt5, of
+// GPIO0 to GPIO27.
+var bcm2711AltFuncs = [28][6]string{
+	{"SDA0", "SA5", "PCLK", "SPI3_CE0_N", "TXD2", "SDA6"},
+	{"SCL0", "SA4", "DE", "SPI3_MISO", "RXD2", "SCL6"},
+	{"SDA1", "SA3", "LCD_VSYNC", "SPI3_MOSI", "CTS2", "SDA3"},
+	{"SCL1", "SA2", "LCD_HSYNC", "SPI3_SCLK", "RTS2", "SCL3"},
+	{"GPCLK0", "SA1", "DPI_D0", "SPI4_CE0_N", "TXD3", "SDA3"},
+	{"GPCLK1", "SA0", "DPI_D1", "SPI4_MISO", "RXD3", "SCL3"},
+	{"GPCLK2", "SOE_N", "DPI_D2", "SPI4_MOSI", "CTS3", "SDA4"},
+	{"SPI0_CE1_N", "SWE_N", "DPI_D3", "SPI4_SCLK", "RTS3", "SCL4"},
+	{"SPI0_CE0_N", "SD0", "DPI_D4", "BSCSL_CE_N", "TXD4", "SDA4"},
+	{"SPI0_MISO", "SD1", "DPI_D5", "BSCSL_MISO", "RXD4", "SCL4"},
+	{"SPI0_MOSI", "SD2", "DPI_D6", "BSCSL_SDA", "CTS4", "SDA5"},
+	{"SPI0_SCLK", "SD3", "DPI_D7", "BSCSL_SCL", "RTS4", "SCL5"},
+	{"PWM0_0", "SD4", "DPI_D8", "SPI5_CE0_N", "TXD5", "SDA5"},
+	{"PWM0_1", "SD5", "DPI_D9", "SPI5_MISO", "RXD5", "SCL5"},
+	{"TXD0", "SD6", "DPI_D10", "SPI5_MOSI", "CTS5", "TXD1"},
+	{"RXD0", "SD7", "DPI_D11", "SPI5_SCLK", "RTS5", "RXD1"},
+	{"", "SD8", "DPI_D12", "CTS0", "SPI1_CE2_N", "CTS1"},
+	{"", "SD9", "DPI_D13", "RTS0", "SPI1_CE1_N", "RTS1"},
+	{"PCM_CLK", "SD10", "DPI_D14", "SPI6_CE0_N", "SPI1_CE0_N", "PWM0_0"},
+	{"PCM_FS", "SD11", "DPI_D15", "SPI6_MISO", "SPI1_MISO", "PWM0_1"},
+	{"PCM_DIN", "SD12", "DPI_D16", "SPI6_MOSI", "SPI1_MOSI", "GPCLK0"},
+	{"PCM_DOUT", "SD13", "DPI_D17", "SPI6_SCLK", "SPI1_SCLK", "GPCLK1"},
+	{"SD0_CLK", "SD14", "DPI_D18", "SD1_CLK", "ARM_TRST", "SDA6"},
+	{"SD0_CMD", "SD15", "DPI_D19", "SD1_CMD", "ARM_RTCK", "SCL6"},
+	{"SD0_DAT0", "SD16", "DPI_D20", "SD1_DAT0", "ARM_TDO", "SPI3_CE1_N"},
+	{"SD0_DAT1", "SD17", "DPI_D21", "SD1_DAT1", "ARM_TCK", "SPI4_CE1_N"},
+	{"SD0_DAT2", "TE0", "DPI_D22", "SD1_DAT2", "ARM_TDI", "SPI5_CE1_N"},
+	{"SD0_DAT3", "TE1", "DPI_D23", "SD1_DAT3", "ARM_TMS", "SPI6_CE1_N"},
+}
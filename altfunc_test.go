@@ -0,0 +1,43 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Test suite for altfunc module.
+//
+// Tests do not use any pins.
+package gpio
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAltFunc(t *testing.T) {
+	defer func(c Chipset) { chipset = c }(chipset)
+	patterns := []struct {
+		name     string
+		chip     Chipset
+		pin      int
+		mode     Mode
+		expected string
+	}{
+		{"txd0", BCM2835, GPIO14, Alt0, "TXD0"},
+		{"rxd1", BCM2835, GPIO15, Alt5, "RXD1"},
+		{"gpclk0", BCM2835, GPIO4, Alt0, "GPCLK0"},
+		{"reserved", BCM2835, GPIO14, Alt2, ""},
+		{"input", BCM2835, GPIO14, Input, ""},
+		{"output", BCM2835, GPIO14, Output, ""},
+		{"unknown chip", 0, GPIO14, Alt0, "TXD0"},
+		{"2711 pwm", BCM2711, GPIO12, Alt0, "PWM0_0"},
+		{"2711 txd2", BCM2711, 0, Alt4, "TXD2"},
+		{"2711 dpi", BCM2711, GPIO14, Alt2, "DPI_D10"},
+		{"negative pin", BCM2835, -1, Alt0, ""},
+		{"high pin", BCM2835, 28, Alt0, ""},
+	}
+	for _, p := range patterns {
+		chipset = p.chip
+		assert.Equal(t, p.expected, AltFunc(p.pin, p.mode), p.name)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Reading sets of input pins as integers, such as from DIP switches.
+
+package gpio
+
+import (
+	"errors"
+	"time"
+)
+
+// ReadBits returns the levels of the pins as an integer, with the first pin
+// being the least significant bit.
+//
+// The levels of the pins with the corresponding bit set in invert are
+// inverted, such as for switches that pull the pin low when on.
+func ReadBits(pins []*Pin, invert uint) uint {
+	g := PinGroup{pins: pins}
+	return (g.Read() ^ invert) & bitsMask(len(pins))
+}
+
+// WatchBits watches the pins in the default Watcher, and calls the handler
+// with their value, as per ReadBits, whenever it changes.
+//
+// The pins are debounced as a unit, as per Watcher.AddGroupWatch, and the
+// handler is called with the initial value when the watch is added.
+func WatchBits(pins []*Pin, invert uint, debounce time.Duration, handler func(uint)) (*GroupWatch, error) {
+	watcher, err := getDefaultWatcher()
+	if err != nil {
+		return nil, err
+	}
+	g := &PinGroup{pins: pins}
+	return watcher.addGroupWatch(g, invert&bitsMask(len(pins)), 0, 0, debounce, handler)
+}
+
+// DecodeBCD returns the value of a binary coded decimal, such as read from a
+// set of BCD thumbwheel switches, with one decimal digit in each 4 bits.
+//
+// Returns ErrInvalidBCD if any digit is greater than 9.
+func DecodeBCD(v uint) (uint, error) {
+	var n uint
+	scale := uint(1)
+	for ; v != 0; v >>= 4 {
+		d := v & 0xf
+		if d > 9 {
+			return 0, ErrInvalidBCD
+		}
+		n += d * scale
+		scale *= 10
+	}
+	return n, nil
+}
+
+// bitsMask returns the mask covering n bits.
+func bitsMask(n int) uint {
+	return 1<<uint(n) - 1
+}
+
+var (
+	// ErrInvalidBCD indicates a binary coded decimal digit greater than 9.
+	ErrInvalidBCD = errors.New("invalid BCD")
+)
@@ -0,0 +1,187 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Board identification.
+
+package gpio
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Header identifies the GPIO header on the board.
+type Header int
+
+const (
+	// HeaderNone indicates the board has no GPIO header, such as a Compute
+	// Module, where the GPIOs are on the module connector.
+	HeaderNone Header = iota
+
+	// Header26 indicates the 26-pin P1 header of the original Model A and B.
+	Header26
+
+	// Header40 indicates the 40-pin J8 header.
+	Header40
+)
+
+// BoardInfo describes the Raspberry Pi board.
+type BoardInfo struct {
+	// The revision code, as reported by the firmware.
+	Revision uint32
+
+	// The model, e.g. "3B+".
+	Model string
+
+	// The PCB revision, e.g. "1.2".
+	PCBRevision string
+
+	// The memory size in MB.
+	Memory int
+
+	// The manufacturer, e.g. "Sony UK".
+	Manufacturer string
+
+	// The processor, e.g. "BCM2837".
+	Processor string
+
+	// The GPIO header.
+	Header Header
+}
+
+// Board returns the description of the board, decoded from its revision code.
+//
+// The revision code is read from the device-tree, or from /proc/cpuinfo on
+// older kernels.
+func Board() (BoardInfo, error) {
+	rev, err := readRevision()
+	if err != nil {
+		return BoardInfo{}, err
+	}
+	return decodeRevision(rev)
+}
+
+func readRevision() (uint32, error) {
+	if b, err := os.ReadFile("/proc/device-tree/system/linux,revision"); err == nil && len(b) >= 4 {
+		return binary.BigEndian.Uint32(b), nil
+	}
+	f, err := os.Open("/proc/cpuinfo")
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		kv := strings.SplitN(s.Text(), ":", 2)
+		if len(kv) == 2 && strings.TrimSpace(kv[0]) == "Revision" {
+			rev, err := strconv.ParseUint(strings.TrimSpace(kv[1]), 16, 32)
+			if err != nil {
+				return 0, err
+			}
+			return uint32(rev), nil
+		}
+	}
+	return 0, ErrUnknownBoard
+}
+
+// decodeRevision decodes a new or old style revision code.
+func decodeRevision(rev uint32) (BoardInfo, error) {
+	if rev&(1<<23) == 0 {
+		// old style, ignoring the warranty bits.
+		b, ok := oldRevisions[rev&0xffff]
+		if !ok {
+			return BoardInfo{}, ErrUnknownBoard
+		}
+		b.Revision = rev
+		b.Processor = "BCM2835"
+		return b, nil
+	}
+	model, ok := boardTypes[(rev>>4)&0xff]
+	if !ok {
+		return BoardInfo{}, ErrUnknownBoard
+	}
+	b := BoardInfo{
+		Revision:     rev,
+		Model:        model,
+		PCBRevision:  fmt.Sprintf("1.%d", rev&0xf),
+		Memory:       256 << ((rev >> 20) & 0x7),
+		Manufacturer: manufacturers[(rev>>16)&0xf],
+		Processor:    processors[(rev>>12)&0xf],
+		Header:       Header40,
+	}
+	if strings.HasPrefix(model, "CM") {
+		b.Header = HeaderNone
+	}
+	return b, nil
+}
+
+var boardTypes = map[uint32]string{
+	0x00: "A",
+	0x01: "B",
+	0x02: "A+",
+	0x03: "B+",
+	0x04: "2B",
+	0x05: "Alpha",
+	0x06: "CM1",
+	0x08: "3B",
+	0x09: "Zero",
+	0x0a: "CM3",
+	0x0c: "Zero W",
+	0x0d: "3B+",
+	0x0e: "3A+",
+	0x10: "CM3+",
+	0x11: "4B",
+	0x12: "Zero 2 W",
+	0x13: "400",
+	0x14: "CM4",
+	0x15: "CM4S",
+}
+
+var manufacturers = map[uint32]string{
+	0: "Sony UK",
+	1: "Egoman",
+	2: "Embest",
+	3: "Sony Japan",
+	4: "Embest",
+	5: "Stadium",
+}
+
+var processors = map[uint32]string{
+	0: "BCM2835",
+	1: "BCM2836",
+	2: "BCM2837",
+	3: "BCM2711",
+	4: "BCM2712",
+}
+
+var oldRevisions = map[uint32]BoardInfo{
+	0x0002: {Model: "B", PCBRevision: "1.0", Memory: 256, Manufacturer: "Egoman", Header: Header26},
+	0x0003: {Model: "B", PCBRevision: "1.0", Memory: 256, Manufacturer: "Egoman", Header: Header26},
+	0x0004: {Model: "B", PCBRevision: "2.0", Memory: 256, Manufacturer: "Sony UK", Header: Header26},
+	0x0005: {Model: "B", PCBRevision: "2.0", Memory: 256, Manufacturer: "Qisda", Header: Header26},
+	0x0006: {Model: "B", PCBRevision: "2.0", Memory: 256, Manufacturer: "Egoman", Header: Header26},
+	0x0007: {Model: "A", PCBRevision: "2.0", Memory: 256, Manufacturer: "Egoman", Header: Header26},
+	0x0008: {Model: "A", PCBRevision: "2.0", Memory: 256, Manufacturer: "Sony UK", Header: Header26},
+	0x0009: {Model: "A", PCBRevision: "2.0", Memory: 256, Manufacturer: "Qisda", Header: Header26},
+	0x000d: {Model: "B", PCBRevision: "2.0", Memory: 512, Manufacturer: "Egoman", Header: Header26},
+	0x000e: {Model: "B", PCBRevision: "2.0", Memory: 512, Manufacturer: "Sony UK", Header: Header26},
+	0x000f: {Model: "B", PCBRevision: "2.0", Memory: 512, Manufacturer: "Egoman", Header: Header26},
+	0x0010: {Model: "B+", PCBRevision: "1.2", Memory: 512, Manufacturer: "Sony UK", Header: Header40},
+	0x0011: {Model: "CM1", PCBRevision: "1.0", Memory: 512, Manufacturer: "Sony UK", Header: HeaderNone},
+	0x0012: {Model: "A+", PCBRevision: "1.1", Memory: 256, Manufacturer: "Sony UK", Header: Header40},
+	0x0013: {Model: "B+", PCBRevision: "1.2", Memory: 512, Manufacturer: "Embest", Header: Header40},
+	0x0014: {Model: "CM1", PCBRevision: "1.0", Memory: 512, Manufacturer: "Embest", Header: HeaderNone},
+	0x0015: {Model: "A+", PCBRevision: "1.1", Memory: 256, Manufacturer: "Embest", Header: Header40},
+}
+
+var (
+	// ErrUnknownBoard indicates the board could not be identified.
+	ErrUnknownBoard = errors.New("unknown board")
+)
@@ -0,0 +1,40 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Test suite for board module.
+//
+// Tests do not use any pins.
+package gpio
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeRevision(t *testing.T) {
+	patterns := []struct {
+		rev      uint32
+		expected BoardInfo
+	}{
+		{0x000e, BoardInfo{0x000e, "B", "2.0", 512, "Sony UK", "BCM2835", Header26}},
+		{0x1000002, BoardInfo{0x1000002, "B", "1.0", 256, "Egoman", "BCM2835", Header26}},
+		{0x0014, BoardInfo{0x0014, "CM1", "1.0", 512, "Embest", "BCM2835", HeaderNone}},
+		{0x9000c1, BoardInfo{0x9000c1, "Zero W", "1.1", 512, "Sony UK", "BCM2835", Header40}},
+		{0xa02082, BoardInfo{0xa02082, "3B", "1.2", 1024, "Sony UK", "BCM2837", Header40}},
+		{0xa020d3, BoardInfo{0xa020d3, "3B+", "1.3", 1024, "Sony UK", "BCM2837", Header40}},
+		{0xc03111, BoardInfo{0xc03111, "4B", "1.1", 4096, "Sony UK", "BCM2711", Header40}},
+		{0xb03140, BoardInfo{0xb03140, "CM4", "1.0", 2048, "Sony UK", "BCM2711", HeaderNone}},
+	}
+	for _, p := range patterns {
+		b, err := decodeRevision(p.rev)
+		assert.Nil(t, err, "%x", p.rev)
+		assert.Equal(t, p.expected, b, "%x", p.rev)
+	}
+	_, err := decodeRevision(0x0001)
+	assert.Equal(t, ErrUnknownBoard, err)
+	_, err = decodeRevision(0x800ff0)
+	assert.Equal(t, ErrUnknownBoard, err)
+}
@@ -0,0 +1,141 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Identification of the kernel consumer holding a line.
+
+//go:build linux
+// +build linux
+
+package gpio
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// lineInfo mirrors the kernel struct gpioline_info.
+type lineInfo struct {
+	offset   uint32
+	flags    uint32
+	name     [32]byte
+	consumer [32]byte
+}
+
+// getLineInfoIoctl is GPIO_GET_LINEINFO_IOCTL, _IOWR(0xB4, 0x02, lineInfo).
+const getLineInfoIoctl = 3<<30 | uint(unsafe.Sizeof(lineInfo{}))<<16 | 0xB4<<8 | 0x02
+
+// lineConsumer returns the label of the kernel consumer of the pin, or an
+// empty string if it cannot be determined.
+//
+// The GPIO character device is tried first, as it does not require root, and
+// then debugfs.
+func lineConsumer(pin int) string {
+	if c := chardevConsumer(pin); c != "" {
+		return c
+	}
+	f, err := os.Open("/sys/kernel/debug/gpio")
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	return debugfsConsumer(f, pin)
+}
+
+// chardevConsumer returns the consumer of the pin from the line info of the
+// GPIO character device.
+func chardevConsumer(pin int) string {
+	f, err := os.Open("/dev/gpiochip0")
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	li := lineInfo{offset: uint32(pin)}
+	_, _, errno := unix.Syscall(unix.SYS_IOCTL, f.Fd(), uintptr(getLineInfoIoctl), uintptr(unsafe.Pointer(&li)))
+	if errno != 0 {
+		return ""
+	}
+	return cString(li.consumer[:])
+}
+
+// debugfsConsumer returns the consumer of the pin from the contents of
+// /sys/kernel/debug/gpio.
+//
+// The lines of the BCM GPIO chip are listed relative to the chip base, e.g.
+//
+//	gpiochip0: GPIOs 512-569, parent: platform/fe200000.gpio, pinctrl-bcm2711:
+//	 gpio-529 (GPIO17              |sysfs               ) in  hi IRQ
+//
+// while older kernels omit the line name.
+func debugfsConsumer(r io.Reader, pin int) string {
+	base := -1
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := s.Text()
+		if strings.HasPrefix(line, "gpiochip") {
+			base = -1
+			if strings.Contains(line, "pinctrl-bcm") {
+				base = chipBase(line)
+			}
+			continue
+		}
+		if base < 0 {
+			continue
+		}
+		f := strings.Fields(line)
+		if len(f) == 0 || !strings.HasPrefix(f[0], "gpio-") {
+			continue
+		}
+		n, err := strconv.Atoi(f[0][len("gpio-"):])
+		if err != nil || n-base != pin {
+			continue
+		}
+		open := strings.Index(line, "(")
+		end := strings.Index(line, ")")
+		if open < 0 || end < open {
+			return ""
+		}
+		// lines without a consumer only list the line name.
+		desc := line[open+1 : end]
+		bar := strings.Index(desc, "|")
+		if bar < 0 {
+			return ""
+		}
+		return strings.TrimSpace(desc[bar+1:])
+	}
+	return ""
+}
+
+// chipBase returns the first line number from a gpiochip header, such as
+// "gpiochip0: GPIOs 512-569, ...".
+func chipBase(line string) int {
+	i := strings.Index(line, "GPIOs ")
+	if i < 0 {
+		return -1
+	}
+	rng := line[i+len("GPIOs "):]
+	if dash := strings.Index(rng, "-"); dash >= 0 {
+		rng = rng[:dash]
+	}
+	base, err := strconv.Atoi(rng)
+	if err != nil {
+		return -1
+	}
+	return base
+}
+
+// cString returns the string in b, up to the first NUL.
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
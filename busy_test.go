@@ -0,0 +1,70 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build linux
+// +build linux
+
+// Test suite for busy module.
+//
+// Tests do not use any pins.
+package gpio
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const debugfsGPIO = `gpiochip1: GPIOs 504-511, parent: platform/soc:firmware:gpio, raspberrypi-exp-gpio, can sleep:
+ gpio-504 (BT_ON               )
+ gpio-509 (CAM_GPIO            |cam1_regulator      ) out lo
+gpiochip0: GPIOs 512-569, parent: platform/fe200000.gpio, pinctrl-bcm2711:
+ gpio-512 (ID_SDA              )
+ gpio-529 (GPIO17              |sysfs               ) in  hi IRQ
+ gpio-530 (GPIO18              |w1-gpio             ) in  hi
+`
+
+const debugfsGPIOOld = `gpiochip0: GPIOs 0-53, parent: platform/3f200000.gpio, pinctrl-bcm2835:
+ gpio-4   (                    |onewire@4           ) in  hi
+ gpio-47  (                    |led0                ) out lo
+`
+
+func TestDebugfsConsumer(t *testing.T) {
+	patterns := []struct {
+		name     string
+		contents string
+		pin      int
+		consumer string
+	}{
+		{"sysfs", debugfsGPIO, 17, "sysfs"},
+		{"driver", debugfsGPIO, 18, "w1-gpio"},
+		{"unused", debugfsGPIO, 0, ""},
+		{"unlisted", debugfsGPIO, 5, ""},
+		{"other chip", debugfsGPIO, -3, ""},
+		{"old", debugfsGPIOOld, 4, "onewire@4"},
+		{"old led", debugfsGPIOOld, 47, "led0"},
+		{"empty", "", 4, ""},
+	}
+	for _, p := range patterns {
+		tf := func(t *testing.T) {
+			assert.Equal(t, p.consumer, debugfsConsumer(strings.NewReader(p.contents), p.pin))
+		}
+		t.Run(p.name, tf)
+	}
+}
+
+func TestBusyError(t *testing.T) {
+	err := pinError("export", 18, &BusyError{Consumer: "w1-gpio"})
+	assert.True(t, errors.Is(err, ErrBusy))
+	assert.Equal(t, `export GPIO18: pin already in use by "w1-gpio"`, err.Error())
+	var be *BusyError
+	assert.True(t, errors.As(err, &be))
+	assert.Equal(t, "w1-gpio", be.Consumer)
+	err = &BusyError{}
+	assert.True(t, errors.Is(err, ErrBusy))
+	assert.Equal(t, "pin already in use", err.Error())
+}
@@ -0,0 +1,264 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package button provides a debounced push button that detects presses,
+// releases, long presses and double clicks.
+package button
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/warthog618/gpio"
+)
+
+// Button watches a pin connected to a push button and calls handlers as the
+// button is used.
+//
+// The handlers are passed the time of the gesture, and are called from a
+// goroutine of their own, so must not block for long.
+type Button struct {
+	pin         *gpio.Pin
+	active      gpio.Level
+	debounce    time.Duration
+	longPress   time.Duration
+	doubleClick time.Duration
+
+	onPressed     func(time.Time)
+	onReleased    func(time.Time)
+	onLongPress   func(time.Time)
+	onDoubleClick func(time.Time)
+
+	watch *gpio.Watch
+
+	// Guards the following.
+	mu            sync.Mutex
+	pressed       bool
+	long          bool
+	pressTime     time.Time
+	lastClick     time.Time
+	debounceTimer *time.Timer
+	longTimer     *time.Timer
+	closed        bool
+}
+
+// Option modifies the configuration of a Button.
+type Option func(*Button)
+
+// WithActiveLevel sets the level of the pin while the button is pressed.
+//
+// The default is Low, as for a button pulled up and shorted to ground.
+func WithActiveLevel(l gpio.Level) Option {
+	return func(b *Button) {
+		b.active = l
+	}
+}
+
+// WithDebounce sets the period the pin must remain at a level before the
+// button is considered pressed or released.
+//
+// The default is 20ms.
+func WithDebounce(d time.Duration) Option {
+	return func(b *Button) {
+		b.debounce = d
+	}
+}
+
+// WithLongPress sets the period the button must be held to be a long press.
+//
+// The default is 1s.
+func WithLongPress(d time.Duration) Option {
+	return func(b *Button) {
+		b.longPress = d
+	}
+}
+
+// WithDoubleClick sets the maximum period between the releases of two clicks
+// for them to be a double click.
+//
+// The default is 400ms.
+func WithDoubleClick(d time.Duration) Option {
+	return func(b *Button) {
+		b.doubleClick = d
+	}
+}
+
+// OnPressed sets the handler called when the button is pressed.
+func OnPressed(h func(time.Time)) Option {
+	return func(b *Button) {
+		b.onPressed = h
+	}
+}
+
+// OnReleased sets the handler called when the button is released.
+func OnReleased(h func(time.Time)) Option {
+	return func(b *Button) {
+		b.onReleased = h
+	}
+}
+
+// OnLongPress sets the handler called when the button has been held for the
+// long press period.
+//
+// The handler is called while the button is still held, and the release that
+// follows is not counted as a click.
+func OnLongPress(h func(time.Time)) Option {
+	return func(b *Button) {
+		b.onLongPress = h
+	}
+}
+
+// OnDoubleClick sets the handler called when the button is clicked twice
+// within the double click period.
+//
+// The pressed and released handlers are still called for both clicks.
+func OnDoubleClick(h func(time.Time)) Option {
+	return func(b *Button) {
+		b.onDoubleClick = h
+	}
+}
+
+// New creates a Button on the pin.
+//
+// The pin is set to an input, pulled to its inactive level, and is requested
+// until the Button is closed.
+// The GPIO must be open.
+func New(pin int, options ...Option) (*Button, error) {
+	b := &Button{
+		pin:         gpio.NewPin(pin),
+		active:      gpio.Low,
+		debounce:    20 * time.Millisecond,
+		longPress:   time.Second,
+		doubleClick: 400 * time.Millisecond,
+	}
+	if b.pin == nil {
+		return nil, ErrInvalidPin
+	}
+	if err := b.pin.Request("button"); err != nil {
+		return nil, err
+	}
+	for _, option := range options {
+		option(b)
+	}
+	b.pin.Input()
+	if b.active == gpio.Low {
+		b.pin.PullUp()
+	} else {
+		b.pin.PullDown()
+	}
+	b.pressed = b.pin.Read() == b.active
+	wt, err := b.pin.AddWatch(gpio.EdgeBoth, b.edgeHandler)
+	if err != nil {
+		b.pin.Release()
+		return nil, err
+	}
+	b.watch = wt
+	return b, nil
+}
+
+// Close removes the watch on the pin and stops any pending handlers.
+func (b *Button) Close() {
+	b.watch.Unwatch()
+	b.pin.Release()
+	b.mu.Lock()
+	b.closed = true
+	if b.debounceTimer != nil {
+		b.debounceTimer.Stop()
+	}
+	if b.longTimer != nil {
+		b.longTimer.Stop()
+	}
+	b.mu.Unlock()
+}
+
+// Pressed returns true if the button is currently pressed, after debouncing.
+func (b *Button) Pressed() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.pressed
+}
+
+// edgeHandler restarts the debounce period on any edge.
+//
+// The initial event from the watch is not distinguished from edges, as it
+// may be delivered after them, so it also restarts the debounce period, after
+// which settled compares the level with the current state.
+func (b *Button) edgeHandler(gpio.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	if b.debounceTimer == nil {
+		b.debounceTimer = time.AfterFunc(b.debounce, b.settled)
+		return
+	}
+	b.debounceTimer.Reset(b.debounce)
+}
+
+// settled updates the state of the button once the pin has been stable for
+// the debounce period.
+func (b *Button) settled() {
+	b.mu.Lock()
+	pressed := b.pin.Read() == b.active
+	if b.closed || pressed == b.pressed {
+		b.mu.Unlock()
+		return
+	}
+	now := time.Now()
+	b.pressed = pressed
+	var handlers []func(time.Time)
+	if pressed {
+		b.long = false
+		b.pressTime = now
+		if b.longTimer == nil {
+			b.longTimer = time.AfterFunc(b.longPress, b.held)
+		} else {
+			b.longTimer.Reset(b.longPress)
+		}
+		handlers = append(handlers, b.onPressed)
+	} else {
+		if b.longTimer != nil {
+			b.longTimer.Stop()
+		}
+		handlers = append(handlers, b.onReleased)
+		switch {
+		case b.long:
+			b.lastClick = time.Time{}
+		case !b.lastClick.IsZero() && now.Sub(b.lastClick) <= b.doubleClick:
+			b.lastClick = time.Time{}
+			handlers = append(handlers, b.onDoubleClick)
+		default:
+			b.lastClick = now
+		}
+	}
+	b.mu.Unlock()
+	for _, h := range handlers {
+		if h != nil {
+			h(now)
+		}
+	}
+}
+
+// held is called when the button has been pressed for the long press period.
+func (b *Button) held() {
+	b.mu.Lock()
+	if b.closed || !b.pressed || b.long || time.Since(b.pressTime) < b.longPress {
+		b.mu.Unlock()
+		return
+	}
+	b.long = true
+	h := b.onLongPress
+	b.mu.Unlock()
+	if h != nil {
+		h(time.Now())
+	}
+}
+
+var (
+	// ErrInvalidPin indicates the pin is not a valid GPIO pin.
+	ErrInvalidPin = errors.New("invalid pin")
+)
@@ -0,0 +1,267 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package capture records the level changes on a set of pins and encodes them
+// as Value Change Dump (VCD) or sigrok session files, so captures can be
+// inspected in waveform viewers such as GTKWave and PulseView.
+//
+// Changes may be recorded from a Watcher, using a Recorder, or converted from
+// the samples collected by the sampler package.
+// Captures written as VCD can be read back with ReadVCD, and replayed with
+// their original timing, to regression test application logic off-hardware.
+package capture
+
+import (
+	"archive/zip"
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/warthog618/gpio"
+)
+
+// Change is a change in the level of a pin.
+type Change struct {
+	// The time of the change, relative to the start of the capture.
+	Time time.Duration
+
+	// The pin that changed.
+	Pin int
+
+	// The level of the pin after the change.
+	Level gpio.Level
+}
+
+// Capture is the level changes on a set of pins over a period of time.
+type Capture struct {
+	// The pins captured.
+	Pins []int
+
+	// The levels of the pins at the start of the capture, with GPIO0 in the
+	// least significant bit.
+	Initial uint64
+
+	// The changes in level, ordered by time.
+	Changes []Change
+
+	// The length of the capture.
+	//
+	// If less than the time of the last change then the capture ends at the
+	// last change.
+	Length time.Duration
+}
+
+// end returns the time of the end of the capture.
+func (c *Capture) end() time.Duration {
+	end := c.Length
+	if n := len(c.Changes); n > 0 && c.Changes[n-1].Time > end {
+		end = c.Changes[n-1].Time
+	}
+	return end
+}
+
+// WriteVCD writes the capture to the writer in Value Change Dump format.
+func (c *Capture) WriteVCD(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "$timescale 1ns $end")
+	fmt.Fprintln(bw, "$scope module gpio $end")
+	ids := make(map[int]string, len(c.Pins))
+	for i, pin := range c.Pins {
+		ids[pin] = vcdID(i)
+		fmt.Fprintf(bw, "$var wire 1 %s GPIO%d $end\n", ids[pin], pin)
+	}
+	fmt.Fprintln(bw, "$upscope $end")
+	fmt.Fprintln(bw, "$enddefinitions $end")
+	fmt.Fprintln(bw, "#0")
+	fmt.Fprintln(bw, "$dumpvars")
+	for _, pin := range c.Pins {
+		fmt.Fprintf(bw, "%s%s\n", levelString(level(c.Initial, pin)), ids[pin])
+	}
+	fmt.Fprintln(bw, "$end")
+	levels := c.Initial
+	last := time.Duration(-1)
+	for _, chg := range c.Changes {
+		id, ok := ids[chg.Pin]
+		if !ok || level(levels, chg.Pin) == chg.Level {
+			continue
+		}
+		levels = setLevel(levels, chg.Pin, chg.Level)
+		if chg.Time != last {
+			fmt.Fprintf(bw, "#%d\n", int64(chg.Time))
+			last = chg.Time
+		}
+		fmt.Fprintf(bw, "%s%s\n", levelString(chg.Level), id)
+	}
+	if end := c.end(); end > last && end > 0 {
+		fmt.Fprintf(bw, "#%d\n", int64(end))
+	}
+	return bw.Flush()
+}
+
+// WriteSigrok writes the capture to the writer as a sigrok session file, as
+// read by PulseView and sigrok-cli.
+//
+// Session files contain regularly spaced samples, so the changes are resampled
+// at the given rate, in Hz.
+// Changes closer together than the sample period may be lost.
+func (c *Capture) WriteSigrok(w io.Writer, rate float64) error {
+	if rate <= 0 {
+		return ErrInvalidRate
+	}
+	if len(c.Pins) == 0 {
+		return ErrNoPins
+	}
+	period := time.Duration(float64(time.Second) / rate)
+	if period <= 0 {
+		return ErrInvalidRate
+	}
+	unitsize := (len(c.Pins) + 7) / 8
+	zw := zip.NewWriter(w)
+	f, err := zw.Create("version")
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(f, "2")
+	f, err = zw.Create("metadata")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(f, "[global]")
+	fmt.Fprintln(f, "sigrok version=0.5.2")
+	fmt.Fprintln(f)
+	fmt.Fprintln(f, "[device 1]")
+	fmt.Fprintln(f, "capturefile=logic-1")
+	fmt.Fprintf(f, "total probes=%d\n", len(c.Pins))
+	fmt.Fprintf(f, "samplerate=%d Hz\n", int64(rate))
+	fmt.Fprintln(f, "total analog=0")
+	for i, pin := range c.Pins {
+		fmt.Fprintf(f, "probe%d=GPIO%d\n", i+1, pin)
+	}
+	fmt.Fprintf(f, "unitsize=%d\n", unitsize)
+	f, err = zw.Create("logic-1-1")
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(f)
+	unit := make([]byte, unitsize)
+	levels := c.Initial
+	n := 0
+	end := c.end()
+	for t := time.Duration(0); t <= end; t += period {
+		for ; n < len(c.Changes) && c.Changes[n].Time <= t; n++ {
+			levels = setLevel(levels, c.Changes[n].Pin, c.Changes[n].Level)
+		}
+		for i := range unit {
+			unit[i] = 0
+		}
+		for i, pin := range c.Pins {
+			if level(levels, pin) {
+				unit[i/8] |= 1 << uint(i%8)
+			}
+		}
+		bw.Write(unit)
+	}
+	if err = bw.Flush(); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// Recorder records the level changes reported by a Watcher.
+//
+// The Handler is added as the handler for watches on the pins being recorded.
+type Recorder struct {
+	pins    []int
+	start   time.Time
+	initial uint64
+
+	// Guards changes.
+	mu      sync.Mutex
+	changes []Change
+}
+
+// NewRecorder creates a Recorder for the pins.
+//
+// The capture starts when the Recorder is created, with the initial levels
+// read from the pins at that time, so the GPIO must be open.
+func NewRecorder(pins []int) *Recorder {
+	initial := uint64(gpio.ReadBank(0)) | uint64(gpio.ReadBank(1))<<32
+	return &Recorder{
+		pins:    append([]int(nil), pins...),
+		start:   time.Now(),
+		initial: initial,
+	}
+}
+
+// Handler records the event.
+//
+// Events on pins not being recorded are ignored.
+func (r *Recorder) Handler(evt gpio.Event) {
+	pin := evt.Pin.Pin()
+	for _, p := range r.pins {
+		if p != pin {
+			continue
+		}
+		t := evt.Time.Sub(r.start)
+		if t < 0 {
+			t = 0
+		}
+		r.mu.Lock()
+		r.changes = append(r.changes, Change{Time: t, Pin: pin, Level: evt.Level})
+		r.mu.Unlock()
+		return
+	}
+}
+
+// Capture returns the changes recorded so far.
+func (r *Recorder) Capture() *Capture {
+	r.mu.Lock()
+	changes := append([]Change(nil), r.changes...)
+	r.mu.Unlock()
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Time < changes[j].Time
+	})
+	return &Capture{
+		Pins:    append([]int(nil), r.pins...),
+		Initial: r.initial,
+		Changes: changes,
+		Length:  time.Since(r.start),
+	}
+}
+
+// vcdID returns the VCD identifier for the nth pin.
+func vcdID(n int) string {
+	return string(rune('!' + n))
+}
+
+func level(levels uint64, pin int) gpio.Level {
+	return levels&(1<<uint(pin)) != 0
+}
+
+func setLevel(levels uint64, pin int, l gpio.Level) uint64 {
+	if l {
+		return levels | 1<<uint(pin)
+	}
+	return levels &^ (1 << uint(pin))
+}
+
+func levelString(l gpio.Level) string {
+	if l {
+		return "1"
+	}
+	return "0"
+}
+
+var (
+	// ErrInvalidRate indicates the sample rate is not positive.
+	ErrInvalidRate = errors.New("invalid sample rate")
+
+	// ErrNoPins indicates the capture contains no pins.
+	ErrNoPins = errors.New("no pins")
+)
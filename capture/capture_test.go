@@ -0,0 +1,139 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Test suite for capture module.
+//
+// Tests only encode and decode captures, so can be run on any machine.
+package capture_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/warthog618/gpio"
+	"github.com/warthog618/gpio/capture"
+)
+
+func TestVCD(t *testing.T) {
+	patterns := []struct {
+		name     string
+		c        capture.Capture
+		expected capture.Capture
+	}{
+		{"empty",
+			capture.Capture{Pins: []int{4}},
+			capture.Capture{Pins: []int{4}},
+		},
+		{"initial",
+			capture.Capture{Pins: []int{4, 17}, Initial: 1 << 17, Length: time.Millisecond},
+			capture.Capture{Pins: []int{4, 17}, Initial: 1 << 17, Length: time.Millisecond},
+		},
+		{"changes",
+			capture.Capture{
+				Pins:    []int{4, 17, 40},
+				Initial: 1<<4 | 1<<40,
+				Changes: []capture.Change{
+					{Time: 10 * time.Microsecond, Pin: 4, Level: gpio.Low},
+					{Time: 10 * time.Microsecond, Pin: 17, Level: gpio.High},
+					{Time: 25 * time.Microsecond, Pin: 40, Level: gpio.Low},
+				},
+				Length: time.Millisecond,
+			},
+			capture.Capture{
+				Pins:    []int{4, 17, 40},
+				Initial: 1<<4 | 1<<40,
+				Changes: []capture.Change{
+					{Time: 10 * time.Microsecond, Pin: 4, Level: gpio.Low},
+					{Time: 10 * time.Microsecond, Pin: 17, Level: gpio.High},
+					{Time: 25 * time.Microsecond, Pin: 40, Level: gpio.Low},
+				},
+				Length: time.Millisecond,
+			},
+		},
+		{"filtered",
+			capture.Capture{
+				Pins:    []int{4},
+				Initial: 1<<4 | 1<<5,
+				Changes: []capture.Change{
+					{Time: 10 * time.Microsecond, Pin: 4, Level: gpio.High},
+					{Time: 20 * time.Microsecond, Pin: 5, Level: gpio.Low},
+					{Time: 30 * time.Microsecond, Pin: 4, Level: gpio.Low},
+				},
+			},
+			capture.Capture{
+				Pins:    []int{4},
+				Initial: 1 << 4,
+				Changes: []capture.Change{
+					{Time: 30 * time.Microsecond, Pin: 4, Level: gpio.Low},
+				},
+				Length: 30 * time.Microsecond,
+			},
+		},
+	}
+	for _, p := range patterns {
+		t.Run(p.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.Nil(t, p.c.WriteVCD(&buf))
+			c, err := capture.ReadVCD(&buf)
+			require.Nil(t, err)
+			assert.Equal(t, &p.expected, c)
+		})
+	}
+}
+
+func TestReadVCD(t *testing.T) {
+	vcd := `
+$date today $end
+$timescale 10us $end
+$scope module top $end
+$var wire 1 ! GPIO4 $end
+$var wire 8 " bus $end
+$var wire 1 # clk $end
+$upscope $end
+$enddefinitions $end
+#0
+$dumpvars
+1!
+b0 "
+x#
+$end
+#3
+0!
+1#
+#5
+`
+	c, err := capture.ReadVCD(strings.NewReader(vcd))
+	require.Nil(t, err)
+	expected := &capture.Capture{
+		Pins:    []int{4},
+		Initial: 1 << 4,
+		Changes: []capture.Change{
+			{Time: 30 * time.Microsecond, Pin: 4, Level: gpio.Low},
+		},
+		Length: 50 * time.Microsecond,
+	}
+	assert.Equal(t, expected, c)
+
+	patterns := []struct {
+		name string
+		vcd  string
+	}{
+		{"timescale", "$timescale 1ps $end"},
+		{"unterminated", "$var wire 1 ! GPIO4"},
+		{"time", "#5 #3"},
+		{"timestamp", "#x"},
+	}
+	for _, p := range patterns {
+		t.Run(p.name, func(t *testing.T) {
+			c, err := capture.ReadVCD(strings.NewReader(p.vcd))
+			assert.Equal(t, capture.ErrInvalidVCD, err)
+			assert.Nil(t, c)
+		})
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package capture
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/warthog618/gpio"
+)
+
+// ReadVCD reads a capture from a Value Change Dump, such as one written by
+// WriteVCD, so a recording from a live session can be replayed later.
+//
+// Only single bit variables named after their pin, such as GPIO17, are read,
+// and unknown and high impedance values are ignored.
+// Values at time 0 are the initial levels, and the length is the time of the
+// last timestamp.
+func ReadVCD(r io.Reader) (*Capture, error) {
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<20)
+	s.Split(bufio.ScanWords)
+	c := &Capture{}
+	pins := map[string]int{}
+	scale := time.Nanosecond
+	var t time.Duration
+	for s.Scan() {
+		tok := s.Text()
+		switch {
+		case tok == "$timescale":
+			ts, err := section(s)
+			if err != nil {
+				return nil, err
+			}
+			if scale, err = timescale(strings.Join(ts, "")); err != nil {
+				return nil, err
+			}
+		case tok == "$var":
+			v, err := section(s)
+			if err != nil {
+				return nil, err
+			}
+			// type size id reference
+			if len(v) < 4 || v[1] != "1" || !strings.HasPrefix(v[3], "GPIO") {
+				continue
+			}
+			pin, err := strconv.Atoi(v[3][len("GPIO"):])
+			if err != nil || pin < 0 || pin >= 64 {
+				continue
+			}
+			pins[v[2]] = pin
+			c.Pins = append(c.Pins, pin)
+		case tok == "$dumpvars" || tok == "$dumpall" || tok == "$dumpon" ||
+			tok == "$dumpoff" || tok == "$end":
+			// the values within are read as value changes.
+		case strings.HasPrefix(tok, "$"):
+			if _, err := section(s); err != nil {
+				return nil, err
+			}
+		case tok[0] == '#':
+			n, err := strconv.ParseInt(tok[1:], 10, 64)
+			if err != nil || time.Duration(n)*scale < t {
+				return nil, ErrInvalidVCD
+			}
+			t = time.Duration(n) * scale
+			c.Length = t
+		case tok[0] == '0' || tok[0] == '1':
+			pin, ok := pins[tok[1:]]
+			if !ok {
+				continue
+			}
+			l := gpio.Level(tok[0] == '1')
+			if t == 0 {
+				c.Initial = setLevel(c.Initial, pin, l)
+				continue
+			}
+			c.Changes = append(c.Changes, Change{Time: t, Pin: pin, Level: l})
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	sort.Ints(c.Pins)
+	return c, nil
+}
+
+// Replay calls fn with each change in the capture, paced to occur at the same
+// time, relative to the start of the replay, as it did in the capture.
+//
+// This allows application logic to be regression tested against a recording
+// with realistic timing, without requiring the hardware.
+// Returns the error from the context if it is done before the replay
+// completes.
+func (c *Capture) Replay(ctx context.Context, fn func(Change)) error {
+	start := time.Now()
+	for _, chg := range c.Changes {
+		if d := chg.Time - time.Since(start); d > 0 {
+			timer := time.NewTimer(d)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			}
+		} else if err := ctx.Err(); err != nil {
+			return err
+		}
+		fn(chg)
+	}
+	return nil
+}
+
+// section returns the tokens up to the next $end.
+func section(s *bufio.Scanner) ([]string, error) {
+	var tt []string
+	for s.Scan() {
+		if s.Text() == "$end" {
+			return tt, nil
+		}
+		tt = append(tt, s.Text())
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return nil, ErrInvalidVCD
+}
+
+// timescale returns the duration of the timescale, such as "1ns" or "10us".
+func timescale(ts string) (time.Duration, error) {
+	i := strings.IndexFunc(ts, func(r rune) bool { return r < '0' || r > '9' })
+	if i <= 0 {
+		return 0, ErrInvalidVCD
+	}
+	n, err := strconv.Atoi(ts[:i])
+	if err != nil {
+		return 0, ErrInvalidVCD
+	}
+	units := map[string]time.Duration{
+		"ns": time.Nanosecond,
+		"us": time.Microsecond,
+		"ms": time.Millisecond,
+		"s":  time.Second,
+	}
+	unit, ok := units[ts[i:]]
+	if !ok {
+		// finer timescales cannot be represented.
+		return 0, ErrInvalidVCD
+	}
+	return time.Duration(n) * unit, nil
+}
+
+var (
+	// ErrInvalidVCD indicates the Value Change Dump is malformed or uses a
+	// timescale finer than a nanosecond.
+	ErrInvalidVCD = errors.New("invalid VCD")
+)
@@ -0,0 +1,68 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Description of the GPIO register block of each chipset.
+
+package gpio
+
+import (
+	"os"
+)
+
+// ChipInfo describes the GPIO register block of a chipset.
+type ChipInfo struct {
+	// The number of 32-bit registers in the block.
+	Registers int
+
+	// The offset of the block from the peripheral base.
+	Base int
+
+	// The number of GPIO lines controlled by the block.
+	NumPins int
+}
+
+var chips = map[Chipset]ChipInfo{
+	// up to the test register.
+	BCM2835: {Registers: 45, Base: gpioOffset, NumPins: 54},
+	// up to the last of the pull registers.
+	BCM2711: {Registers: 61, Base: gpioOffset, NumPins: 58},
+}
+
+// Info returns the description of the GPIO register block of the chipset.
+//
+// For an unknown chipset this covers the registers of all supported chipsets.
+func (c Chipset) Info() ChipInfo {
+	if ci, ok := chips[c]; ok {
+		return ci
+	}
+	return ChipInfo{Registers: minRegs, Base: gpioOffset, NumPins: 58}
+}
+
+// detectChip identifies the chipset from the board, before the registers are
+// mapped, or returns the unknown chipset if the board cannot be identified.
+func detectChip() Chipset {
+	b, err := Board()
+	if err != nil || b.Processor == "" {
+		return 0
+	}
+	if b.Processor == "BCM2711" {
+		return BCM2711
+	}
+	return BCM2835
+}
+
+// mapLength returns the length of the mapping required for the chipset, which
+// is the register block rounded up to a whole number of pages.
+//
+// The mapping always covers the registers of all supported chipsets, so that
+// the chipset can be identified from the registers if it is unknown.
+func mapLength(c Chipset) int {
+	n := c.Info().Registers * 4
+	if n < minRegs*4 {
+		n = minRegs * 4
+	}
+	page := os.Getpagesize()
+	return (n + page - 1) / page * page
+}
@@ -0,0 +1,133 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Cleanup of pins on Close.
+
+package gpio
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// OpenOption modifies the behaviour of Open.
+type OpenOption func(*openConfig)
+
+type openConfig struct {
+	revert bool
+	strict bool
+}
+
+// WithRevertOnClose records the mode and level of each pin when it is first
+// changed by SetMode or Write, and reverts the pin to that state on Close.
+//
+// The pull of the pin is not reverted, as it cannot be read back on the
+// BCM2835.
+func WithRevertOnClose() OpenOption {
+	return func(c *openConfig) {
+		c.revert = true
+	}
+}
+
+// WithStrictClose prevents the Close that releases the last reference to the
+// GPIO from removing active watches.
+//
+// Instead that Close returns ErrWatchesActive, and the GPIO remains open, while
+// the default Watcher is watching any pins.  The watches must be removed, or
+// the default Watcher closed with CloseWatcher, before the GPIO can be closed.
+// Watchers created by NewWatcher are not considered, and must be closed
+// before Close in any case.
+func WithStrictClose() OpenOption {
+	return func(c *openConfig) {
+		c.strict = true
+	}
+}
+
+// pinState is the state of a pin prior to it being changed.
+type pinState struct {
+	mode  Mode
+	level Level
+}
+
+var (
+	// cleanupLock covers cleanups and saved.
+	cleanupLock sync.Mutex
+	cleanups    []func()
+
+	// reverting is non-zero if pins are to be reverted on Close.
+	reverting int32
+
+	// strictClose is non-zero if Close fails while watches are active.
+	strictClose int32
+
+	// touched is a bitmap of the pins with a saved state.
+	touched uint64
+	saved   [MaxGPIOPin]pinState
+)
+
+// OnClose registers a function to be called by the Close that releases the
+// last reference to the GPIO.
+//
+// The functions are called in the reverse order of registration, while the
+// GPIO is still open, so they may change the state of pins.
+// The functions are cleared by Close.
+func OnClose(fn func()) {
+	cleanupLock.Lock()
+	cleanups = append(cleanups, fn)
+	cleanupLock.Unlock()
+}
+
+// Cleanup registers a function to be called with the pin by Close, e.g. to
+// revert the pin to an input or drive it to a safe level.
+//
+// This is equivalent to OnClose with a function that calls fn(pin).
+func (pin *Pin) Cleanup(fn func(*Pin)) {
+	OnClose(func() { fn(pin) })
+}
+
+// touch saves the state of the pin the first time it is changed, if
+// reverting is enabled.
+func (pin *Pin) touch() {
+	bit := uint64(1) << uint(pin.pin)
+	if atomic.LoadInt32(&reverting) == 0 || atomic.LoadUint64(&touched)&bit != 0 {
+		return
+	}
+	cleanupLock.Lock()
+	defer cleanupLock.Unlock()
+	t := atomic.LoadUint64(&touched)
+	if t&bit != 0 {
+		return
+	}
+	saved[pin.pin] = pinState{mode: pin.Mode(), level: pin.level()}
+	atomic.StoreUint64(&touched, t|bit)
+}
+
+// runCleanups calls the registered cleanup functions, then reverts any
+// touched pins, and clears both.
+func runCleanups() {
+	cleanupLock.Lock()
+	cc := cleanups
+	cleanups = nil
+	cleanupLock.Unlock()
+	for i := len(cc) - 1; i >= 0; i-- {
+		cc[i]()
+	}
+	atomic.StoreInt32(&reverting, 0)
+	cleanupLock.Lock()
+	defer cleanupLock.Unlock()
+	t := atomic.LoadUint64(&touched)
+	atomic.StoreUint64(&touched, 0)
+	for i := 0; i < MaxGPIOPin; i++ {
+		if t&(1<<uint(i)) == 0 {
+			continue
+		}
+		pin := newPin(i)
+		s := saved[i]
+		if s.mode == Output {
+			pin.Write(s.level)
+		}
+		pin.SetMode(s.mode)
+	}
+}
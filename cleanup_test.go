@@ -0,0 +1,74 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && hardware
+// +build linux,hardware
+
+// Test suite for cleanup module.
+//
+// Tests use J8 pin 7.
+package gpio_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warthog618/gpio"
+)
+
+func TestOnClose(t *testing.T) {
+	setupDIO(t)
+	order := []int(nil)
+	gpio.OnClose(func() { order = append(order, 1) })
+	gpio.OnClose(func() { order = append(order, 2) })
+	teardownDIO()
+	assert.Equal(t, []int{2, 1}, order)
+
+	// cleared by Close
+	setupDIO(t)
+	teardownDIO()
+	assert.Equal(t, []int{2, 1}, order)
+}
+
+func TestPinCleanup(t *testing.T) {
+	setupDIO(t)
+	pin := gpio.NewPin(gpio.J8p7)
+	assert.Equal(t, gpio.Input, pin.Mode())
+	pin.Low()
+	pin.Output()
+	var cleaned *gpio.Pin
+	pin.Cleanup(func(p *gpio.Pin) {
+		cleaned = p
+		p.Input()
+	})
+	teardownDIO()
+	assert.Equal(t, pin, cleaned)
+
+	setupDIO(t)
+	defer teardownDIO()
+	assert.Equal(t, gpio.Input, gpio.NewPin(gpio.J8p7).Mode())
+}
+
+func TestRevertOnClose(t *testing.T) {
+	assert.Nil(t, gpio.Open(gpio.WithRevertOnClose()))
+	pin := gpio.NewPin(gpio.J8p7)
+	assert.Equal(t, gpio.Input, pin.Mode())
+	pin.Low()
+	pin.Output()
+	assert.Equal(t, gpio.Low, pin.Read())
+	teardownDIO()
+
+	setupDIO(t)
+	defer teardownDIO()
+	pin = gpio.NewPin(gpio.J8p7)
+	assert.Equal(t, gpio.Input, pin.Mode())
+	// not reverted if not opened WithRevertOnClose
+	pin.Output()
+	teardownDIO()
+	setupDIO(t)
+	pin = gpio.NewPin(gpio.J8p7)
+	assert.Equal(t, gpio.Output, pin.Mode())
+	pin.Input()
+}
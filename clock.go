@@ -0,0 +1,215 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// General purpose clock outputs.
+
+package gpio
+
+import (
+	"errors"
+	"time"
+
+	"github.com/warthog618/gpio/internal/periph"
+)
+
+// Clock manager registers and bits.
+const (
+	cmOffset  = 0x101000
+	cmGP0CTL  = 0x70 / 4
+	cmPCMCTL  = 0x98 / 4
+	cmPasswd  = 0x5a << 24
+	cmEnable  = 1 << 4
+	cmKill    = 1 << 5
+	cmBusy    = 1 << 7
+	cmMash1   = 1 << 9
+	cmSrcOsc  = 1
+	cmSrcPLLD = 6
+	cmMaxDivi = 0xfff
+)
+
+// clockPin identifies the general purpose clock available on a pin, and the
+// mode that connects it.
+type clockPin struct {
+	clock int
+	mode  Mode
+}
+
+var clockPins = map[int]clockPin{
+	GPIO4:  {0, Alt0},
+	GPIO5:  {1, Alt0},
+	GPIO6:  {2, Alt0},
+	GPIO20: {0, Alt5},
+	GPIO21: {1, Alt5},
+	32:     {0, Alt0},
+	34:     {0, Alt0},
+	42:     {1, Alt0},
+	43:     {2, Alt0},
+	44:     {1, Alt0},
+}
+
+// Clock is a general purpose clock output on a pin.
+//
+// The clock is generated by the clock manager, which is accessed via
+// /dev/mem, so root privileges are required.
+type Clock struct {
+	pin  *Pin
+	mode Mode
+	cm   *periph.Block
+	// the index of the clock control register, with the divisor following.
+	ctl  int
+	freq uint
+}
+
+// NewClock starts a clock of the requested frequency, in Hz, on the pin.
+//
+// The pin must be one connected to a general purpose clock, such as GPIO4,
+// GPIO5 or GPIO6.  The frequency is generated by dividing a fixed clock
+// source, so the actual frequency may differ from that requested and is
+// returned by Frequency.
+// Returns ErrProtected if the pin is protected.
+func NewClock(pin int, freq uint) (*Clock, error) {
+	cp, ok := clockPins[pin]
+	if !ok {
+		return nil, pinError("clock", pin, ErrInvalidClockPin)
+	}
+	if Protected(pin) {
+		return nil, pinError("clock", pin, ErrProtected)
+	}
+	if _, _, err := clockDivisor(freq); err != nil {
+		return nil, err
+	}
+	p := newPin(pin)
+	if p == nil {
+		return nil, pinError("clock", pin, ErrInvalidClockPin)
+	}
+	cm, err := periph.Map(cmOffset, 4096)
+	if err != nil {
+		return nil, err
+	}
+	c := &Clock{
+		pin:  p,
+		mode: p.Mode(),
+		cm:   cm,
+		ctl:  cmGP0CTL + 2*cp.clock,
+	}
+	if err = c.SetFrequency(freq); err != nil {
+		cm.Close()
+		return nil, err
+	}
+	p.SetMode(cp.mode)
+	return c, nil
+}
+
+// clockSources returns the frequencies of the oscillator and PLLD.
+func clockSources() (osc, plld uint) {
+	if chipset == BCM2711 {
+		return 54000000, 750000000
+	}
+	return 19200000, 500000000
+}
+
+// clockDivisor returns the source and divisor that best provide the
+// frequency.
+//
+// The oscillator is preferred, as it is more stable, unless the frequency is
+// too high for it.
+func clockDivisor(freq uint) (src, div uint32, err error) {
+	if freq == 0 {
+		return 0, 0, ErrInvalidFrequency
+	}
+	osc, plld := clockSources()
+	for _, s := range []struct {
+		src  uint32
+		freq uint
+	}{{cmSrcOsc, osc}, {cmSrcPLLD, plld}} {
+		divi := s.freq / freq
+		if divi < 2 || divi > cmMaxDivi {
+			continue
+		}
+		// computed in 64 bits, as the product overflows a 32 bit uint.
+		divf := uint(uint64(s.freq%freq) * 4096 / uint64(freq))
+		return s.src, uint32(divi<<12 | divf), nil
+	}
+	return 0, 0, ErrInvalidFrequency
+}
+
+// clockFrequency returns the frequency generated by the source and divisor.
+func clockFrequency(src, div uint32) uint {
+	osc, plld := clockSources()
+	srcFreq := osc
+	if src == cmSrcPLLD {
+		srcFreq = plld
+	}
+	return uint(uint64(srcFreq) * 4096 / uint64(div))
+}
+
+// SetFrequency changes the frequency of the clock.
+func (c *Clock) SetFrequency(freq uint) error {
+	src, div, err := clockDivisor(freq)
+	if err != nil {
+		return err
+	}
+	c.stop()
+	cmStart(c.cm.Regs, c.ctl, src, div)
+	c.freq = clockFrequency(src, div)
+	return nil
+}
+
+// Frequency returns the actual frequency of the clock, in Hz.
+func (c *Clock) Frequency() uint {
+	return c.freq
+}
+
+// stop disables the clock and waits for it to stop.
+func (c *Clock) stop() {
+	cmStop(c.cm.Regs, c.ctl)
+}
+
+// cmStart starts the clock manager clock with the control register at ctl,
+// and the divisor following, from the source and divisor.
+//
+// The clock must be stopped.
+func cmStart(regs []uint32, ctl int, src, div uint32) {
+	regs[ctl+1] = cmPasswd | div
+	c := uint32(cmPasswd | src)
+	if div&0xfff != 0 {
+		c |= cmMash1
+	}
+	regs[ctl] = c
+	regs[ctl] = c | cmEnable
+}
+
+// cmStop disables the clock manager clock with the control register at ctl,
+// and waits for it to stop.
+func cmStop(regs []uint32, ctl int) {
+	regs[ctl] = cmPasswd | regs[ctl]&^(cmEnable|0xff000000)
+	for i := 0; regs[ctl]&cmBusy != 0; i++ {
+		if i > 100 {
+			regs[ctl] = cmPasswd | cmKill
+			break
+		}
+		time.Sleep(10 * time.Microsecond)
+	}
+}
+
+// Close stops the clock and restores the pin to its previous mode.
+func (c *Clock) Close() error {
+	if c.cm == nil {
+		return nil
+	}
+	c.stop()
+	c.pin.SetMode(c.mode)
+	err := c.cm.Close()
+	c.cm = nil
+	return err
+}
+
+var (
+	// ErrInvalidClockPin indicates the pin does not support a clock output.
+	ErrInvalidClockPin = errors.New("pin does not support a clock")
+
+	// ErrInvalidFrequency indicates the frequency cannot be generated.
+	ErrInvalidFrequency = errors.New("invalid frequency")
+)
@@ -0,0 +1,53 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && hardware
+// +build linux,hardware
+
+// Test suite for clock module.
+//
+// Tests use J8 pin 7, and require root privileges to access /dev/mem.
+package gpio_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warthog618/gpio"
+)
+
+func TestNewClockInvalid(t *testing.T) {
+	setupDIO(t)
+	defer teardownDIO()
+	c, err := gpio.NewClock(gpio.J8p15, 100000)
+	assert.Nil(t, c)
+	assert.ErrorIs(t, err, gpio.ErrInvalidClockPin)
+	c, err = gpio.NewClock(gpio.J8p7, 0)
+	assert.Nil(t, c)
+	assert.Equal(t, gpio.ErrInvalidFrequency, err)
+	c, err = gpio.NewClock(gpio.J8p7, 1000)
+	assert.Nil(t, c)
+	assert.Equal(t, gpio.ErrInvalidFrequency, err)
+}
+
+func TestClock(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("requires root")
+	}
+	setupDIO(t)
+	defer teardownDIO()
+	pin := gpio.NewPin(gpio.J8p7)
+	mode := pin.Mode()
+	c, err := gpio.NewClock(gpio.J8p7, 32768)
+	assert.Nil(t, err)
+	assert.Equal(t, gpio.Alt0, pin.Mode())
+	assert.InDelta(t, 32768, c.Frequency(), 1)
+	assert.Nil(t, c.SetFrequency(25000000))
+	assert.Equal(t, uint(25000000), c.Frequency())
+	assert.Equal(t, gpio.ErrInvalidFrequency, c.SetFrequency(1000))
+	assert.Nil(t, c.Close())
+	assert.Equal(t, mode, pin.Mode())
+}
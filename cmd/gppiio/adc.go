@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright © 2019 Kent Gibson <warthog618@gmail.com>.
+
+// +build linux
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/warthog618/gpio"
+	adcpkg "github.com/warthog618/gpio/adc"
+	"github.com/warthog618/gpio/spi"
+	"github.com/warthog618/gpio/spi/adc0832"
+	"github.com/warthog618/gpio/spi/mcp3w0c"
+)
+
+func init() {
+	adcCmd.Flags().StringVarP(&adcOpts.Driver, "driver", "d", "mcp3008", "the ADC driver [adc0832|mcp3004|mcp3008|mcp3204|mcp3208]")
+	adcCmd.Flags().StringVarP(&adcOpts.Clk, "clk", "", "J8p40", "the clock pin")
+	adcCmd.Flags().StringVarP(&adcOpts.Csz, "csz", "", "J8p31", "the chip select pin")
+	adcCmd.Flags().StringVarP(&adcOpts.Di, "di", "", "J8p35", "the data in pin, from the Pi to the ADC")
+	adcCmd.Flags().StringVarP(&adcOpts.Do, "do", "", "J8p37", "the data out pin, from the ADC to the Pi")
+	adcCmd.Flags().DurationVarP(&adcOpts.Tclk, "tclk", "", 500*time.Nanosecond, "the time between clock edges")
+	adcCmd.Flags().DurationVarP(&adcOpts.Tset, "tset", "", 2500*time.Nanosecond, "the mux settling time (adc0832 only)")
+	adcCmd.Flags().StringVarP(&adcOpts.Delay, "delay", "", "busy", "the delay between clock edges [busy|sleep|none]")
+	adcCmd.Flags().BoolVarP(&adcOpts.Differential, "differential", "D", false, "read the differential pair rather than the single channel")
+	adcCmd.SetHelpTemplate(adcCmd.HelpTemplate() + extendedAdcHelp)
+	rootCmd.AddCommand(adcCmd)
+}
+
+var extendedAdcHelp = `
+Pins:
+  Pins may be identified by name (J8pXX) or number (0-26).
+  The di and do pins may be the same pin, if they are tied.
+
+Note that reading the ADC forces all the pins other than do into output
+mode, so do not use on pins that serve other purposes.
+`
+
+var (
+	adcCmd = &cobra.Command{
+		Use:     "adc <channel1>...",
+		Short:   "Read the value of an ADC channel or channels",
+		Args:    cobra.MinimumNArgs(1),
+		RunE:    adc,
+		Example: "  gppiio adc --driver mcp3008 --clk J8p40 --csz J8p31 0 1",
+	}
+	adcOpts = struct {
+		Driver       string
+		Clk          string
+		Csz          string
+		Di           string
+		Do           string
+		Tclk         time.Duration
+		Tset         time.Duration
+		Delay        string
+		Differential bool
+	}{}
+)
+
+// adcDriver reads a channel from an ADC.
+type adcDriver interface {
+	adcpkg.Reader
+	ReadDifferential(ch int) (uint16, error)
+	Close()
+}
+
+// adcDelays are the delay functions selectable by --delay.
+var adcDelays = map[string]func(time.Duration){
+	"busy":  gpio.Delay,
+	"sleep": time.Sleep,
+	"none":  spi.NoDelay,
+}
+
+// adcChannels is the number of channels of each driver.
+var adcChannels = map[string]int{
+	"adc0832": 2,
+	"mcp3004": 4,
+	"mcp3008": 8,
+	"mcp3204": 4,
+	"mcp3208": 8,
+}
+
+func adc(cmd *cobra.Command, args []string) error {
+	driver := strings.ToLower(adcOpts.Driver)
+	channels, ok := adcChannels[driver]
+	if !ok {
+		return fmt.Errorf("unknown driver '%s'", adcOpts.Driver)
+	}
+	delay, ok := adcDelays[strings.ToLower(adcOpts.Delay)]
+	if !ok {
+		return fmt.Errorf("unknown delay '%s'", adcOpts.Delay)
+	}
+	opt := spi.WithDelay(delay)
+	cc := []int(nil)
+	for _, arg := range args {
+		c, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil || c >= uint64(channels) {
+			return fmt.Errorf("invalid channel '%s'", arg)
+		}
+		cc = append(cc, int(c))
+	}
+	pp, err := parseOffsets([]string{adcOpts.Clk, adcOpts.Csz, adcOpts.Di, adcOpts.Do})
+	if err != nil {
+		return err
+	}
+	err = gpio.Open()
+	if err != nil {
+		return err
+	}
+	defer gpio.Close()
+	clk, csz, di, do := pp[0], pp[1], pp[2], pp[3]
+	var a adcDriver
+	switch driver {
+	case "adc0832":
+		tset := adcOpts.Tset
+		if tset < adcOpts.Tclk {
+			tset = adcOpts.Tclk
+		}
+		a = adc0832.New(adcOpts.Tclk, tset, clk, csz, di, do, opt)
+	case "mcp3004":
+		a = mcp3w0c.NewMCP3004(adcOpts.Tclk, clk, csz, di, do, opt)
+	case "mcp3008":
+		a = mcp3w0c.NewMCP3008(adcOpts.Tclk, clk, csz, di, do, opt)
+	case "mcp3204":
+		a = mcp3w0c.NewMCP3204(adcOpts.Tclk, clk, csz, di, do, opt)
+	default:
+		a = mcp3w0c.NewMCP3208(adcOpts.Tclk, clk, csz, di, do, opt)
+	}
+	defer a.Close()
+	for _, c := range cc {
+		var v uint16
+		if adcOpts.Differential {
+			v, err = a.ReadDifferential(c)
+		} else {
+			v, err = a.Read(c)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("ch%d: %d\n", c, v)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+
+// +build linux
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/warthog618/gpio"
+)
+
+func init() {
+	benchCmd.Flags().BoolVarP(&benchOpts.Loop, "loop", "l", false, "also test a pair of jumpered pins")
+	benchCmd.Flags().DurationVarP(&benchOpts.Period, "period", "p", time.Second, "the period of each rate measurement")
+	benchCmd.Flags().IntVarP(&benchOpts.Edges, "num-edges", "n", 1000, "the number of edges for the latency measurement")
+	benchCmd.SetHelpTemplate(benchCmd.HelpTemplate() + extendedBenchHelp)
+	rootCmd.AddCommand(benchCmd)
+}
+
+var extendedBenchHelp = `
+Pins:
+  Pins may be identified by name (J8pXX) or number (0-26).
+
+The write rate is measured by toggling the first pin, and the read rate by
+reading it.  The rates are measured by busy looping, so are the best case
+for a single thread.
+
+With --loop, the first pin drives the second, which must be connected to it
+by a jumper, such as across J8 pins 15 and 16.  The connection is checked by
+reading back both levels, and the interrupt latency is measured by driving
+edges through it.  The command fails if the connection check fails.
+
+Note that the first pin is forced into output mode, and is left low.
+`
+
+var (
+	benchCmd = &cobra.Command{
+		Use:     "bench <pin> [<loop pin>]",
+		Short:   "Measure the performance of the GPIO",
+		PreRunE: prebench,
+		RunE:    bench,
+		Example: "  gppiio bench J8p7\n  gppiio bench --loop J8p15 J8p16",
+	}
+	benchOpts = struct {
+		Loop   bool
+		Period time.Duration
+		Edges  int
+	}{}
+)
+
+func prebench(cmd *cobra.Command, args []string) error {
+	if benchOpts.Loop {
+		return cobra.ExactArgs(2)(cmd, args)
+	}
+	return cobra.ExactArgs(1)(cmd, args)
+}
+
+func bench(cmd *cobra.Command, args []string) error {
+	if benchOpts.Period <= 0 {
+		return errors.New("period must be positive")
+	}
+	oo, err := parseOffsets(args)
+	if err != nil {
+		return err
+	}
+	if benchOpts.Loop && oo[0] == oo[1] {
+		return errors.New("loop pins must differ")
+	}
+	err = gpio.Open()
+	if err != nil {
+		return err
+	}
+	defer gpio.Close()
+	if err = checkProtected(oo[0]); err != nil {
+		return err
+	}
+	if b, err := gpio.Board(); err == nil {
+		fmt.Printf("board:         Pi %s rev %s, %dMB\n", b.Model, b.PCBRevision, b.Memory)
+	}
+	fmt.Printf("chip:          %s\n", chipName(gpio.Chip()))
+	pinOut := gpio.NewPin(oo[0])
+	pinOut.Low()
+	pinOut.Output()
+	defer pinOut.Low()
+	runtime.LockOSThread()
+	writes := benchRate(pinOut.Toggle)
+	reads := benchRate(func() { pinOut.Read() })
+	runtime.UnlockOSThread()
+	fmt.Printf("write rate:    %.3f MHz (%.3f MHz toggle)\n", writes/1e6, writes/2e6)
+	fmt.Printf("read rate:     %.3f MHz\n", reads/1e6)
+	if !benchOpts.Loop {
+		return nil
+	}
+	pinIn := gpio.NewPin(oo[1])
+	pinIn.Input()
+	if !benchLoop(pinOut, pinIn) {
+		fmt.Println("loop:          FAIL")
+		return fmt.Errorf("pin %d does not follow pin %d - check the jumper", oo[1], oo[0])
+	}
+	fmt.Println("loop:          ok")
+	stats, err := gpio.MeasureInterruptLatency(pinIn, pinOut, benchOpts.Edges)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("latency:       min %s, median %s, mean %s, p99 %s, max %s\n",
+		stats.Min, stats.Median, stats.Mean, stats.P99, stats.Max)
+	fmt.Printf("missed edges:  %d of %d\n", stats.Missed, stats.Count+stats.Missed)
+	return nil
+}
+
+// benchRate returns the rate, in calls per second, that fn can be called in
+// a busy loop over the benchmark period.
+func benchRate(fn func()) float64 {
+	n := 0
+	start := time.Now()
+	var elapsed time.Duration
+	for elapsed < benchOpts.Period {
+		for i := 0; i < 1024; i++ {
+			fn()
+		}
+		n += 1024
+		elapsed = time.Since(start)
+	}
+	return float64(n) / elapsed.Seconds()
+}
+
+// benchLoop returns true if pinIn follows both levels written to pinOut.
+func benchLoop(pinOut, pinIn *gpio.Pin) bool {
+	for _, l := range []gpio.Level{gpio.High, gpio.Low} {
+		pinOut.Write(l)
+		time.Sleep(time.Millisecond)
+		if pinIn.Read() != l {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright © 2019 Kent Gibson <warthog618@gmail.com>.
+
+// +build linux
+
+package main
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/warthog618/gpio"
+)
+
+func init() {
+	blinkCmd.Flags().BoolVarP(&blinkOpts.ActiveLow, "active-low", "l", false, "treat the line level as active low")
+	blinkCmd.Flags().DurationVarP(&blinkOpts.Period, "period", "p", 500*time.Millisecond, "the period of one blink")
+	blinkCmd.Flags().UintVarP(&blinkOpts.Count, "count", "c", 0, "exit after n blinks")
+	blinkCmd.SetHelpTemplate(blinkCmd.HelpTemplate() + extendedBlinkHelp)
+	rootCmd.AddCommand(blinkCmd)
+}
+
+var extendedBlinkHelp = `
+Pins:
+  Pins may be identified by name (J8pXX) or number (0-26).
+
+The pin is active for the first half of each period and inactive for the
+second.  By default the pin blinks until interrupted, and is left inactive
+on exit.
+
+Note that blinking a pin forces it into output mode.
+`
+
+var (
+	blinkCmd = &cobra.Command{
+		Use:     "blink <pin>",
+		Short:   "Toggle the level of a pin",
+		Args:    cobra.ExactArgs(1),
+		RunE:    blink,
+		Example: "  gppiio blink J8p7 --period 1s --count 5",
+	}
+	blinkOpts = struct {
+		ActiveLow bool
+		Period    time.Duration
+		Count     uint
+	}{}
+)
+
+func blink(cmd *cobra.Command, args []string) error {
+	if blinkOpts.Period/2 <= 0 {
+		return errors.New("period too short")
+	}
+	o, err := parseOffset(args[0])
+	if err != nil {
+		return err
+	}
+	err = gpio.Open()
+	if err != nil {
+		return err
+	}
+	defer gpio.Close()
+	if err = checkProtected(o); err != nil {
+		return err
+	}
+	active := gpio.High
+	if blinkOpts.ActiveLow {
+		active = gpio.Low
+	}
+	pin := gpio.NewPin(o)
+	pin.Write(!active)
+	pin.Output()
+	defer pin.Write(!active)
+	sigdone := make(chan os.Signal, 1)
+	signal.Notify(sigdone, os.Interrupt, os.Kill)
+	defer signal.Stop(sigdone)
+	half := time.NewTicker(blinkOpts.Period / 2)
+	defer half.Stop()
+	pin.Write(active)
+	for count := uint(0); ; {
+		select {
+		case <-half.C:
+			pin.Toggle()
+			if pin.Shadow() == active {
+				continue
+			}
+			count++
+			if blinkOpts.Count > 0 && count >= blinkOpts.Count {
+				return nil
+			}
+		case <-sigdone:
+			return nil
+		}
+	}
+}
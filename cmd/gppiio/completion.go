@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+
+// +build linux
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/warthog618/gpio"
+)
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+	rootCmd.BashCompletionFunction = bashCompletionFunction()
+	names := pinNames()
+	for _, cmd := range []*cobra.Command{getCmd, modeCmd, pullCmd, blinkCmd, pulseCmd, monCmd, exportCmd, waitCmd, benchCmd} {
+		cmd.MarkZshCompPositionalArgumentWords(1, names...)
+	}
+}
+
+var completionCmd = &cobra.Command{
+	Use:   "completion <bash|zsh|powershell>",
+	Short: "Generate a shell completion script",
+	Long: `Generate a shell completion script, which completes commands and flags,
+and pin names, levels and modes for the commands that take them.
+
+To load the completions in the current bash shell:
+
+  source <(gppiio completion bash)
+
+or to load them for every session, write the script to the bash-completion
+directory, e.g. /etc/bash_completion.d/gppiio.
+
+Zsh only completes the first pin of each command.`,
+	Args:      cobra.ExactValidArgs(1),
+	ValidArgs: []string{"bash", "zsh", "powershell"},
+	RunE:      completion,
+}
+
+func completion(cmd *cobra.Command, args []string) error {
+	switch args[0] {
+	case "bash":
+		return rootCmd.GenBashCompletion(os.Stdout)
+	case "zsh":
+		return rootCmd.GenZshCompletion(os.Stdout)
+	case "powershell":
+		return rootCmd.GenPowerShellCompletion(os.Stdout)
+	}
+	return errors.New("unsupported shell")
+}
+
+// pinNames returns the J8 and BCM names of the header pins.
+func pinNames() []string {
+	var names []string
+	for o := 0; o < gpio.MaxGPIOPin; o++ {
+		j8, _ := gpio.BCMToJ8(o)
+		names = append(names, fmt.Sprintf("J8p%d", j8), fmt.Sprintf("GPIO%d", o))
+	}
+	return names
+}
+
+// bashCompletionFunction returns the custom bash functions that complete pin
+// names, and the pin=level and pin=mode assignments of set and setmode.
+func bashCompletionFunction() string {
+	var modes []string
+	for _, m := range []gpio.Mode{gpio.Input, gpio.Output,
+		gpio.Alt0, gpio.Alt1, gpio.Alt2, gpio.Alt3, gpio.Alt4, gpio.Alt5} {
+		modes = append(modes, m.String())
+	}
+	r := strings.NewReplacer(
+		"PINS", strings.Join(pinNames(), " "),
+		"MODES", strings.Join(modes, " "))
+	return r.Replace(`
+__gppiio_assign()
+{
+    if [[ ${cur} == *=* ]]; then
+        local pin="${cur%%=*}"
+        COMPREPLY=( $(compgen -P "${pin}=" -W "$1" -- "${cur#*=}") )
+    else
+        COMPREPLY=( $(compgen -S "=" -W "PINS" -- "${cur}") )
+        if [[ $(type -t compopt) = "builtin" ]]; then
+            compopt -o nospace
+        fi
+    fi
+}
+
+__gppiio_custom_func()
+{
+    case ${last_command} in
+        gppiio_get | gppiio_mode | gppiio_pull | gppiio_blink | gppiio_pulse | gppiio_mon | gppiio_export | gppiio_wait | gppiio_bench)
+            COMPREPLY=( $(compgen -W "PINS" -- "${cur}") )
+            ;;
+        gppiio_set)
+            __gppiio_assign "high low"
+            ;;
+        gppiio_setmode)
+            __gppiio_assign "MODES"
+            ;;
+    esac
+}
+`)
+}
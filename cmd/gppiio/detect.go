@@ -30,13 +30,16 @@ func detect(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	defer gpio.Close()
-	switch gpio.Chip() {
+	fmt.Println(chipName(gpio.Chip()))
+	return nil
+}
+
+func chipName(c gpio.Chipset) string {
+	switch c {
 	case gpio.BCM2835:
-		fmt.Println("bcm2835")
+		return "bcm2835"
 	case gpio.BCM2711:
-		fmt.Println("bcm2711")
-	default:
-		fmt.Println("unknown")
+		return "bcm2711"
 	}
-	return nil
+	return "unknown"
 }
@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright © 2019 Kent Gibson <warthog618@gmail.com>.
+
+// +build linux
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/warthog618/gpio"
+	"github.com/warthog618/gpio/exporter"
+)
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportOpts.Listen, "listen", "L", ":9101", "the address to listen on")
+	exportCmd.SetHelpTemplate(exportCmd.HelpTemplate() + extendedExportHelp)
+	rootCmd.AddCommand(exportCmd)
+}
+
+var extendedExportHelp = `
+Pins:
+  Pins may be identified by name (J8pXX) or number (0-26).
+
+The metrics are served from /metrics.
+
+Note that exporting a pin forces it into input mode.
+`
+
+var (
+	exportCmd = &cobra.Command{
+		Use:     "export <pin1>...",
+		Short:   "Export pin metrics to Prometheus",
+		Args:    cobra.MinimumNArgs(1),
+		RunE:    export,
+		Example: "  gppiio export --listen :9101 J8p7 J8p15",
+	}
+	exportOpts = struct {
+		Listen string
+	}{}
+)
+
+func export(cmd *cobra.Command, args []string) error {
+	oo, err := parseOffsets(args)
+	if err != nil {
+		return err
+	}
+	err = gpio.Open()
+	if err != nil {
+		return err
+	}
+	defer gpio.Close()
+	for _, o := range oo {
+		gpio.NewPin(o).Input()
+	}
+	w, err := gpio.NewWatcher(gpio.WithSerialDispatch())
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	e, err := exporter.New(w, oo...)
+	if err != nil {
+		return err
+	}
+	defer e.Close()
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", e)
+	srv := &http.Server{Addr: exportOpts.Listen, Handler: mux}
+	sigdone := make(chan os.Signal, 1)
+	signal.Notify(sigdone, os.Interrupt, os.Kill)
+	defer signal.Stop(sigdone)
+	go func() {
+		<-sigdone
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		srv.Shutdown(ctx)
+	}()
+	err = srv.ListenAndServe()
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
@@ -70,11 +70,14 @@ func get(cmd *cobra.Command, args []string) (err error) {
 		return err
 	}
 	defer gpio.Close()
+	for _, o := range oo {
+		gpio.NewPin(o).Input()
+	}
+	// sample all the pins together to avoid skew.
+	banks := [2]uint32{gpio.ReadBank(0), gpio.ReadBank(1)}
 	vv := make([]gpio.Level, len(oo))
 	for i, o := range oo {
-		pin := gpio.NewPin(o)
-		pin.Input()
-		v := pin.Read()
+		v := gpio.Level(banks[o/32]&(1<<uint(o%32)) != 0)
 		if getOpts.ActiveLow {
 			v = !v
 		}
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright © 2019 Kent Gibson <warthog618@gmail.com>.
+
+// +build linux
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/warthog618/gpio"
+)
+
+func init() {
+	rootCmd.AddCommand(infoCmd)
+}
+
+var infoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Display the state of all header pins",
+	Long: `Display the BCM number, J8 header position, mode, level, pull and
+alternate function of each of the GPIO pins on the J8 header.
+
+The pull is only known on the BCM2711, or where set since boot.`,
+	Args: cobra.NoArgs,
+	RunE: info,
+}
+
+// the number of GPIO pins on the J8 header.
+const headerPins = 28
+
+func info(cmd *cobra.Command, args []string) error {
+	err := gpio.Open()
+	if err != nil {
+		return err
+	}
+	defer gpio.Close()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "BCM\tJ8\tMODE\tLEVEL\tPULL\tFUNCTION")
+	for o := 0; o < headerPins; o++ {
+		pin := gpio.NewPin(o)
+		m := pin.Mode()
+		fmt.Fprintf(w, "%d\t%d\t%s\t%d\t%s\t%s\n",
+			o, j8Positions[o], m, level2Int(pin.Read()), pin.Pull(), gpio.AltFunc(o, m))
+	}
+	return w.Flush()
+}
+
+var j8Positions = map[int]int{
+	gpio.J8p3:  3,
+	gpio.J8p5:  5,
+	gpio.J8p7:  7,
+	gpio.J8p8:  8,
+	gpio.J8p10: 10,
+	gpio.J8p11: 11,
+	gpio.J8p12: 12,
+	gpio.J8p13: 13,
+	gpio.J8p15: 15,
+	gpio.J8p16: 16,
+	gpio.J8p18: 18,
+	gpio.J8p19: 19,
+	gpio.J8p21: 21,
+	gpio.J8p22: 22,
+	gpio.J8p23: 23,
+	gpio.J8p24: 24,
+	gpio.J8p26: 26,
+	gpio.J8p27: 27,
+	gpio.J8p28: 28,
+	gpio.J8p29: 29,
+	gpio.J8p31: 31,
+	gpio.J8p32: 32,
+	gpio.J8p33: 33,
+	gpio.J8p35: 35,
+	gpio.J8p36: 36,
+	gpio.J8p37: 37,
+	gpio.J8p38: 38,
+	gpio.J8p40: 40,
+}
@@ -77,7 +77,11 @@ func mode(cmd *cobra.Command, args []string) (err error) {
 
 func printModes(oo []int, mm []gpio.Mode) {
 	for i, o := range oo {
-		fmt.Printf("pin %2d: %s\n", o, modeNames[mm[i]])
+		if fn := gpio.AltFunc(o, mm[i]); fn != "" {
+			fmt.Printf("pin %2d: %s (%s)\n", o, mm[i], fn)
+		} else {
+			fmt.Printf("pin %2d: %s\n", o, mm[i])
+		}
 	}
 }
 
@@ -88,14 +92,3 @@ func printModesShort(oo []int, mm []gpio.Mode) {
 	}
 	fmt.Println()
 }
-
-var modeNames = map[gpio.Mode]string{
-	gpio.Input:  "input",
-	gpio.Output: "output",
-	gpio.Alt0:   "alt0",
-	gpio.Alt1:   "alt1",
-	gpio.Alt2:   "alt2",
-	gpio.Alt3:   "alt3",
-	gpio.Alt4:   "alt4",
-	gpio.Alt5:   "alt5",
-}
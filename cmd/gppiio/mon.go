@@ -7,10 +7,13 @@
 package main
 
 import (
+	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"os/signal"
+	"strconv"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -24,12 +27,31 @@ func init() {
 	monCmd.Flags().UintVarP(&monOpts.NumEvents, "num-events", "n", 0, "exit after n edges")
 	monCmd.Flags().BoolVarP(&monOpts.Quiet, "quiet", "q", false, "don't display event details")
 	monCmd.Flags().BoolVarP(&monOpts.Sync, "sync", "s", false, "display and count the initial sync event")
+	monCmd.Flags().StringVarP(&monOpts.Format, "format", "F", "text", "the format of event details [text|json|csv]")
+	monCmd.Flags().StringVarP(&monOpts.TimestampFormat, "timestamp-format", "t", "rfc3339", "the format of event timestamps [rfc3339|unix-nano|relative]")
+	monCmd.Flags().DurationVarP(&monOpts.Debounce, "debounce", "d", 0, "ignore edges within the period after an edge on the same pin")
 	monCmd.SetHelpTemplate(monCmd.HelpTemplate() + extendedMonHelp)
 	rootCmd.AddCommand(monCmd)
 }
 
 var extendedMonHelp = `
 By default both rising and falling edge events are detected and reported.
+
+Formats:
+  text  one human readable line per event.
+  json  one JSON object per event, with seq, pin, edge and time fields.
+  csv   a header line, then one record per event, with the same fields.
+
+The seq is the number of the event, from 1.
+
+Timestamp formats:
+  rfc3339    RFC3339 with nanoseconds.
+  unix-nano  nanoseconds since the Unix epoch.
+  relative   seconds since the start of monitoring.
+
+With --debounce, edges on a pin within the period after a reported edge on
+that pin are ignored, so each press of a noisy mechanical switch is reported
+once.
 `
 
 var (
@@ -41,12 +63,15 @@ var (
 		RunE:  mon,
 	}
 	monOpts = struct {
-		ActiveLow   bool
-		RisingEdge  bool
-		FallingEdge bool
-		Quiet       bool
-		Sync        bool
-		NumEvents   uint
+		ActiveLow       bool
+		RisingEdge      bool
+		FallingEdge     bool
+		Quiet           bool
+		Sync            bool
+		NumEvents       uint
+		Format          string
+		TimestampFormat string
+		Debounce        time.Duration
 	}{}
 )
 
@@ -60,6 +85,14 @@ func mon(cmd *cobra.Command, args []string) error {
 	if monOpts.RisingEdge && monOpts.FallingEdge {
 		return errors.New("can't filter both falling-edge and rising-edge events")
 	}
+	p, err := newEventPrinter(monOpts.Format)
+	if err != nil {
+		return err
+	}
+	tf, err := newTimeFormatter(monOpts.TimestampFormat, time.Now())
+	if err != nil {
+		return err
+	}
 	oo, err := parseOffsets(args)
 	if err != nil {
 		return err
@@ -68,10 +101,6 @@ func mon(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	if monOpts.ActiveLow {
-		monOpts.RisingEdge = !monOpts.RisingEdge
-		monOpts.FallingEdge = !monOpts.FallingEdge
-	}
 	var edge gpio.Edge
 	switch {
 	case monOpts.RisingEdge == monOpts.FallingEdge:
@@ -82,46 +111,56 @@ func mon(cmd *cobra.Command, args []string) error {
 		edge = gpio.EdgeFalling
 	}
 	evtchan := make(chan event)
-	eh := func(p *gpio.Pin) {
-		evt := event{
-			Time:  time.Now(),
-			Pin:   p.Pin(),
-			Level: p.Read(),
+	eh := func(evt gpio.Event) {
+		evtchan <- event{
+			Time:  evt.Time,
+			Pin:   evt.Pin.Pin(),
+			Level: evt.Level,
 		}
-		evtchan <- evt
 	}
 	defer gpio.Close()
 	for _, o := range oo {
 		pin := gpio.NewPin(o)
 		pin.Input()
-		pin.Watch(edge, eh)
+		var wt *gpio.Watch
+		if monOpts.ActiveLow {
+			wt, err = pin.Inverted().AddWatch(edge, eh)
+		} else {
+			wt, err = pin.AddWatch(edge, eh)
+		}
+		if err != nil {
+			// watches already added are removed by their deferred Unwatch.
+			return err
+		}
+		defer wt.Unwatch()
 	}
-	monWait(evtchan)
+	monWait(evtchan, p, tf)
 	return nil
 }
 
-func monWait(evtchan <-chan event) {
+func monWait(evtchan <-chan event, p eventPrinter, tf timeFormatter) {
 	sigdone := make(chan os.Signal, 1)
 	signal.Notify(sigdone, os.Interrupt, os.Kill)
 	defer signal.Stop(sigdone)
 	count := uint(0)
 	pinSynced := make(map[int]bool)
+	lastEdge := make(map[int]time.Time)
 	for {
 		select {
 		case evt := <-evtchan:
-			level := evt.Level
-			if monOpts.ActiveLow {
-				level = !level
-			}
 			edge := "rising"
-			if level == gpio.Low {
+			if evt.Level == gpio.Low {
 				edge = "falling"
 			}
 			if monOpts.Sync || pinSynced[evt.Pin] {
-				if !monOpts.Quiet {
-					fmt.Printf("event:%3d %-7s %s\n", evt.Pin, edge, evt.Time.Format(time.RFC3339Nano))
+				if last, ok := lastEdge[evt.Pin]; ok && evt.Time.Sub(last) < monOpts.Debounce {
+					continue
 				}
+				lastEdge[evt.Pin] = evt.Time
 				count++
+				if !monOpts.Quiet {
+					p(count, evt.Pin, edge, tf(evt.Time))
+				}
 				if monOpts.NumEvents > 0 && count >= monOpts.NumEvents {
 					return
 				}
@@ -132,3 +171,65 @@ func monWait(evtchan <-chan event) {
 		}
 	}
 }
+
+// eventPrinter prints the details of an event.
+type eventPrinter func(seq uint, pin int, edge string, ts string)
+
+func newEventPrinter(format string) (eventPrinter, error) {
+	switch format {
+	case "text":
+		return func(seq uint, pin int, edge string, ts string) {
+			fmt.Printf("event:%3d %-7s %s\n", pin, edge, ts)
+		}, nil
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		return func(seq uint, pin int, edge string, ts string) {
+			enc.Encode(eventRecord{seq, pin, edge, ts})
+		}, nil
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		header := false
+		return func(seq uint, pin int, edge string, ts string) {
+			if !header {
+				w.Write([]string{"seq", "pin", "edge", "time"})
+				header = true
+			}
+			w.Write([]string{
+				strconv.FormatUint(uint64(seq), 10),
+				strconv.Itoa(pin),
+				edge,
+				ts,
+			})
+			w.Flush()
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown format '%s'", format)
+}
+
+type eventRecord struct {
+	Seq  uint   `json:"seq"`
+	Pin  int    `json:"pin"`
+	Edge string `json:"edge"`
+	Time string `json:"time"`
+}
+
+// timeFormatter formats the timestamp of an event.
+type timeFormatter func(t time.Time) string
+
+func newTimeFormatter(format string, start time.Time) (timeFormatter, error) {
+	switch format {
+	case "rfc3339":
+		return func(t time.Time) string {
+			return t.Format(time.RFC3339Nano)
+		}, nil
+	case "unix-nano":
+		return func(t time.Time) string {
+			return strconv.FormatInt(t.UnixNano(), 10)
+		}, nil
+	case "relative":
+		return func(t time.Time) string {
+			return fmt.Sprintf("%.9f", t.Sub(start).Seconds())
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown timestamp format '%s'", format)
+}
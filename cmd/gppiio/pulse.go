@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright © 2019 Kent Gibson <warthog618@gmail.com>.
+
+// +build linux
+
+package main
+
+import (
+	"errors"
+	"runtime"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/warthog618/gpio"
+)
+
+func init() {
+	pulseCmd.Flags().DurationVarP(&pulseOpts.Width, "width", "w", 10*time.Microsecond, "the width of the pulse")
+	pulseCmd.Flags().StringVarP(&pulseOpts.Level, "level", "", "high", "the level of the pulse")
+	pulseCmd.SetHelpTemplate(pulseCmd.HelpTemplate() + extendedPulseHelp)
+	rootCmd.AddCommand(pulseCmd)
+}
+
+var extendedPulseHelp = `
+Pins:
+  Pins may be identified by name (J8pXX) or number (0-26).
+
+Levels:
+  Levels may be [high|hi|true|1|low|lo|false|0] and are case insensitive.
+
+The pin is set to the opposite level, then pulsed to the level for the width.
+The width is timed by busy waiting so it is accurate to within a few
+microseconds, unless the process is preempted.
+
+Note that pulsing a pin forces it into output mode, and it is left at the
+opposite level.
+`
+
+var (
+	pulseCmd = &cobra.Command{
+		Use:     "pulse <pin>",
+		Short:   "Emit a single pulse on a pin",
+		Args:    cobra.ExactArgs(1),
+		RunE:    pulse,
+		Example: "  gppiio pulse J8p7 --width 10us --level high",
+	}
+	pulseOpts = struct {
+		Width time.Duration
+		Level string
+	}{}
+)
+
+func pulse(cmd *cobra.Command, args []string) error {
+	if pulseOpts.Width <= 0 {
+		return errors.New("width must be positive")
+	}
+	level, err := gpio.ParseLevel(pulseOpts.Level)
+	if err != nil {
+		return err
+	}
+	o, err := parseOffset(args[0])
+	if err != nil {
+		return err
+	}
+	err = gpio.Open()
+	if err != nil {
+		return err
+	}
+	defer gpio.Close()
+	if err = checkProtected(o); err != nil {
+		return err
+	}
+	pin := gpio.NewPin(o)
+	pin.Write(!level)
+	pin.Output()
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	start := time.Now()
+	pin.Write(level)
+	for time.Since(start) < pulseOpts.Width {
+	}
+	pin.Write(!level)
+	return nil
+}
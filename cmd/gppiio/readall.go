@@ -0,0 +1,130 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+
+// +build linux
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/warthog618/gpio"
+)
+
+func init() {
+	rootCmd.AddCommand(readallCmd)
+}
+
+var readallCmd = &cobra.Command{
+	Use:   "readall",
+	Short: "Display the state of the header in the style of WiringPi",
+	Long: `Display the BCM number, name, mode and level of each pin on the header,
+laid out by physical pin position, in the style of the WiringPi "gpio readall"
+command.
+
+The name of a pin in an alternate mode is the name of its alternate function.
+The WiringPi numbering is not displayed.`,
+	Args: cobra.NoArgs,
+	RunE: readall,
+}
+
+const (
+	readallBorder = " +-----+------------+------+---+----------+---+------+------------+-----+"
+	readallHeader = " | BCM |    Name    | Mode | V | Physical | V | Mode | Name       | BCM |"
+	readallRule   = " +-----+------------+------+---+----++----+---+------+------------+-----+"
+)
+
+func readall(cmd *cobra.Command, args []string) error {
+	rows := 20
+	title := ""
+	if b, err := gpio.Board(); err == nil {
+		title = "Pi " + b.Model
+		if b.Header == gpio.Header26 {
+			rows = 13
+		}
+	}
+	err := gpio.Open()
+	if err != nil {
+		return err
+	}
+	defer gpio.Close()
+	border := titled(readallBorder, title)
+	fmt.Println(border)
+	fmt.Println(readallHeader)
+	fmt.Println(readallRule)
+	for r := 0; r < rows; r++ {
+		odd := 2*r + 1
+		l := readHeaderPin(odd)
+		rt := readHeaderPin(odd + 1)
+		fmt.Printf(" | %3s | %10s | %4s | %1s | %2d || %-2d | %1s | %-4s | %-10s | %3s |\n",
+			l.bcm, l.name, l.mode, l.level, odd, odd+1, rt.level, rt.mode, rt.name, rt.bcm)
+	}
+	fmt.Println(readallRule)
+	fmt.Println(readallHeader)
+	fmt.Println(border)
+	return nil
+}
+
+// headerPin is the state of a header pin, formatted for display.
+type headerPin struct {
+	bcm   string
+	name  string
+	mode  string
+	level string
+}
+
+// readHeaderPin returns the state of the J8 header pin.
+func readHeaderPin(j8 int) headerPin {
+	bcm, err := gpio.J8ToBCM(j8)
+	if err != nil {
+		return headerPin{name: powerPins[j8]}
+	}
+	pin := gpio.NewPin(bcm)
+	m := pin.Mode()
+	hp := headerPin{
+		bcm:   strconv.Itoa(bcm),
+		name:  gpio.AltFunc(bcm, m),
+		level: strconv.Itoa(level2Int(pin.Read())),
+	}
+	if hp.name == "" {
+		hp.name = fmt.Sprintf("GPIO%d", bcm)
+	}
+	switch m {
+	case gpio.Input:
+		hp.mode = "IN"
+	case gpio.Output:
+		hp.mode = "OUT"
+	default:
+		hp.mode = strings.ToUpper(m.String())
+	}
+	return hp
+}
+
+// titled returns the border with the title centred in it.
+func titled(border, title string) string {
+	if title == "" || len(title)+2 > len(border) {
+		return border
+	}
+	pos := (len(border) - len(title)) / 2
+	return border[:pos] + title + border[pos+len(title):]
+}
+
+// powerPins are the names of the J8 header pins that are not GPIOs.
+var powerPins = map[int]string{
+	1:  "3.3v",
+	2:  "5v",
+	4:  "5v",
+	6:  "0v",
+	9:  "0v",
+	14: "0v",
+	17: "3.3v",
+	20: "0v",
+	25: "0v",
+	30: "0v",
+	34: "0v",
+	39: "0v",
+}
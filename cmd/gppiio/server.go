@@ -0,0 +1,258 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright © 2019 Kent Gibson <warthog618@gmail.com>.
+
+// +build linux
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/warthog618/gpio"
+)
+
+func init() {
+	serverCmd.Flags().StringVarP(&serverOpts.Listen, "listen", "L", ":8080", "the address to listen on")
+	serverCmd.SetHelpTemplate(serverCmd.HelpTemplate() + extendedServerHelp)
+	rootCmd.AddCommand(serverCmd)
+}
+
+var extendedServerHelp = `
+Endpoints:
+  GET  /pins                   the state of all pins
+  GET  /pins/<pin>             the state of a pin
+  PUT  /pins/<pin>/level       set the level, forcing the pin into output mode
+  PUT  /pins/<pin>/mode        set the mode
+  PUT  /pins/<pin>/pull        set the pull
+  GET  /events?pins=<pin>,...  stream edge events as Server-Sent Events
+
+Values for PUT are passed in the body or the value query parameter, and use
+the same names as the other commands, e.g.
+  curl -X PUT localhost:8080/pins/J8p7/level?value=high
+
+The events endpoint accepts an edge query parameter [rising|falling|both].
+The pins must already be inputs.
+
+Note that the server provides no authentication, so only listen on trusted
+networks.
+`
+
+var (
+	serverCmd = &cobra.Command{
+		Use:   "server",
+		Short: "Serve pin control and events over HTTP",
+		Args:  cobra.NoArgs,
+		RunE:  server,
+	}
+	serverOpts = struct {
+		Listen string
+	}{}
+)
+
+type pinState struct {
+	Pin   int    `json:"pin"`
+	Mode  string `json:"mode"`
+	Level int    `json:"level"`
+	Pull  string `json:"pull"`
+}
+
+type pinServer struct {
+	watcher *gpio.Watcher
+}
+
+func server(cmd *cobra.Command, args []string) error {
+	err := gpio.Open()
+	if err != nil {
+		return err
+	}
+	defer gpio.Close()
+	// serial dispatch so each handler sees the sync event first.
+	w, err := gpio.NewWatcher(gpio.WithSerialDispatch())
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	ps := pinServer{watcher: w}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pins", ps.pins)
+	mux.HandleFunc("/pins/", ps.pin)
+	mux.HandleFunc("/events", ps.events)
+	srv := &http.Server{Addr: serverOpts.Listen, Handler: mux}
+	sigdone := make(chan os.Signal, 1)
+	signal.Notify(sigdone, os.Interrupt, os.Kill)
+	defer signal.Stop(sigdone)
+	go func() {
+		<-sigdone
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		srv.Shutdown(ctx)
+	}()
+	err = srv.ListenAndServe()
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+func readPinState(o int) pinState {
+	pin := gpio.NewPin(o)
+	return pinState{
+		Pin:   o,
+		Mode:  pin.Mode().String(),
+		Level: level2Int(pin.Read()),
+		Pull:  pin.Pull().String(),
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func (ps pinServer) pins(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	ss := make([]pinState, gpio.MaxGPIOPin)
+	for o := range ss {
+		ss[o] = readPinState(o)
+	}
+	writeJSON(w, ss)
+}
+
+func (ps pinServer) pin(w http.ResponseWriter, r *http.Request) {
+	path := strings.Split(strings.TrimPrefix(r.URL.Path, "/pins/"), "/")
+	o, err := parseOffset(path[0])
+	if err != nil || len(path) > 2 {
+		http.NotFound(w, r)
+		return
+	}
+	if len(path) == 1 {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		writeJSON(w, readPinState(o))
+		return
+	}
+	if r.Method != http.MethodPut && r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	value := r.URL.Query().Get("value")
+	if value == "" {
+		body, _ := io.ReadAll(io.LimitReader(r.Body, 64))
+		value = strings.TrimSpace(string(body))
+	}
+	if path[1] != "pull" && gpio.Protected(o) {
+		http.Error(w, gpio.ErrProtected.Error(), http.StatusForbidden)
+		return
+	}
+	pin := gpio.NewPin(o)
+	switch path[1] {
+	case "level":
+		l, err := gpio.ParseLevel(value)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		pin.Write(l)
+		pin.Output()
+	case "mode":
+		m, err := gpio.ParseMode(value)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		pin.SetMode(m)
+	case "pull":
+		p, err := gpio.ParsePull(value)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		pin.SetPull(p)
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	writeJSON(w, readPinState(o))
+}
+
+func (ps pinServer) events(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+	q := r.URL.Query()
+	oo, err := parseOffsets(strings.Split(q.Get("pins"), ","))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	edge := gpio.EdgeBoth
+	switch q.Get("edge") {
+	case "", "both":
+	case "rising":
+		edge = gpio.EdgeRising
+	case "falling":
+		edge = gpio.EdgeFalling
+	default:
+		http.Error(w, fmt.Sprintf("unknown edge '%s'", q.Get("edge")), http.StatusBadRequest)
+		return
+	}
+	evtchan := make(chan gpio.Event, 64)
+	for _, o := range oo {
+		synced := false
+		wt, err := ps.watcher.AddWatch(gpio.NewPin(o), edge, func(evt gpio.Event) {
+			if !synced {
+				synced = true
+				return
+			}
+			select {
+			case evtchan <- evt:
+			default:
+			}
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		defer wt.Unwatch()
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	flusher.Flush()
+	enc := json.NewEncoder(w)
+	for seq := uint(1); ; seq++ {
+		select {
+		case evt := <-evtchan:
+			edge := "rising"
+			if evt.Level == gpio.Low {
+				edge = "falling"
+			}
+			fmt.Fprint(w, "data: ")
+			enc.Encode(eventRecord{seq, evt.Pin.Pin(), edge, evt.Time.Format(time.RFC3339Nano)})
+			fmt.Fprint(w, "\n")
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
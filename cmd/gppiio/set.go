@@ -8,7 +8,10 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"os/signal"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/warthog618/gpio"
@@ -16,6 +19,7 @@ import (
 
 func init() {
 	setCmd.Flags().BoolVarP(&setOpts.ActiveLow, "active-low", "l", false, "treat the line level as active low")
+	setCmd.Flags().DurationVarP(&setOpts.Duration, "duration", "d", 0, "hold the levels for the duration then restore the pins")
 	setCmd.SetHelpTemplate(setCmd.HelpTemplate() + extendedSetHelp)
 	rootCmd.AddCommand(setCmd)
 }
@@ -30,6 +34,7 @@ var (
 	}
 	setOpts = struct {
 		ActiveLow bool
+		Duration  time.Duration
 	}{}
 )
 
@@ -41,6 +46,9 @@ Levels:
   Levels may be [high|hi|true|1|low|lo|false|0] and are case insensitive.
   
 Note that setting a pin forces it into output mode.
+
+With --duration, the levels are held for the duration, or until interrupted,
+then the pins are restored to their previous mode and level.
 `
 
 func set(cmd *cobra.Command, args []string) error {
@@ -59,17 +67,47 @@ func set(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	defer gpio.Close()
+	if err = checkProtected(ll...); err != nil {
+		return err
+	}
+	pp := make([]*gpio.Pin, len(ll))
+	mm := make([]gpio.Mode, len(ll))
+	prev := make([]gpio.Level, len(ll))
 	for i, v := range vv {
 		pin := gpio.NewPin(ll[i])
-		if getOpts.ActiveLow {
-			v = !v
-		}
+		pp[i] = pin
+		mm[i] = pin.Mode()
+		prev[i] = pin.Read()
 		pin.Output()
-		pin.Write(v)
+		if setOpts.ActiveLow {
+			pin.Inverted().Write(v)
+		} else {
+			pin.Write(v)
+		}
+	}
+	if setOpts.Duration > 0 {
+		setWait(setOpts.Duration)
+		for i, pin := range pp {
+			if mm[i] == gpio.Output {
+				pin.Write(prev[i])
+			}
+			pin.SetMode(mm[i])
+		}
 	}
 	return nil
 }
 
+// setWait waits for the duration, or until interrupted.
+func setWait(d time.Duration) {
+	sigdone := make(chan os.Signal, 1)
+	signal.Notify(sigdone, os.Interrupt, os.Kill)
+	defer signal.Stop(sigdone)
+	select {
+	case <-time.After(d):
+	case <-sigdone:
+	}
+}
+
 func parseLineLevel(arg string) (int, gpio.Level, error) {
 	aa := strings.Split(arg, "=")
 	if len(aa) != 2 {
@@ -79,27 +117,9 @@ func parseLineLevel(arg string) (int, gpio.Level, error) {
 	if err != nil {
 		return 0, gpio.Low, err
 	}
-	v, err := parseLevel(aa[1])
+	v, err := gpio.ParseLevel(aa[1])
 	if err != nil {
 		return 0, gpio.Low, err
 	}
 	return int(o), v, nil
 }
-
-func parseLevel(arg string) (gpio.Level, error) {
-	if l, ok := levelNames[strings.ToLower(arg)]; ok {
-		return l, nil
-	}
-	return gpio.Low, fmt.Errorf("can't parse level '%s'", arg)
-}
-
-var levelNames = map[string]gpio.Level{
-	"high":  gpio.High,
-	"hi":    gpio.High,
-	"true":  gpio.High,
-	"1":     gpio.High,
-	"low":   gpio.Low,
-	"lo":    gpio.Low,
-	"false": gpio.Low,
-	"0":     gpio.Low,
-}
@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright © 2019 Kent Gibson <warthog618@gmail.com>.
+
+// +build linux
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/warthog618/gpio"
+	"golang.org/x/sys/unix"
+)
+
+func init() {
+	setmodeCmd.Flags().BoolVarP(&setmodeOpts.Force, "force", "f", false, "set alternate functions without confirmation")
+	setmodeCmd.SetHelpTemplate(setmodeCmd.HelpTemplate() + extendedSetmodeHelp)
+	rootCmd.AddCommand(setmodeCmd)
+}
+
+var (
+	setmodeCmd = &cobra.Command{
+		Use:     "setmode <pin1>=<mode1>...",
+		Short:   "Set the functional mode of a pin or pins",
+		Args:    cobra.MinimumNArgs(1),
+		RunE:    setmode,
+		Example: "  gppiio setmode J8p15=output J8p7=alt0",
+	}
+	setmodeOpts = struct {
+		Force bool
+	}{}
+)
+
+var extendedSetmodeHelp = `
+Pins:
+  Pins may be identified by name (J8pXX) or number (0-26).
+
+Modes:
+  Modes may be [input|output|alt0|alt1|alt2|alt3|alt4|alt5] and are case
+  insensitive.
+
+Setting an alternate function connects the pin to a peripheral, which may
+drive the pin, so confirmation is required unless --force is specified.
+Without --force, alternate functions can only be set from a terminal.
+`
+
+func setmode(cmd *cobra.Command, args []string) error {
+	ll := []int(nil)
+	mm := []gpio.Mode(nil)
+	alts := []string(nil)
+	for _, arg := range args {
+		aa := strings.Split(arg, "=")
+		if len(aa) != 2 {
+			return fmt.Errorf("invalid pin<->mode mapping: %s", arg)
+		}
+		o, err := parseOffset(aa[0])
+		if err != nil {
+			return err
+		}
+		m, err := gpio.ParseMode(aa[1])
+		if err != nil {
+			return err
+		}
+		if m != gpio.Input && m != gpio.Output {
+			alts = append(alts, fmt.Sprintf("pin %d to %s", o, m))
+		}
+		ll = append(ll, o)
+		mm = append(mm, m)
+	}
+	if len(alts) > 0 && !setmodeOpts.Force {
+		if err := confirm("Set " + strings.Join(alts, ", ")); err != nil {
+			return err
+		}
+	}
+	err := gpio.Open()
+	if err != nil {
+		return err
+	}
+	defer gpio.Close()
+	if err = checkProtected(ll...); err != nil {
+		return err
+	}
+	for i, m := range mm {
+		gpio.NewPin(ll[i]).SetMode(m)
+	}
+	return nil
+}
+
+// confirm asks the user to confirm the action, returning an error if they
+// do not, or if stdin is not a terminal.
+func confirm(action string) error {
+	if _, err := unix.IoctlGetTermios(int(os.Stdin.Fd()), unix.TCGETS); err != nil {
+		return errors.New("confirmation required - use --force")
+	}
+	fmt.Printf("%s? [y/N] ", action)
+	resp, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(resp)) {
+	case "y", "yes":
+		return nil
+	}
+	return errors.New("aborted")
+}
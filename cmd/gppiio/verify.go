@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright © 2019 Kent Gibson <warthog618@gmail.com>.
+
+// +build linux
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/warthog618/gpio"
+)
+
+func init() {
+	verifyCmd.SetHelpTemplate(verifyCmd.HelpTemplate() + extendedVerifyHelp)
+	modeCmd.AddCommand(verifyCmd)
+}
+
+var verifyCmd = &cobra.Command{
+	Use:          "verify <config>",
+	Short:        "Verify the pin configuration against that expected",
+	Args:         cobra.ExactArgs(1),
+	RunE:         verify,
+	SilenceUsage: true,
+	Example:      "  gppiio mode verify controller.json",
+}
+
+var extendedVerifyHelp = `
+Config:
+  The config is a JSON object mapping pins to their expected state, e.g.
+
+  {
+    "J8p7": {"mode": "input", "pull": "up"},
+    "GPIO17": {"mode": "output", "level": "low"}
+  }
+
+  Each of mode, level and pull is optional.
+  Pins may be identified by name (J8pXX or GPIOXX) or number (0-27).
+  Modes may be [input|output|alt0|alt1|alt2|alt3|alt4|alt5].
+  Pulls may be [up|down|none].
+
+Any differences are reported and the command exits with a non-zero status.
+
+Note that pulls can only be read back from the bcm2711, so expected pulls are
+reported as unverifiable on other chips, and the command also exits with a
+non-zero status.
+`
+
+type pinExpectation struct {
+	Mode  string `json:"mode"`
+	Level string `json:"level"`
+	Pull  string `json:"pull"`
+}
+
+type expectation struct {
+	pin   int
+	mode  *gpio.Mode
+	level *gpio.Level
+	pull  *gpio.Pull
+}
+
+func verify(cmd *cobra.Command, args []string) error {
+	ee, err := loadExpectations(args[0])
+	if err != nil {
+		return err
+	}
+	err = gpio.Open()
+	if err != nil {
+		return err
+	}
+	defer gpio.Close()
+	diffs := 0
+	unverified := 0
+	for _, e := range ee {
+		pin := gpio.NewPin(e.pin)
+		if e.mode != nil {
+			if m := pin.Mode(); m != *e.mode {
+				fmt.Printf("pin %2d: mode: expected %s, got %s\n", e.pin, *e.mode, m)
+				diffs++
+			}
+		}
+		if e.level != nil {
+			if l := pin.Read(); l != *e.level {
+				fmt.Printf("pin %2d: level: expected %d, got %d\n", e.pin, level2Int(*e.level), level2Int(l))
+				diffs++
+			}
+		}
+		if e.pull != nil {
+			switch p := pin.Pull(); p {
+			case *e.pull:
+			case gpio.PullUnknown:
+				fmt.Printf("pin %2d: pull: expected %s, but cannot be read back\n", e.pin, *e.pull)
+				unverified++
+			default:
+				fmt.Printf("pin %2d: pull: expected %s, got %s\n", e.pin, *e.pull, p)
+				diffs++
+			}
+		}
+	}
+	if diffs != 0 {
+		return fmt.Errorf("%d difference(s) from %s", diffs, args[0])
+	}
+	if unverified != 0 {
+		return fmt.Errorf("%d pull(s) from %s could not be verified", unverified, args[0])
+	}
+	return nil
+}
+
+func loadExpectations(path string) ([]expectation, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	cfg := map[string]pinExpectation{}
+	if err = json.NewDecoder(f).Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("can't parse config '%s': %s", path, err)
+	}
+	ee := []expectation(nil)
+	for name, pe := range cfg {
+		o, err := parseOffset(name)
+		if err != nil {
+			return nil, err
+		}
+		e := expectation{pin: o}
+		if pe.Mode != "" {
+			m, err := gpio.ParseMode(pe.Mode)
+			if err != nil {
+				return nil, err
+			}
+			e.mode = &m
+		}
+		if pe.Level != "" {
+			l, err := gpio.ParseLevel(pe.Level)
+			if err != nil {
+				return nil, err
+			}
+			e.level = &l
+		}
+		if pe.Pull != "" {
+			p, err := gpio.ParsePull(pe.Pull)
+			if err != nil {
+				return nil, err
+			}
+			e.pull = &p
+		}
+		ee = append(ee, e)
+	}
+	sort.Slice(ee, func(i, j int) bool { return ee[i].pin < ee[j].pin })
+	return ee, nil
+}
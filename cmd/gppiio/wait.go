@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+
+// +build linux
+
+package main
+
+import (
+	"errors"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/warthog618/gpio"
+)
+
+func init() {
+	waitCmd.Flags().StringVarP(&waitOpts.Edge, "edge", "e", "both", "the edge to wait for [rising|falling|both]")
+	waitCmd.Flags().DurationVarP(&waitOpts.Timeout, "timeout", "t", 0, "the maximum time to wait, or 0 to wait indefinitely")
+	waitCmd.SetHelpTemplate(waitCmd.HelpTemplate() + extendedWaitHelp)
+	rootCmd.AddCommand(waitCmd)
+}
+
+var extendedWaitHelp = `
+Pins:
+  Pins may be identified by name (J8pXX) or number (0-26).
+
+The current level of the pin is not reported, only a subsequent edge.
+
+Exits with status 0 when the edge is detected, or 1 if the timeout expires
+first, or an error occurs.
+
+Note that waiting on a pin forces it into input mode.
+`
+
+var (
+	waitCmd = &cobra.Command{
+		Use:     "wait <pin>",
+		Short:   "Wait for an edge on a pin",
+		Args:    cobra.ExactArgs(1),
+		RunE:    wait,
+		Example: "  gppiio wait J8p7 --edge rising --timeout 10s",
+	}
+	waitOpts = struct {
+		Edge    string
+		Timeout time.Duration
+	}{}
+)
+
+func wait(cmd *cobra.Command, args []string) error {
+	edge, err := gpio.ParseEdge(waitOpts.Edge)
+	if err != nil {
+		return err
+	}
+	if edge == gpio.EdgeNone {
+		return errors.New("edge must be rising, falling or both")
+	}
+	o, err := parseOffset(args[0])
+	if err != nil {
+		return err
+	}
+	err = gpio.Open()
+	if err != nil {
+		return err
+	}
+	defer gpio.Close()
+	pin := gpio.NewPin(o)
+	pin.Input()
+	_, err = pin.WaitForEdge(edge, waitOpts.Timeout)
+	return err
+}
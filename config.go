@@ -0,0 +1,171 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Declarative pin configuration.
+
+package gpio
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Config declares the configuration of a set of pins.
+//
+// It can be loaded from a JSON file using LoadConfig, or from a JSON or YAML
+// file using the config package, and applied using ApplyConfig.
+type Config struct {
+	Pins []PinConfig `json:"pins" yaml:"pins"`
+}
+
+// PinConfig declares the configuration of a single pin.
+//
+// Fields that are empty leave the corresponding pin setting unchanged.
+type PinConfig struct {
+	// The name used to refer to the pin.
+	Name string `json:"name" yaml:"name"`
+
+	// The BCM GPIO number of the pin.
+	Pin int `json:"pin" yaml:"pin"`
+
+	// The mode [input|output|alt0|alt1|alt2|alt3|alt4|alt5].
+	Mode string `json:"mode,omitempty" yaml:"mode,omitempty"`
+
+	// The pull [up|down|none].
+	Pull string `json:"pull,omitempty" yaml:"pull,omitempty"`
+
+	// The initial level of an output [high|low].
+	Level string `json:"level,omitempty" yaml:"level,omitempty"`
+
+	// The edge to watch [rising|falling|both].
+	// The handler is provided to ApplyConfig using WithHandler.
+	Watch string `json:"watch,omitempty" yaml:"watch,omitempty"`
+}
+
+// LoadConfig loads a Config from a JSON file.
+//
+// YAML files are loaded using the config package, so the gpio package does
+// not depend on a YAML decoder.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &Config{}
+	if err = json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// ApplyOption modifies the behaviour of ApplyConfig.
+type ApplyOption func(*applyOptions)
+
+type applyOptions struct {
+	handlers map[string]func(Event)
+}
+
+// WithHandler provides the handler for the watch on the named pin.
+func WithHandler(name string, handler func(Event)) ApplyOption {
+	return func(o *applyOptions) {
+		o.handlers[name] = handler
+	}
+}
+
+// ApplyConfig applies the configuration to the pins and returns the pins
+// keyed by name.
+//
+// Outputs are set to their initial level before being set to outputs, to
+// prevent glitches.  Watches are added to the default Watcher using AddWatch.
+// The names are registered as aliases, replacing any existing mapping, so the
+// pins can also be found using PinByName.
+//
+// The configuration is validated before any pins are changed, and setting the
+// mode or level of a protected pin is an error.
+func ApplyConfig(cfg *Config, options ...ApplyOption) (map[string]*Pin, error) {
+	ao := applyOptions{handlers: make(map[string]func(Event))}
+	for _, option := range options {
+		option(&ao)
+	}
+	type pinSetup struct {
+		pin   *Pin
+		mode  *Mode
+		pull  *Pull
+		level *Level
+		edge  *Edge
+	}
+	setups := make([]pinSetup, len(cfg.Pins))
+	pins := make(map[string]*Pin)
+	for i, pc := range cfg.Pins {
+		if pc.Name == "" {
+			return nil, fmt.Errorf("pin %d: no name", pc.Pin)
+		}
+		if _, ok := pins[pc.Name]; ok {
+			return nil, fmt.Errorf("%s: duplicate name", pc.Name)
+		}
+		s := &setups[i]
+		if s.pin = NewPin(pc.Pin); s.pin == nil {
+			return nil, fmt.Errorf("%s: invalid pin %d", pc.Name, pc.Pin)
+		}
+		if Protected(pc.Pin) && (pc.Mode != "" || pc.Level != "") {
+			return nil, fmt.Errorf("%s: %w", pc.Name, ErrProtected)
+		}
+		if pc.Mode != "" {
+			m, err := ParseMode(pc.Mode)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", pc.Name, err)
+			}
+			s.mode = &m
+		}
+		if pc.Pull != "" {
+			p, err := ParsePull(pc.Pull)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", pc.Name, err)
+			}
+			s.pull = &p
+		}
+		if pc.Level != "" {
+			l, err := ParseLevel(pc.Level)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", pc.Name, err)
+			}
+			s.level = &l
+		}
+		if pc.Watch != "" {
+			e, err := ParseEdge(pc.Watch)
+			if err != nil || e == EdgeNone {
+				return nil, fmt.Errorf("%s: invalid watch '%s'", pc.Name, pc.Watch)
+			}
+			if s.mode != nil && *s.mode == Output {
+				return nil, fmt.Errorf("%s: %w", pc.Name, ErrNotInput)
+			}
+			if ao.handlers[pc.Name] == nil {
+				return nil, fmt.Errorf("%s: no handler for watch", pc.Name)
+			}
+			s.edge = &e
+		}
+		pins[pc.Name] = s.pin
+	}
+	for i, s := range setups {
+		Alias(cfg.Pins[i].Name, s.pin.pin)
+		if s.pull != nil {
+			s.pin.SetPull(*s.pull)
+		}
+		if s.level != nil {
+			s.pin.Write(*s.level)
+		}
+		if s.mode != nil {
+			s.pin.SetMode(*s.mode)
+		}
+		if s.edge != nil {
+			name := cfg.Pins[i].Name
+			if _, err := s.pin.AddWatch(*s.edge, ao.handlers[name]); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	}
+	return pins, nil
+}
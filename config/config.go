@@ -0,0 +1,42 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package config loads declarative pin configurations from JSON or YAML files.
+//
+// The configurations are applied using gpio.ApplyConfig.  The loading is
+// separate from the gpio package so that package does not depend on a YAML
+// decoder.
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/warthog618/gpio"
+	"gopkg.in/yaml.v3"
+)
+
+// Load loads a gpio.Config from a file.
+//
+// Files with a .yaml or .yml extension are decoded as YAML, and all others as
+// JSON, as per gpio.LoadConfig.
+func Load(path string) (*gpio.Config, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+	default:
+		return gpio.LoadConfig(path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &gpio.Config{}
+	if err = yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,65 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Test suite for config module.
+//
+// Tests only load files, so can be run on any machine.
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/warthog618/gpio"
+	"github.com/warthog618/gpio/config"
+)
+
+func writeConfig(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.Nil(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	expected := &gpio.Config{Pins: []gpio.PinConfig{
+		{Name: "button", Pin: gpio.GPIO4, Mode: "input", Pull: "up", Watch: "falling"},
+	}}
+	yml := `
+pins:
+  - name: button
+    pin: 4
+    mode: input
+    pull: up
+    watch: falling
+`
+	patterns := []struct {
+		name    string
+		content string
+		ok      bool
+	}{
+		{"pins.json", `{"pins":[{"name":"button","pin":4,"mode":"input","pull":"up","watch":"falling"}]}`, true},
+		{"pins.yaml", yml, true},
+		{"pins.YML", yml, true},
+		{"bad.json", "{", false},
+		{"bad.yaml", "pins: [", false},
+	}
+	for _, p := range patterns {
+		t.Run(p.name, func(t *testing.T) {
+			cfg, err := config.Load(writeConfig(t, p.name, p.content))
+			if !p.ok {
+				assert.NotNil(t, err)
+				assert.Nil(t, cfg)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, expected, cfg)
+		})
+	}
+	_, err := config.Load(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.True(t, os.IsNotExist(err))
+}
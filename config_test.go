@@ -0,0 +1,76 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && hardware
+// +build linux,hardware
+
+// Test suite for config module.
+//
+// Tests use J8 pin 7.
+package gpio_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/warthog618/gpio"
+)
+
+func writeConfig(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.Nil(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	expected := &gpio.Config{Pins: []gpio.PinConfig{
+		{Name: "button", Pin: gpio.J8p7, Mode: "input", Pull: "up", Watch: "falling"},
+	}}
+	path := writeConfig(t, "pins.json",
+		`{"pins":[{"name":"button","pin":4,"mode":"input","pull":"up","watch":"falling"}]}`)
+	cfg, err := gpio.LoadConfig(path)
+	assert.Nil(t, err)
+	assert.Equal(t, expected, cfg)
+	path = writeConfig(t, "bad.json", "{")
+	_, err = gpio.LoadConfig(path)
+	assert.NotNil(t, err)
+}
+
+func TestApplyConfig(t *testing.T) {
+	setupDIO(t)
+	defer teardownDIO()
+	patterns := []struct {
+		name string
+		pc   gpio.PinConfig
+	}{
+		{"no name", gpio.PinConfig{Pin: gpio.J8p7}},
+		{"invalid pin", gpio.PinConfig{Name: "p", Pin: gpio.MaxGPIOPin}},
+		{"invalid mode", gpio.PinConfig{Name: "p", Pin: gpio.J8p7, Mode: "alt6"}},
+		{"invalid pull", gpio.PinConfig{Name: "p", Pin: gpio.J8p7, Pull: "sideways"}},
+		{"invalid level", gpio.PinConfig{Name: "p", Pin: gpio.J8p7, Level: "mid"}},
+		{"invalid watch", gpio.PinConfig{Name: "p", Pin: gpio.J8p7, Watch: "edgy"}},
+		{"no handler", gpio.PinConfig{Name: "p", Pin: gpio.J8p7, Watch: "both"}},
+		{"watched output", gpio.PinConfig{Name: "p", Pin: gpio.J8p7, Mode: "output", Watch: "both"}},
+	}
+	for _, p := range patterns {
+		pins, err := gpio.ApplyConfig(&gpio.Config{Pins: []gpio.PinConfig{p.pc}})
+		assert.NotNil(t, err, p.name)
+		assert.Nil(t, pins, p.name)
+	}
+	cfg := &gpio.Config{Pins: []gpio.PinConfig{
+		{Name: "button", Pin: gpio.J8p7, Mode: "input", Pull: "up"},
+	}}
+	pins, err := gpio.ApplyConfig(cfg)
+	assert.Nil(t, err)
+	require.Contains(t, pins, "button")
+	pin := pins["button"]
+	assert.Equal(t, gpio.J8p7, pin.Pin())
+	assert.Equal(t, gpio.Input, pin.Mode())
+	assert.Equal(t, gpio.PullUp, pin.Pull())
+	assert.Equal(t, gpio.High, pin.Read())
+}
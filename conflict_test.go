@@ -0,0 +1,49 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Test suite for pin conflicts between drivers.
+//
+// Tests do not use any hardware, as the registers are emulated.
+package gpio_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/warthog618/gpio"
+	"github.com/warthog618/gpio/led"
+	"github.com/warthog618/gpio/spi/mcp3w0c"
+)
+
+func TestDriverConflict(t *testing.T) {
+	defer gpio.Emulate(gpio.BCM2835)()
+	l, err := led.New(gpio.J8p7)
+	require.Nil(t, err)
+
+	// SPI ADC with its chip select on the LED pin
+	adc, err := mcp3w0c.Open(time.Microsecond, gpio.J8p23, gpio.J8p7, gpio.J8p19, gpio.J8p21, 10)
+	assert.Nil(t, adc)
+	var ce *gpio.ConflictError
+	require.ErrorAs(t, err, &ce)
+	assert.ErrorIs(t, err, gpio.ErrBusy)
+	assert.Equal(t, "led", ce.Owner)
+	assert.Equal(t, "spi", ce.Consumer)
+	// none of the ADC pins are left requested
+	assert.Equal(t, "", gpio.NewPin(gpio.J8p23).Consumer())
+	l.Close()
+
+	// and the reverse, with tied data pins
+	adc, err = mcp3w0c.Open(time.Microsecond, gpio.J8p23, gpio.J8p7, gpio.J8p19, gpio.J8p19, 10)
+	require.Nil(t, err)
+	l, err = led.New(gpio.J8p7)
+	assert.Nil(t, l)
+	require.ErrorAs(t, err, &ce)
+	assert.Equal(t, "spi", ce.Owner)
+	assert.Equal(t, "led", ce.Consumer)
+	adc.Close()
+	assert.Empty(t, gpio.Requested())
+}
@@ -0,0 +1,78 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Edge counting for DIO Pins.
+
+package gpio
+
+import (
+	"sync/atomic"
+)
+
+// EnableCounter counts the edges on the pin in the default Watcher.
+//
+// The edges are counted by the Watcher itself, rather than being delivered to
+// a handler, so the cost of each edge is an atomic increment.  This suits high
+// rate signals, such as from flow meters and encoders, where a goroutine per
+// event is too costly.
+//
+// The count is read using Counter, and the counter is removed by Unwatch.
+// Returns ErrBusy if the pin already has a counter.
+func (p *Pin) EnableCounter(edge Edge) error {
+	watcher, err := getDefaultWatcher()
+	if err != nil {
+		return err
+	}
+	watcher.Lock()
+	wt := watcher.counter(p.pin)
+	watcher.Unlock()
+	if wt != nil {
+		return pinError("count", p.pin, ErrBusy)
+	}
+	_, err = watcher.register(p, edge, nil, false, []WatchOption{counting})
+	return err
+}
+
+// Counter returns the number of edges counted on the pin since the counter
+// was enabled or last reset, and a function to reset the counter.
+//
+// The reset only deducts the returned count, so edges counted after the read
+// are not lost.
+// Returns 0 if the pin does not have a counter.
+func (p *Pin) Counter() (uint64, func()) {
+	memlock.Lock()
+	watcher := defaultWatcher
+	memlock.Unlock()
+	if watcher == nil {
+		return 0, func() {}
+	}
+	watcher.Lock()
+	wt := watcher.counter(p.pin)
+	watcher.Unlock()
+	if wt == nil {
+		return 0, func() {}
+	}
+	n := atomic.LoadUint64(&wt.count)
+	return n, func() { atomic.AddUint64(&wt.count, -n) }
+}
+
+// counter returns the counting watch on the pin, or nil if there is none.
+//
+// Assumes the caller holds the lock.
+func (w *Watcher) counter(pin int) *Watch {
+	if irq, ok := w.interrupts[pin]; ok {
+		for _, wt := range irq.watches {
+			if wt.counting {
+				return wt
+			}
+		}
+	}
+	return nil
+}
+
+// counting configures a watch to count edges rather than call a handler.
+func counting(wt *Watch) {
+	wt.counting = true
+}
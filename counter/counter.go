@@ -0,0 +1,248 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package counter counts the edges on a pin and measures their frequency over
+// gate intervals, as for anemometers, flow meters and fan tachometers.
+package counter
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/warthog618/gpio"
+)
+
+// Stats are the statistics of the edges seen by a Counter.
+type Stats struct {
+	// The number of edges since the Counter was created or reset.
+	Total uint64
+
+	// The number of edges in the last complete gate interval.
+	Edges uint64
+
+	// The frequency of the edges in the last complete gate interval, in Hz.
+	Frequency float64
+
+	// The statistics of the periods between the edges in the last complete
+	// gate interval, with Jitter being the standard deviation.
+	//
+	// These are zero if fewer than two edges were seen in the interval.
+	MinPeriod  time.Duration
+	MaxPeriod  time.Duration
+	MeanPeriod time.Duration
+	Jitter     time.Duration
+}
+
+// Counter counts the edges on a pin.
+//
+// The edges are timestamped by a Watcher, so the periods are not affected by
+// the latency of handling them, though edges closer together than the
+// interrupt latency may be lost.
+type Counter struct {
+	pin     *gpio.Pin
+	edge    gpio.Edge
+	gate    time.Duration
+	watcher *gpio.Watcher
+
+	// Guards the following.
+	mu     sync.Mutex
+	synced bool
+	total  uint64
+	// accumulators for the current gate interval.
+	gateStart time.Time
+	edges     uint64
+	last      time.Time
+	periods   int
+	min, max  time.Duration
+	sum       float64
+	sumSq     float64
+	// statistics from the last complete gate interval.
+	stats Stats
+
+	// closed to stop the gate goroutine.
+	stopCh chan struct{}
+	// closed when the gate goroutine exits.
+	doneCh chan struct{}
+}
+
+// Option modifies the configuration of a Counter.
+type Option func(*Counter)
+
+// WithEdge sets the edge counted.
+//
+// The default is EdgeRising.
+func WithEdge(edge gpio.Edge) Option {
+	return func(c *Counter) {
+		c.edge = edge
+	}
+}
+
+// WithGate sets the gate interval over which the frequency is measured.
+//
+// The default is 1s.
+func WithGate(d time.Duration) Option {
+	return func(c *Counter) {
+		if d > 0 {
+			c.gate = d
+		}
+	}
+}
+
+// New creates a Counter on the pin and starts counting.
+//
+// The pin is set to an input and watched by a Watcher private to the Counter,
+// and is requested until the Counter is closed.
+// The GPIO must be open.
+func New(pin int, options ...Option) (*Counter, error) {
+	c := &Counter{
+		pin:    gpio.NewPin(pin),
+		edge:   gpio.EdgeRising,
+		gate:   time.Second,
+		stopCh: make(chan struct{}),
+		doneCh: make(chan struct{}),
+	}
+	if c.pin == nil {
+		return nil, ErrInvalidPin
+	}
+	if err := c.pin.Request("counter"); err != nil {
+		return nil, err
+	}
+	for _, option := range options {
+		option(c)
+	}
+	// serial dispatch so edges are handled in order, and none are dropped.
+	w, err := gpio.NewWatcher(gpio.WithSerialDispatch())
+	if err != nil {
+		c.pin.Release()
+		return nil, err
+	}
+	c.watcher = w
+	c.pin.Input()
+	c.gateStart = time.Now()
+	if _, err = w.AddWatch(c.pin, c.edge, c.handler); err != nil {
+		w.Close()
+		c.pin.Release()
+		return nil, err
+	}
+	go c.run()
+	return c, nil
+}
+
+// Close stops counting.
+func (c *Counter) Close() {
+	c.watcher.Close()
+	close(c.stopCh)
+	<-c.doneCh
+	c.pin.Release()
+}
+
+// Count returns the number of edges since the Counter was created or reset.
+func (c *Counter) Count() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.total
+}
+
+// Stats returns the statistics of the edges.
+func (c *Counter) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := c.stats
+	s.Total = c.total
+	return s
+}
+
+// Reset zeroes the count and statistics, and restarts the gate interval.
+func (c *Counter) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.total = 0
+	c.stats = Stats{}
+	c.resetGate(time.Now())
+}
+
+func (c *Counter) handler(evt gpio.Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.synced {
+		// ignore the initial sync event from the watch.
+		c.synced = true
+		return
+	}
+	c.total++
+	c.edges++
+	if !c.last.IsZero() {
+		p := evt.Time.Sub(c.last)
+		if c.min == 0 || p < c.min {
+			c.min = p
+		}
+		if p > c.max {
+			c.max = p
+		}
+		c.periods++
+		c.sum += float64(p)
+		c.sumSq += float64(p) * float64(p)
+	}
+	c.last = evt.Time
+}
+
+// run closes the gate at the end of each interval.
+func (c *Counter) run() {
+	defer close(c.doneCh)
+	ticker := time.NewTicker(c.gate)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			c.closeGate(now)
+		case <-c.stopCh:
+			return
+		}
+	}
+}
+
+// closeGate records the statistics of the gate interval ending now, and
+// starts the next.
+func (c *Counter) closeGate(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s := Stats{Edges: c.edges}
+	if elapsed := now.Sub(c.gateStart); elapsed > 0 {
+		s.Frequency = float64(c.edges) / elapsed.Seconds()
+	}
+	if n := c.periods; n > 0 {
+		mean := c.sum / float64(n)
+		s.MinPeriod = c.min
+		s.MaxPeriod = c.max
+		s.MeanPeriod = time.Duration(mean)
+		if v := c.sumSq/float64(n) - mean*mean; v > 0 {
+			s.Jitter = time.Duration(math.Sqrt(v))
+		}
+	}
+	c.stats = s
+	// periods spanning gates are attributed to the later gate.
+	last := c.last
+	c.resetGate(now)
+	c.last = last
+}
+
+// resetGate starts a new gate interval.
+func (c *Counter) resetGate(now time.Time) {
+	c.gateStart = now
+	c.edges = 0
+	c.last = time.Time{}
+	c.periods = 0
+	c.min = 0
+	c.max = 0
+	c.sum = 0
+	c.sumSq = 0
+}
+
+var (
+	// ErrInvalidPin indicates the pin is not a valid GPIO pin.
+	ErrInvalidPin = errors.New("invalid pin")
+)
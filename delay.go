@@ -0,0 +1,96 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Precise delays.
+
+package gpio
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	calibrateOnce sync.Once
+
+	// sleepLatency is the observed overshoot of a short sleep.
+	//
+	// Delays, or the remainder of delays, shorter than this are busy waited.
+	sleepLatency time.Duration
+)
+
+// Delay waits for the duration.
+//
+// Sleeps have a granularity of around 100µs under the default kernel, which
+// is too coarse for bit bashing protocols, so Delay sleeps for as much of the
+// duration as it can without overshooting, then busy waits for the remainder.
+//
+// The sleep latency is calibrated on the first call, which may take up to a
+// millisecond longer than requested.
+//
+// As it busy waits, Delay consumes a CPU for short delays, and it may be
+// delayed by preemption like any other goroutine.
+func Delay(d time.Duration) {
+	delayUntil(time.Now().Add(d))
+}
+
+// DelayMicros waits for n microseconds, as per Delay.
+func DelayMicros(n int) {
+	Delay(time.Duration(n) * time.Microsecond)
+}
+
+// Pulse drives the pin to the level for the width, then to the opposite level.
+//
+// The width is timed as per Delay, so is accurate for widths well below the
+// sleep granularity.  The pin should be an output.
+func (pin *Pin) Pulse(level Level, width time.Duration) {
+	pin.Write(level)
+	delayUntil(time.Now().Add(width))
+	pin.Write(!level)
+}
+
+// Strobe generates n pulses on the pin, each of the width and separated by
+// the width, such as to clock a latch or shift register.
+//
+// The pulses are the opposite of the current level of the pin, which is
+// restored after each pulse.  The edges are timed from the start of the
+// strobe, so delays in one pulse do not accumulate over the strobe.
+// The pin should be an output.
+func (pin *Pin) Strobe(n int, width time.Duration) {
+	idle := pin.Shadow()
+	next := time.Now()
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			delayUntil(next)
+		}
+		pin.Write(!idle)
+		next = next.Add(width)
+		delayUntil(next)
+		pin.Write(idle)
+		next = next.Add(width)
+	}
+}
+
+// delayUntil waits until the deadline, as per Delay.
+func delayUntil(deadline time.Time) {
+	calibrateOnce.Do(calibrateDelay)
+	if s := time.Until(deadline) - sleepLatency; s > 0 {
+		time.Sleep(s)
+	}
+	for time.Now().Before(deadline) {
+	}
+}
+
+// calibrateDelay determines the sleepLatency from the worst of several short
+// sleeps.
+func calibrateDelay() {
+	for i := 0; i < 5; i++ {
+		start := time.Now()
+		time.Sleep(time.Microsecond)
+		if d := time.Since(start); d > sleepLatency {
+			sleepLatency = d
+		}
+	}
+}
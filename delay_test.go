@@ -0,0 +1,39 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Test suite for delay module.
+//
+// Tests do not use any pins.
+package gpio_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warthog618/gpio"
+)
+
+func TestDelay(t *testing.T) {
+	// calibrate
+	gpio.Delay(0)
+	patterns := []time.Duration{
+		0,
+		time.Microsecond,
+		10 * time.Microsecond,
+		500 * time.Microsecond,
+		5 * time.Millisecond,
+	}
+	for _, d := range patterns {
+		start := time.Now()
+		gpio.Delay(d)
+		elapsed := time.Since(start)
+		assert.GreaterOrEqual(t, int64(elapsed), int64(d), d)
+		assert.Less(t, int64(elapsed), int64(d+5*time.Millisecond), d)
+	}
+	start := time.Now()
+	gpio.DelayMicros(50)
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(50*time.Microsecond))
+}
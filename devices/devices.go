@@ -0,0 +1,264 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package devices composes the device drivers, such as buttons, LEDs, relays
+// and ADCs, into a named set, declared by a single structure.
+//
+// The set checks that no pin is used by more than one device before any are
+// created, and opens the GPIO, creates the devices, and closes them again in
+// a consistent order.
+package devices
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/warthog618/gpio"
+	"github.com/warthog618/gpio/adc"
+	"github.com/warthog618/gpio/button"
+	"github.com/warthog618/gpio/led"
+	"github.com/warthog618/gpio/relay"
+)
+
+// Spec declares the devices in a set, by name.
+//
+// Names need only be unique within each kind of device.
+type Spec struct {
+	Relays  map[string]RelaySpec
+	LEDs    map[string]LEDSpec
+	ADCs    map[string]ADCSpec
+	Buttons map[string]ButtonSpec
+}
+
+// RelaySpec declares a relay.
+type RelaySpec struct {
+	Pin     int
+	Options []relay.Option
+}
+
+// LEDSpec declares an LED.
+type LEDSpec struct {
+	Pin     int
+	Options []led.Option
+}
+
+// ButtonSpec declares a button.
+type ButtonSpec struct {
+	Pin     int
+	Options []button.Option
+}
+
+// ADC is an ADC that can be closed, such as those provided by the adc0832
+// and mcp3w0c packages.
+type ADC interface {
+	adc.Reader
+	Close()
+}
+
+// ADCSpec declares an ADC.
+type ADCSpec struct {
+	// The pins used by the ADC, for conflict detection.
+	//
+	// For an ADC on a shared SPI bus, only the chip select should be listed,
+	// as the bus pins are shared by design.
+	Pins []int
+
+	// New creates the ADC.  It is called with the GPIO open.
+	New func() (ADC, error)
+}
+
+// Set is a set of devices created from a Spec.
+type Set struct {
+	relays  map[string]*relay.Relay
+	leds    map[string]*led.LED
+	adcs    map[string]ADC
+	buttons map[string]*button.Button
+
+	// the Close of each device, in the order created.
+	closers []func()
+
+	// Guards the following.
+	mu     sync.Mutex
+	closed bool
+}
+
+// New opens the GPIO and creates the devices declared by the spec.
+//
+// Returns an error wrapping ErrPinConflict, and creates no devices, if a pin
+// is used by more than one device.
+//
+// The outputs, relays and then LEDs, are created first, so they are driven to
+// their inactive levels as early as possible, then the ADCs, and finally the
+// buttons, so button handlers are only called once the other devices exist.
+// Devices of the same kind are created in name order.
+// If any device cannot be created, the devices already created are closed,
+// and the GPIO is closed.
+func New(spec Spec) (*Set, error) {
+	s := &Set{
+		relays:  map[string]*relay.Relay{},
+		leds:    map[string]*led.LED{},
+		adcs:    map[string]ADC{},
+		buttons: map[string]*button.Button{},
+	}
+	entries := s.entries(spec)
+	if err := checkConflicts(entries); err != nil {
+		return nil, err
+	}
+	if err := gpio.Open(); err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		closer, err := e.create()
+		if err != nil {
+			s.Close()
+			return nil, fmt.Errorf("%s: %w", e, err)
+		}
+		s.closers = append(s.closers, closer)
+	}
+	return s, nil
+}
+
+// Close closes the devices, in the reverse of the order they were created,
+// and then closes the GPIO.
+func (s *Set) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	for i := len(s.closers) - 1; i >= 0; i-- {
+		s.closers[i]()
+	}
+	gpio.Close()
+}
+
+// Relay returns the named relay, or nil if there is no such relay.
+func (s *Set) Relay(name string) *relay.Relay {
+	return s.relays[name]
+}
+
+// LED returns the named LED, or nil if there is no such LED.
+func (s *Set) LED(name string) *led.LED {
+	return s.leds[name]
+}
+
+// ADC returns the named ADC, or nil if there is no such ADC.
+func (s *Set) ADC(name string) ADC {
+	return s.adcs[name]
+}
+
+// Button returns the named button, or nil if there is no such button.
+func (s *Set) Button(name string) *button.Button {
+	return s.buttons[name]
+}
+
+// The kinds of device, in the order they are created.
+const (
+	kindRelay = iota
+	kindLED
+	kindADC
+	kindButton
+)
+
+var kindNames = [...]string{"relay", "led", "adc", "button"}
+
+// entry is a device to be created.
+type entry struct {
+	kind int
+	name string
+	pins []int
+	// creates the device, adds it to the set, and returns its Close.
+	create func() (func(), error)
+}
+
+func (e entry) String() string {
+	return fmt.Sprintf("%s %q", kindNames[e.kind], e.name)
+}
+
+// entries returns the devices declared by the spec, in the order they are to
+// be created.
+func (s *Set) entries(spec Spec) []entry {
+	var ee []entry
+	for name, rs := range spec.Relays {
+		name, rs := name, rs
+		ee = append(ee, entry{kindRelay, name, []int{rs.Pin}, func() (func(), error) {
+			r, err := relay.New(rs.Pin, rs.Options...)
+			if err != nil {
+				return nil, err
+			}
+			s.relays[name] = r
+			return r.Close, nil
+		}})
+	}
+	for name, ls := range spec.LEDs {
+		name, ls := name, ls
+		ee = append(ee, entry{kindLED, name, []int{ls.Pin}, func() (func(), error) {
+			l, err := led.New(ls.Pin, ls.Options...)
+			if err != nil {
+				return nil, err
+			}
+			s.leds[name] = l
+			return l.Close, nil
+		}})
+	}
+	for name, as := range spec.ADCs {
+		name, as := name, as
+		ee = append(ee, entry{kindADC, name, as.Pins, func() (func(), error) {
+			if as.New == nil {
+				return nil, ErrInvalidSpec
+			}
+			a, err := as.New()
+			if err != nil {
+				return nil, err
+			}
+			s.adcs[name] = a
+			return a.Close, nil
+		}})
+	}
+	for name, bs := range spec.Buttons {
+		name, bs := name, bs
+		ee = append(ee, entry{kindButton, name, []int{bs.Pin}, func() (func(), error) {
+			b, err := button.New(bs.Pin, bs.Options...)
+			if err != nil {
+				return nil, err
+			}
+			s.buttons[name] = b
+			return b.Close, nil
+		}})
+	}
+	sort.Slice(ee, func(i, j int) bool {
+		if ee[i].kind != ee[j].kind {
+			return ee[i].kind < ee[j].kind
+		}
+		return ee[i].name < ee[j].name
+	})
+	return ee
+}
+
+// checkConflicts returns an error if a pin is used by more than one device.
+func checkConflicts(entries []entry) error {
+	users := map[int]entry{}
+	for _, e := range entries {
+		for _, pin := range e.pins {
+			if u, ok := users[pin]; ok {
+				return fmt.Errorf("GPIO%d used by %s and %s: %w", pin, u, e, ErrPinConflict)
+			}
+			users[pin] = e
+		}
+	}
+	return nil
+}
+
+var (
+	// ErrPinConflict indicates a pin is used by more than one device.
+	ErrPinConflict = errors.New("pin conflict")
+
+	// ErrInvalidSpec indicates a device is not fully specified, such as an
+	// ADC without a New function.
+	ErrInvalidSpec = errors.New("invalid spec")
+)
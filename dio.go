@@ -38,6 +38,7 @@
 package gpio
 
 import (
+	"sync/atomic"
 	"time"
 )
 
@@ -54,6 +55,13 @@ type Pin struct {
 	mask        uint32
 	// Mutable fields
 	shadow Level
+	pull   Pull
+	// the openGen when the pin was created or revalidated.
+	gen uint32
+	// non-zero once the pin has been closed.
+	closed int32
+	// true if the pin is included in OpenPins.
+	tracked bool
 }
 
 // Level represents the high (true) or low (false) level of a Pin.
@@ -67,6 +75,8 @@ type Pull int
 
 const (
 	memLength = 4096
+	// offset of the GPIO registers from the peripheral base.
+	gpioOffset = 0x200000
 
 	modeMask uint32 = 7 // pin mode is 3 bits wide
 	pullMask uint32 = 3 // pull mode is 2 bits wide
@@ -98,6 +108,10 @@ const (
 	PullNone Pull = iota
 	PullDown
 	PullUp
+
+	// PullUnknown indicates the pull has not been set via the API and cannot
+	// be read back from hardware.
+	PullUnknown Pull = -1
 )
 
 // Convenience mapping from J8 pinouts to BCM pinouts.
@@ -165,7 +179,28 @@ const (
 
 // NewPin creates a new pin object.
 // The pin number provided is the BCM GPIO number.
+//
+// The pin is not included in OpenPins - use NewTrackedPin for that.
 func NewPin(pin int) *Pin {
+	return newPin(pin)
+}
+
+// NewTrackedPin creates a new pin object, as per NewPin, that is included in
+// OpenPins until it is closed, or the GPIO is closed.
+//
+// Tracked pins are referenced by OpenPins, so must be closed once they are no
+// longer required.
+func NewTrackedPin(pin int) *Pin {
+	p := newPin(pin)
+	if p != nil {
+		p.tracked = true
+		registerPin(p)
+	}
+	return p
+}
+
+// newPin creates a new pin object.
+func newPin(pin int) *Pin {
 	if len(mem) == 0 {
 		panic("GPIO not initialised.")
 	}
@@ -199,7 +234,7 @@ func NewPin(pin int) *Pin {
 		shadow = High
 	}
 
-	return &Pin{
+	p := &Pin{
 		pin:         pin,
 		fsel:        fsel,
 		bank:        bank,
@@ -209,7 +244,13 @@ func NewPin(pin int) *Pin {
 		pullReg2711: pullReg,
 		setReg:      setReg,
 		shadow:      shadow,
+		pull:        PullUnknown,
+		gen:         atomic.LoadUint32(&openGen),
+	}
+	if chipset == BCM2711 {
+		p.pull = p.pull2711()
 	}
+	return p
 }
 
 // Input sets pin as Input.
@@ -244,6 +285,15 @@ func (pin *Pin) Shadow() Level {
 	return pin.shadow
 }
 
+// Pull returns the pull up/down state of the pin.
+//
+// On the BCM2711 this is initially read from hardware, but elsewhere it is
+// PullUnknown until the pull is set via SetPull.
+// Thereafter it is the value of the last SetPull.
+func (pin *Pin) Pull() Pull {
+	return pin.pull
+}
+
 // Pin returns the pin number that this Pin represents.
 func (pin *Pin) Pin() int {
 	return pin.pin
@@ -259,7 +309,34 @@ func (pin *Pin) Toggle() {
 }
 
 // SetMode sets the pin Mode.
+//
+// Has no effect if the pin is closed, stale, or protected, other than logging
+// the error to the Logger, if any - use TrySetMode to detect that.
 func (pin *Pin) SetMode(mode Mode) {
+	pin.TrySetMode(mode)
+}
+
+// TrySetMode sets the pin Mode.
+//
+// Returns ErrPinClosed, ErrNotOpen, or ErrProtected, and leaves the mode
+// unchanged, if the pin is closed, stale, or protected.
+func (pin *Pin) TrySetMode(mode Mode) error {
+	if err := pin.usable(); err != nil {
+		return pinError("mode", pin.pin, err)
+	}
+	if Protected(pin.pin) {
+		return pinError("mode", pin.pin, ErrProtected)
+	}
+	pin.setMode(mode)
+	return nil
+}
+
+// setMode sets the pin Mode, without checking the pin is usable.
+func (pin *Pin) setMode(mode Mode) {
+	pin.touch()
+	if l := getLogger(); l != nil {
+		l.Printf("GPIO%d: mode %s", pin.pin, mode)
+	}
 	// shift for pin mode field within fsel register.
 	modeShift := uint(pin.pin%10) * 3
 
@@ -270,7 +347,12 @@ func (pin *Pin) SetMode(mode Mode) {
 }
 
 // Read pin state (high/low)
+//
+// Returns Low if the pin is closed.
 func (pin *Pin) Read() (level Level) {
+	if pin.usable() != nil {
+		return Low
+	}
 	if (mem[pin.levelReg] & pin.mask) != 0 {
 		level = High
 	}
@@ -278,26 +360,94 @@ func (pin *Pin) Read() (level Level) {
 	return
 }
 
+// ReadAll returns the levels of GPIO0 to GPIO31, sampled together, with GPIO0
+// in the least significant bit.
+//
+// The shadows of the pins are not updated.
+func ReadAll() uint32 {
+	return atomic.LoadUint32(&mem[13])
+}
+
+// ReadBank returns the levels of the pins in the bank, sampled together, with
+// the lowest numbered pin in the least significant bit.
+//
+// Bank 0 contains GPIO0 to GPIO31, and bank 1 contains GPIO32 to GPIO53.
+// Returns 0 if the bank is invalid.
+// The shadows of the pins are not updated.
+func ReadBank(bank int) uint32 {
+	if bank < 0 || bank > 1 {
+		return 0
+	}
+	// loaded atomically, as per level.
+	return atomic.LoadUint32(&mem[13+bank])
+}
+
+// level returns the current pin level without updating the shadow.
+//
+// The level register is loaded atomically, as it is read by polling Watchers
+// concurrently with other operations.
+func (pin *Pin) level() Level {
+	return atomic.LoadUint32(&mem[pin.levelReg])&pin.mask != 0
+}
+
 // Set pin state (high/low)
+//
+// Has no effect if the pin is closed, stale, or protected, other than logging
+// the error to the Logger, if any - use TryWrite to detect that.
 func (pin *Pin) Write(level Level) {
+	pin.TryWrite(level)
+}
+
+// TryWrite sets the pin state (high/low).
+//
+// Returns ErrPinClosed, ErrNotOpen, or ErrProtected, and leaves the pin
+// unchanged, if the pin is closed, stale, or protected.
+func (pin *Pin) TryWrite(level Level) error {
+	if err := pin.usable(); err != nil {
+		return pinError("write", pin.pin, err)
+	}
+	if Protected(pin.pin) {
+		return pinError("write", pin.pin, ErrProtected)
+	}
+	pin.touch()
 	if level == Low {
 		mem[pin.clearReg] = pin.mask
 	} else {
 		mem[pin.setReg] = pin.mask
 	}
 	pin.shadow = level
+	return nil
 }
 
-// SetPull sets the pull up/down mode for a Pin.
-// Unlike the mode, the pull value cannot be read back from hardware and
-// so must be remembered by the caller.
-func (pin *Pin) SetPull(pull Pull) {
+// SetPull sets the pull up/down mode for a Pin, and returns the previous pull.
+//
+// Unlike the mode, the pull value cannot be read back from the BCM2835 and
+// so is shadowed in the Pin.  Setting PullUnknown has no effect.
+// Nor does setting the pull of a pin that is closed, stale, or protected,
+// other than logging the error to the Logger, if any.
+func (pin *Pin) SetPull(pull Pull) Pull {
+	prev := pin.pull
+	switch pull {
+	case PullNone, PullDown, PullUp:
+	default:
+		return prev
+	}
+	if err := pin.usable(); err != nil {
+		pinError("pull", pin.pin, err)
+		return prev
+	}
+	if Protected(pin.pin) {
+		pinError("pull", pin.pin, ErrProtected)
+		return prev
+	}
 	switch chipset {
 	case BCM2711:
 		pin.setPull2711(pull)
 	default:
 		pin.setPull2835(pull)
 	}
+	pin.pull = pull
+	return prev
 }
 
 func (pin *Pin) setPull2835(pull Pull) {
@@ -331,6 +481,19 @@ func (pin *Pin) setPull2711(pull Pull) {
 	mem[pin.pullReg2711] = mem[pin.pullReg2711]&^(pullMask<<shift) | uint32(pull)<<shift
 }
 
+func (pin *Pin) pull2711() Pull {
+	shift := uint(pin.pin&0x0f) << 1
+	pull := Pull(mem[pin.pullReg2711] >> shift & pullMask)
+	// 2711 reverses up/down sense
+	switch pull {
+	case PullUp:
+		pull = PullDown
+	case PullDown:
+		pull = PullUp
+	}
+	return pull
+}
+
 // PullUp sets the pull state of the pin to PullUp.
 func (pin *Pin) PullUp() {
 	pin.SetPull(PullUp)
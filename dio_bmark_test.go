@@ -3,6 +3,9 @@
 // Use of this source code is governed by an MIT-style
 // license that can be found in the LICENSE file.
 
+//go:build linux && hardware
+// +build linux,hardware
+
 //
 //  Test suite for dio module.
 //
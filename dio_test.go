@@ -3,6 +3,9 @@
 // Use of this source code is governed by an MIT-style
 // license that can be found in the LICENSE file.
 
+//go:build linux && hardware
+// +build linux,hardware
+
 //
 //  Test suite for dio module.
 //
@@ -58,6 +61,23 @@ func TestRead(t *testing.T) {
 	assert.Equal(t, gpio.High, pin.Read())
 }
 
+func TestReadAll(t *testing.T) {
+	setupDIO(t)
+	defer teardownDIO()
+	mask := uint32(1) << uint(gpio.J8p7)
+	pin := gpio.NewPin(gpio.J8p7)
+	pin.PullDown()
+	time.Sleep(time.Microsecond)
+	assert.Zero(t, gpio.ReadAll()&mask)
+	assert.Zero(t, gpio.ReadBank(0)&mask)
+	pin.PullUp()
+	time.Sleep(time.Microsecond)
+	assert.Equal(t, mask, gpio.ReadAll()&mask)
+	assert.Equal(t, mask, gpio.ReadBank(0)&mask)
+	assert.Zero(t, gpio.ReadBank(-1))
+	assert.Zero(t, gpio.ReadBank(2))
+}
+
 func TestMode(t *testing.T) {
 	setupDIO(t)
 	defer teardownDIO()
@@ -98,6 +118,31 @@ func TestPull(t *testing.T) {
 	pin.PullNone()
 }
 
+func TestPullShadow(t *testing.T) {
+	setupDIO(t)
+	defer teardownDIO()
+	pin := gpio.NewPin(gpio.J8p7)
+	defer pin.PullUp()
+	if gpio.Chip() == gpio.BCM2711 {
+		// Assumes pin is initially pulled up.
+		assert.Equal(t, gpio.PullUp, pin.Pull())
+	} else {
+		assert.Equal(t, gpio.PullUnknown, pin.Pull())
+	}
+	pin.PullDown()
+	assert.Equal(t, gpio.PullDown, pin.Pull())
+	assert.Equal(t, gpio.PullDown, pin.SetPull(gpio.PullNone))
+	assert.Equal(t, gpio.PullNone, pin.Pull())
+	assert.Equal(t, gpio.PullNone, pin.SetPull(gpio.PullUnknown))
+	assert.Equal(t, gpio.PullNone, pin.Pull())
+	assert.Equal(t, gpio.PullNone, pin.SetPull(gpio.PullUp))
+	assert.Equal(t, gpio.PullUp, pin.Pull())
+	if gpio.Chip() == gpio.BCM2711 {
+		// readback from hardware
+		assert.Equal(t, gpio.PullUp, gpio.NewPin(gpio.J8p7).Pull())
+	}
+}
+
 func TestPin(t *testing.T) {
 	setupDIO(t)
 	defer teardownDIO()
@@ -0,0 +1,389 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package dma drives GPIO outputs from a cyclic waveform using the BCM283x DMA
+// controller, paced by the PWM peripheral, so the outputs are free of the
+// jitter introduced by the Go scheduler and the kernel.
+//
+// The waveform is a ring of samples, each a tick long.  Each sample has a
+// mask of pins to set and a mask of pins to clear at the start of the sample.
+// This is sufficient for multi-channel servo and LED PWM outputs, as per
+// pigpio and ServoBlaster.
+//
+// The DMA controller and PWM peripheral are accessed via /dev/mem, and the
+// waveform memory is allocated from the VideoCore via /dev/vcio, so root
+// privileges are required.
+// The PWM peripheral is used for pacing, so it cannot be used for other
+// purposes, including analog audio, while the waveform is running.
+package dma
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/warthog618/gpio"
+	"github.com/warthog618/gpio/internal/periph"
+)
+
+// Peripheral register block offsets from the peripheral base.
+const (
+	dmaOffset  = 0x007000
+	cmOffset   = 0x101000
+	gpioOffset = 0x200000
+	pwmOffset  = 0x20c000
+)
+
+// DMA channel register indices.
+const (
+	dmaCS       = 0
+	dmaConblkAd = 1
+	dmaDebug    = 8
+	// the global enable register, relative to the DMA block.
+	dmaEnable = 0xff0 / 4
+	// registers per channel.
+	dmaChannelRegs = 0x100 / 4
+)
+
+// DMA CS bits.
+const (
+	csActive                   = 1 << 0
+	csEnd                      = 1 << 1
+	csInt                      = 1 << 2
+	csPriority                 = 15 << 16
+	csPanicPriority            = 15 << 20
+	csWaitForOutstandingWrites = 1 << 28
+	csReset                    = 1 << 31
+)
+
+// DMA transfer information bits.
+const (
+	tiWaitResp     = 1 << 3
+	tiDestDreq     = 1 << 6
+	tiPermapPWM    = 5 << 16
+	tiNoWideBursts = 1 << 26
+)
+
+// PWM register indices and bits.
+const (
+	pwmCTL   = 0
+	pwmDMAC  = 2
+	pwmRNG1  = 4
+	pwmFIF1  = 6
+	ctlPWEN1 = 1 << 0
+	ctlUSEF1 = 1 << 5
+	ctlCLRF1 = 1 << 6
+	dmacENAB = 1 << 31
+	// DREQ and PANIC thresholds.
+	dmacThresholds = 15<<8 | 15
+)
+
+// Clock manager register indices and bits.
+const (
+	cmPWMCTL   = 0xa0 / 4
+	cmPWMDIV   = 0xa4 / 4
+	cmPasswd   = 0x5a << 24
+	cmBusy     = 1 << 7
+	cmEnable   = 1 << 4
+	cmSrcPLLD  = 6
+	pwmClockHz = 10000000
+)
+
+// GPIO register byte offsets.
+const (
+	gpset0 = 0x1c
+	gpclr0 = 0x28
+)
+
+// Allocation flags.
+const (
+	// uncached, for the BCM2836 and later.
+	memFlagDirect = 0x04
+	// uncached, for the BCM2835.
+	memFlagL1Nonallocating = 0x0c
+)
+
+const pageSize = 4096
+
+// control block words, and words per sample.
+const (
+	cbWords = 8
+	// set GPIO, clear GPIO, then pace.
+	cbsPerSample = 3
+)
+
+var (
+	// ErrInvalidChannel indicates the DMA channel is out of range.
+	ErrInvalidChannel = errors.New("invalid DMA channel")
+
+	// ErrInvalidPin indicates the pin cannot be driven by the waveform.
+	// Only pins 0-31 are supported.
+	ErrInvalidPin = errors.New("invalid pin")
+
+	// ErrInvalidTick indicates the tick is outside the supported range.
+	ErrInvalidTick = errors.New("invalid tick")
+
+	// ErrMailbox indicates a mailbox request to the VideoCore failed.
+	ErrMailbox = errors.New("mailbox request failed")
+
+	// ErrInvalidSamples indicates the number of samples is not positive.
+	ErrInvalidSamples = errors.New("invalid number of samples")
+)
+
+// Waveform is a cyclic waveform output on GPIO pins by DMA.
+type Waveform struct {
+	channel int
+	samples int
+	// the tick in PWM clock cycles.
+	rng uint32
+	// the PWM clock source frequency.
+	plld uint32
+
+	mem  *vcMem
+	dmaB *periph.Block
+	pwm  *periph.Block
+	cm   *periph.Block
+	// the channel registers.
+	regs []uint32
+
+	// Guards the sample masks and running.
+	mu      sync.Mutex
+	running bool
+}
+
+// New creates a Waveform of the given number of samples, each a tick long,
+// using the DMA channel.
+//
+// The channel must not be in use by the kernel or other applications.
+// Channels 5 and 6 are generally free on current Raspberry Pi OS kernels.
+// The tick must be between 1µs and 1ms.
+//
+// gpio.Open must have been called, as the chipset determines the memory
+// and clock configuration.  The pins driven by the waveform must be set to
+// outputs by the caller.
+func New(channel int, tick time.Duration, samples int) (*Waveform, error) {
+	if channel < 0 || channel > 14 {
+		return nil, ErrInvalidChannel
+	}
+	if tick < time.Microsecond || tick > time.Millisecond {
+		return nil, ErrInvalidTick
+	}
+	if samples < 1 {
+		return nil, ErrInvalidSamples
+	}
+	flags := uint32(memFlagDirect)
+	plld := uint32(500000000)
+	switch gpio.Chip() {
+	case gpio.BCM2711:
+		plld = 750000000
+	case gpio.BCM2835:
+		if isBCM2835() {
+			flags = memFlagL1Nonallocating
+		}
+	}
+	w := &Waveform{
+		channel: channel,
+		samples: samples,
+		rng:     uint32(tick * pwmClockHz / time.Second),
+		plld:    plld,
+	}
+	var err error
+	if w.mem, err = allocVCMem(samples*(cbsPerSample*cbWords+2)*4, flags); err != nil {
+		return nil, err
+	}
+	if w.dmaB, err = periph.Map(dmaOffset, pageSize); err != nil {
+		w.Close()
+		return nil, err
+	}
+	if w.pwm, err = periph.Map(pwmOffset, pageSize); err != nil {
+		w.Close()
+		return nil, err
+	}
+	if w.cm, err = periph.Map(cmOffset, pageSize); err != nil {
+		w.Close()
+		return nil, err
+	}
+	w.regs = w.dmaB.Regs[channel*dmaChannelRegs : (channel+1)*dmaChannelRegs]
+	w.initControlBlocks()
+	return w, nil
+}
+
+// isBCM2835 returns true if the SoC is the original BCM2835, rather than one
+// of the later chips sharing its GPIO block.
+func isBCM2835() bool {
+	base, err := periph.Base()
+	return err == nil && base == 0x20000000
+}
+
+// setMask returns the index of the set mask for the sample.
+func (w *Waveform) setMask(sample int) int {
+	return w.samples*cbsPerSample*cbWords + 2*sample
+}
+
+// clearMask returns the index of the clear mask for the sample.
+func (w *Waveform) clearMask(sample int) int {
+	return w.setMask(sample) + 1
+}
+
+// cb returns the index of the control block.
+func (w *Waveform) cb(n int) int {
+	return (n % (w.samples * cbsPerSample)) * cbWords
+}
+
+func (w *Waveform) initControlBlocks() {
+	regs := w.mem.Regs
+	gpioBus := uint32(periph.BusBase + gpioOffset)
+	fifo := uint32(periph.BusBase + pwmOffset + pwmFIF1*4)
+	for s := 0; s < w.samples; s++ {
+		regs[w.setMask(s)] = 0
+		regs[w.clearMask(s)] = 0
+		n := s * cbsPerSample
+		cbs := [cbsPerSample][3]uint32{
+			{tiNoWideBursts | tiWaitResp, w.mem.busAddr(w.setMask(s)), gpioBus + gpset0},
+			{tiNoWideBursts | tiWaitResp, w.mem.busAddr(w.clearMask(s)), gpioBus + gpclr0},
+			// the content is irrelevant - the write is only for pacing.
+			{tiNoWideBursts | tiWaitResp | tiDestDreq | tiPermapPWM, w.mem.busAddr(w.setMask(s)), fifo},
+		}
+		for i, c := range cbs {
+			cb := regs[w.cb(n+i):]
+			cb[0] = c[0]
+			cb[1] = c[1]
+			cb[2] = c[2]
+			cb[3] = 4
+			cb[4] = 0
+			cb[5] = w.mem.busAddr(w.cb(n + i + 1))
+			cb[6] = 0
+			cb[7] = 0
+		}
+	}
+}
+
+// SetSample sets the masks of the pins set and cleared at the start of the
+// sample.
+func (w *Waveform) SetSample(sample int, set, clear uint32) {
+	w.mu.Lock()
+	w.mem.Regs[w.setMask(sample%w.samples)] = set
+	w.mem.Regs[w.clearMask(sample%w.samples)] = clear
+	w.mu.Unlock()
+}
+
+// SetPulse sets the pin high for width samples each cycle, starting at the
+// start sample, replacing any previous pulse on the pin.
+//
+// A width of 0 holds the pin low, and a width of the number of samples holds
+// it high.
+func (w *Waveform) SetPulse(pin, start, width int) error {
+	if pin < 0 || pin > 31 {
+		return ErrInvalidPin
+	}
+	mask := uint32(1) << uint(pin)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	regs := w.mem.Regs
+	for s := 0; s < w.samples; s++ {
+		regs[w.setMask(s)] &^= mask
+		regs[w.clearMask(s)] &^= mask
+	}
+	start %= w.samples
+	switch {
+	case width <= 0:
+		regs[w.clearMask(start)] |= mask
+	case width >= w.samples:
+		regs[w.setMask(start)] |= mask
+	default:
+		regs[w.setMask(start)] |= mask
+		regs[w.clearMask((start+width)%w.samples)] |= mask
+	}
+	return nil
+}
+
+// ClearPin removes the pin from the waveform, leaving it at its current
+// level.
+func (w *Waveform) ClearPin(pin int) error {
+	if pin < 0 || pin > 31 {
+		return ErrInvalidPin
+	}
+	mask := uint32(1) << uint(pin)
+	w.mu.Lock()
+	for s := 0; s < w.samples; s++ {
+		w.mem.Regs[w.setMask(s)] &^= mask
+		w.mem.Regs[w.clearMask(s)] &^= mask
+	}
+	w.mu.Unlock()
+	return nil
+}
+
+// Start starts outputting the waveform.
+//
+// Has no effect if the waveform is already running.
+func (w *Waveform) Start() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.running {
+		return
+	}
+	w.running = true
+	pwm := w.pwm.Regs
+	cm := w.cm.Regs
+
+	// stop the PWM and its clock.
+	pwm[pwmCTL] = 0
+	time.Sleep(10 * time.Microsecond)
+	cm[cmPWMCTL] = cmPasswd | cmSrcPLLD
+	for cm[cmPWMCTL]&cmBusy != 0 {
+		time.Sleep(10 * time.Microsecond)
+	}
+	cm[cmPWMDIV] = cmPasswd | (w.plld/pwmClockHz)<<12
+	cm[cmPWMCTL] = cmPasswd | cmEnable | cmSrcPLLD
+	time.Sleep(100 * time.Microsecond)
+
+	// the PWM requests a FIFO write every tick.
+	pwm[pwmRNG1] = w.rng
+	pwm[pwmCTL] = ctlCLRF1
+	time.Sleep(10 * time.Microsecond)
+	pwm[pwmDMAC] = dmacENAB | dmacThresholds
+	pwm[pwmCTL] = ctlUSEF1 | ctlPWEN1
+
+	w.dmaB.Regs[dmaEnable] |= 1 << uint(w.channel)
+	w.regs[dmaCS] = csReset
+	time.Sleep(10 * time.Microsecond)
+	w.regs[dmaCS] = csInt | csEnd
+	w.regs[dmaConblkAd] = w.mem.busAddr(0)
+	// clear any errors.
+	w.regs[dmaDebug] = 7
+	w.regs[dmaCS] = csWaitForOutstandingWrites | csPanicPriority | csPriority | csActive
+}
+
+// Stop stops outputting the waveform, leaving the pins at their current
+// levels.
+func (w *Waveform) Stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.running {
+		return
+	}
+	w.running = false
+	w.regs[dmaCS] = csReset
+	time.Sleep(10 * time.Microsecond)
+	w.pwm.Regs[pwmCTL] = 0
+}
+
+// Close stops the waveform and releases its resources.
+func (w *Waveform) Close() error {
+	if w.regs != nil {
+		w.Stop()
+	}
+	for _, b := range []*periph.Block{w.cm, w.pwm, w.dmaB} {
+		if b != nil {
+			b.Close()
+		}
+	}
+	if w.mem != nil {
+		w.mem.free()
+		w.mem = nil
+	}
+	w.regs = nil
+	return nil
+}
@@ -0,0 +1,105 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build linux
+// +build linux
+
+package dma
+
+import (
+	"os"
+	"unsafe"
+
+	"github.com/warthog618/gpio/internal/periph"
+	"golang.org/x/sys/unix"
+)
+
+// Mailbox property tags for the VideoCore memory allocator.
+const (
+	tagAllocate = 0x3000c
+	tagLock     = 0x3000d
+	tagUnlock   = 0x3000e
+	tagRelease  = 0x3000f
+)
+
+// ioctlProperty is _IOWR(100, 0, char *).
+const ioctlProperty = 0xc0006400 | uintptr(unsafe.Sizeof(uintptr(0)))<<16
+
+// vcMem is a block of uncached memory allocated from the VideoCore, and
+// mapped into the process.
+type vcMem struct {
+	*periph.Block
+	mbox   *os.File
+	handle uint32
+	// the address of the block as seen by the DMA controller.
+	bus uint32
+}
+
+// allocVCMem allocates and maps a block of uncached memory at least size
+// bytes long.
+func allocVCMem(size int, flags uint32) (*vcMem, error) {
+	size = (size + pageSize - 1) &^ (pageSize - 1)
+	mbox, err := os.OpenFile("/dev/vcio", os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	m := &vcMem{mbox: mbox}
+	if m.handle, err = m.property(tagAllocate, uint32(size), pageSize, flags); err != nil {
+		m.free()
+		return nil, err
+	}
+	if m.bus, err = m.property(tagLock, m.handle); err != nil {
+		m.free()
+		return nil, err
+	}
+	if m.Block, err = periph.MapPhys(int64(m.bus&^0xc0000000), size); err != nil {
+		m.free()
+		return nil, err
+	}
+	return m, nil
+}
+
+// busAddr returns the bus address of the word at the index.
+func (m *vcMem) busAddr(index int) uint32 {
+	return m.bus + uint32(index*4)
+}
+
+// free unmaps and releases the memory.
+func (m *vcMem) free() {
+	if m.Block != nil {
+		m.Block.Close()
+	}
+	if m.bus != 0 {
+		m.property(tagUnlock, m.handle)
+	}
+	if m.handle != 0 {
+		m.property(tagRelease, m.handle)
+	}
+	m.mbox.Close()
+}
+
+// property performs a mailbox property request with a single tag, and
+// returns the first word of the response.
+func (m *vcMem) property(tag uint32, args ...uint32) (uint32, error) {
+	n := len(args)
+	if n < 1 {
+		n = 1
+	}
+	buf := make([]uint32, 6+n)
+	buf[0] = uint32(len(buf) * 4)
+	buf[2] = tag
+	buf[3] = uint32(n * 4)
+	buf[4] = uint32(len(args) * 4)
+	copy(buf[5:], args)
+	_, _, errno := unix.Syscall(unix.SYS_IOCTL, m.mbox.Fd(), ioctlProperty,
+		uintptr(unsafe.Pointer(&buf[0])))
+	if errno != 0 {
+		return 0, errno
+	}
+	if buf[1] != 0x80000000 {
+		return 0, ErrMailbox
+	}
+	return buf[5], nil
+}
@@ -0,0 +1,33 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+// +build !linux
+
+package dma
+
+import (
+	"github.com/warthog618/gpio"
+	"github.com/warthog618/gpio/internal/periph"
+)
+
+// vcMem is a stub of the VideoCore memory, for other platforms.
+type vcMem struct {
+	*periph.Block
+	bus uint32
+}
+
+// allocVCMem returns gpio.ErrNotSupported, as the VideoCore mailbox is only
+// supported on Linux, so New fails.
+func allocVCMem(size int, flags uint32) (*vcMem, error) {
+	return nil, gpio.ErrNotSupported
+}
+
+func (m *vcMem) busAddr(index int) uint32 {
+	return m.bus + uint32(index*4)
+}
+
+func (m *vcMem) free() {
+}
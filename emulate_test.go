@@ -0,0 +1,807 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Test suite for the core logic, using emulated registers.
+//
+// Tests do not use any hardware, so can be run on any machine.
+// The tests that require a Raspberry Pi are built with the hardware tag.
+package gpio
+
+import (
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// emulate replaces the GPIO memory with emulated registers, as if Open had
+// been called on the chipset, and returns a function that restores them.
+//
+// Writes to the set and clear registers are not reflected in the level
+// registers, so tests set the level registers directly to emulate inputs.
+func emulate(chip Chipset) func() {
+	memlock.Lock()
+	defer memlock.Unlock()
+	oldMem, oldChip := mem, chipset
+	mem = make([]uint32, memLength/4)
+	chipset = chip
+	return func() {
+		memlock.Lock()
+		defer memlock.Unlock()
+		mem, chipset = oldMem, oldChip
+	}
+}
+
+// setLevels sets the levels of GPIO0 to GPIO31, as if driven externally.
+//
+// The level register is stored atomically, as it is read concurrently by
+// polling Watchers.
+func setLevels(levels uint32) {
+	atomic.StoreUint32(&mem[13], levels)
+}
+
+// Emulate is emulate, exported for the external tests of this package.
+//
+// Test files are not visible to other packages, so the drivers test their
+// decoders directly, without the emulator.
+var Emulate = emulate
+
+func TestEmulatedMode(t *testing.T) {
+	defer emulate(BCM2835)()
+	patterns := []struct {
+		pin   int
+		fsel  int
+		shift uint
+	}{
+		{GPIO4, 0, 12},
+		{GPIO9, 0, 27},
+		{GPIO10, 1, 0},
+		{GPIO20, 2, 0},
+		{GPIO27, 2, 21},
+	}
+	for _, p := range patterns {
+		pin := NewPin(p.pin)
+		for _, mode := range []Mode{Output, Alt0, Alt5, Input} {
+			pin.SetMode(mode)
+			assert.Equal(t, uint32(mode)<<p.shift, mem[p.fsel], p.pin)
+			assert.Equal(t, mode, pin.Mode(), p.pin)
+		}
+	}
+	// neighbours are unaffected
+	NewPin(GPIO10).SetMode(Alt3)
+	NewPin(GPIO11).SetMode(Output)
+	NewPin(GPIO10).SetMode(Input)
+	assert.Equal(t, uint32(Output)<<3, mem[1])
+}
+
+func TestEmulatedWrite(t *testing.T) {
+	defer emulate(BCM2835)()
+	pin := NewPin(GPIO17)
+	pin.High()
+	assert.Equal(t, uint32(1)<<17, mem[7])
+	assert.Zero(t, mem[10])
+	assert.Equal(t, High, pin.Shadow())
+	pin.Low()
+	assert.Equal(t, uint32(1)<<17, mem[10])
+	assert.Equal(t, Low, pin.Shadow())
+
+	mem[7], mem[10] = 0, 0
+	pin.Toggle()
+	assert.Equal(t, uint32(1)<<17, mem[7])
+	pin.Toggle()
+	assert.Equal(t, uint32(1)<<17, mem[10])
+
+	// protected by default
+	mem[7] = 0
+	NewPin(GPIO14).High()
+	assert.Zero(t, mem[7])
+}
+
+func TestEmulatedRead(t *testing.T) {
+	defer emulate(BCM2835)()
+	pin := NewPin(GPIO22)
+	assert.Equal(t, Low, pin.Read())
+	setLevels(1 << 22)
+	assert.Equal(t, High, pin.Read())
+	assert.Equal(t, High, pin.Shadow())
+	assert.Equal(t, uint32(1)<<22, ReadAll())
+
+	mem[14] = 1 << 3
+	assert.Equal(t, uint32(1)<<3, ReadBank(1))
+	assert.Zero(t, ReadBank(2))
+}
+
+func TestEmulatedPulse(t *testing.T) {
+	defer emulate(BCM2835)()
+	pin := NewPin(GPIO17)
+	start := time.Now()
+	pin.Pulse(High, 50*time.Microsecond)
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(50*time.Microsecond))
+	assert.Equal(t, uint32(1)<<17, mem[7])
+	assert.Equal(t, uint32(1)<<17, mem[10])
+	assert.Equal(t, Low, pin.Shadow())
+
+	// active low strobe
+	pin.High()
+	mem[7], mem[10] = 0, 0
+	start = time.Now()
+	pin.Strobe(3, 20*time.Microsecond)
+	assert.GreaterOrEqual(t, int64(time.Since(start)), int64(100*time.Microsecond))
+	assert.Equal(t, uint32(1)<<17, mem[7])
+	assert.Equal(t, uint32(1)<<17, mem[10])
+	assert.Equal(t, High, pin.Shadow())
+}
+
+func TestEmulatedPull2711(t *testing.T) {
+	defer emulate(BCM2711)()
+	patterns := []struct {
+		pin   int
+		reg   int
+		shift uint
+	}{
+		{GPIO4, 57, 8},
+		{GPIO13, 57, 26},
+		{GPIO16, 58, 0},
+		{GPIO27, 58, 22},
+	}
+	for _, p := range patterns {
+		pin := NewPin(p.pin)
+		// the 2711 reverses the up/down sense
+		pin.PullUp()
+		assert.Equal(t, uint32(PullDown)<<p.shift, mem[p.reg], p.pin)
+		assert.Equal(t, PullUp, pin.pull2711(), p.pin)
+		pin.PullDown()
+		assert.Equal(t, uint32(PullUp)<<p.shift, mem[p.reg], p.pin)
+		assert.Equal(t, PullDown, pin.pull2711(), p.pin)
+		pin.PullNone()
+		assert.Zero(t, mem[p.reg], p.pin)
+		assert.Equal(t, PullNone, pin.Pull(), p.pin)
+	}
+	// invalid pulls are ignored
+	pin := NewPin(GPIO4)
+	pin.PullUp()
+	assert.Equal(t, PullUp, pin.SetPull(Pull(3)))
+	assert.Equal(t, PullUp, pin.Pull())
+}
+
+func TestEmulatedPull2835(t *testing.T) {
+	defer emulate(BCM2835)()
+	pin := NewPin(GPIO4)
+	pin.PullUp()
+	// the pull and clock registers are cleared once the pull is clocked in.
+	assert.Zero(t, mem[pullReg2835])
+	assert.Zero(t, mem[38])
+	assert.Equal(t, PullUp, pin.Pull())
+	// the 2711 registers are untouched.
+	assert.Zero(t, mem[57])
+}
+
+func TestEmulatedPollWatcher(t *testing.T) {
+	defer emulate(BCM2835)()
+	w, err := NewPollWatcher(time.Millisecond)
+	if err == ErrNotSupported {
+		t.Skip(err)
+	}
+	require.Nil(t, err)
+	defer w.Close()
+	pin := NewPin(GPIO23)
+	events := make(chan Event, 10)
+	wt, err := w.AddWatch(pin, EdgeRising, func(evt Event) { events <- evt })
+	require.Nil(t, err)
+	assert.Equal(t, WatchPoll, wt.Mechanism())
+	// initial level
+	evt := <-events
+	assert.Equal(t, Low, evt.Level)
+
+	setLevels(1 << 23)
+	select {
+	case evt = <-events:
+		assert.Equal(t, High, evt.Level)
+		assert.Equal(t, pin, evt.Pin)
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("missed rising edge")
+	}
+
+	// falling edges are filtered
+	setLevels(0)
+	select {
+	case evt = <-events:
+		t.Fatal("unexpected event", evt)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	// bookkeeping
+	pins := w.Pins()
+	assert.Equal(t, []int{GPIO23}, pins)
+	assert.ErrorIs(t, w.RegisterPin(pin, EdgeBoth, func(*Pin) {}), ErrBusy)
+	wt.Unwatch()
+	assert.Empty(t, w.Pins())
+	assert.ErrorIs(t, wt.SetEdge(EdgeBoth), ErrNotWatched)
+}
+
+func TestEmulatedMaxRate(t *testing.T) {
+	defer emulate(BCM2835)()
+	w, err := NewPollWatcher(time.Millisecond)
+	if err == ErrNotSupported {
+		t.Skip(err)
+	}
+	require.Nil(t, err)
+	defer w.Close()
+	pin := NewPin(GPIO23)
+	events := make(chan Event, 10)
+	_, err = w.AddWatch(pin, EdgeBoth, func(evt Event) { events <- evt }, WithMaxRate(1))
+	require.Nil(t, err)
+	// initial level is never throttled
+	evt := <-events
+	assert.Equal(t, Low, evt.Level)
+
+	setLevels(1 << 23)
+	time.Sleep(20 * time.Millisecond)
+	setLevels(0)
+	select {
+	case evt = <-events:
+		t.Fatal("unexpected event", evt)
+	case <-time.After(20 * time.Millisecond):
+	}
+	s := w.Stats()[GPIO23]
+	assert.Equal(t, uint64(3), s.Events)
+	assert.Equal(t, uint64(2), s.Throttled)
+	assert.Zero(t, s.Dropped)
+}
+
+func TestEmulatedWaitForEdge(t *testing.T) {
+	defer emulate(BCM2835)()
+	pin := NewPin(GPIO23)
+	_, err := pin.WaitForEdge(EdgeRising, 20*time.Millisecond)
+	if err == ErrNotSupported {
+		t.Skip(err)
+	}
+	assert.ErrorIs(t, err, ErrTimeout)
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		setLevels(1 << 23)
+	}()
+	evt, err := pin.WaitForEdge(EdgeRising, time.Second)
+	require.Nil(t, err)
+	assert.Equal(t, High, evt.Level)
+	assert.Equal(t, pin, evt.Pin)
+}
+
+func TestEmulatedGroupWatch(t *testing.T) {
+	defer emulate(BCM2835)()
+	w, err := NewPollWatcher(time.Millisecond)
+	if err == ErrNotSupported {
+		t.Skip(err)
+	}
+	require.Nil(t, err)
+	defer w.Close()
+	g := NewPinGroup(GPIO22, GPIO23, GPIO24)
+	matches := make(chan uint, 10)
+	gw, err := w.AddGroupWatch(g, 0x3, 0x1, 10*time.Millisecond, func(v uint) { matches <- v })
+	require.Nil(t, err)
+	defer gw.Unwatch()
+
+	// initial levels don't match
+	select {
+	case v := <-matches:
+		t.Fatal("unexpected match", v)
+	case <-time.After(30 * time.Millisecond):
+	}
+
+	// a glitch through the matching state is debounced
+	setLevels(1<<22 | 1<<23)
+	time.Sleep(3 * time.Millisecond)
+	setLevels(1 << 22)
+	time.Sleep(3 * time.Millisecond)
+	setLevels(1 << 23)
+	select {
+	case v := <-matches:
+		t.Fatal("unexpected match", v)
+	case <-time.After(30 * time.Millisecond):
+	}
+
+	// unmasked pins are ignored
+	setLevels(1<<22 | 1<<24)
+	select {
+	case v := <-matches:
+		assert.Equal(t, uint(0x5), v)
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("missed match")
+	}
+}
+
+func TestEmulatedCounter(t *testing.T) {
+	defer emulate(BCM2835)()
+	pin := NewPin(GPIO23)
+	n, reset := pin.Counter()
+	assert.Zero(t, n)
+	reset()
+	err := pin.EnableCounter(EdgeRising)
+	if err == ErrNotSupported {
+		t.Skip(err)
+	}
+	require.Nil(t, err)
+	defer pin.Unwatch()
+	assert.ErrorIs(t, pin.EnableCounter(EdgeBoth), ErrBusy)
+
+	for i := 0; i < 3; i++ {
+		setLevels(1 << 23)
+		time.Sleep(5 * time.Millisecond)
+		setLevels(0)
+		time.Sleep(5 * time.Millisecond)
+	}
+	n, reset = pin.Counter()
+	assert.Equal(t, uint64(3), n)
+	reset()
+	n, _ = pin.Counter()
+	assert.Zero(t, n)
+
+	pin.Unwatch()
+	n, _ = pin.Counter()
+	assert.Zero(t, n)
+}
+
+func TestEmulatedPinClose(t *testing.T) {
+	defer emulate(BCM2835)()
+	assert.NotContains(t, OpenPins(), NewPin(GPIO23))
+	pin := NewTrackedPin(GPIO23)
+	assert.Contains(t, OpenPins(), pin)
+	require.Nil(t, pin.Request("test"))
+	err := pin.EnableCounter(EdgeRising)
+	if err == ErrNotSupported {
+		t.Skip(err)
+	}
+	require.Nil(t, err)
+	pin.Close()
+	assert.True(t, pin.Closed())
+	assert.NotContains(t, OpenPins(), pin)
+	assert.Equal(t, "", pin.Consumer())
+	assert.ErrorIs(t, pin.Request("test"), ErrPinClosed)
+	assert.ErrorIs(t, pin.EnableCounter(EdgeRising), ErrPinClosed)
+	assert.ErrorIs(t, pin.Revalidate(), ErrPinClosed)
+	pin.Close()
+
+	// the counter watch has been removed, so it may be enabled again.
+	pin = NewPin(GPIO23)
+	require.Nil(t, pin.EnableCounter(EdgeRising))
+	pin.Close()
+
+	pin = NewPin(GPIO24)
+	pin.High()
+	pin.Output()
+	pin.Close(WithRevertToInput())
+	assert.Equal(t, Input, pin.Mode())
+	assert.Equal(t, Low, pin.Shadow())
+
+	// closed pins are not read or written.
+	mem[pin.levelReg] = pin.mask
+	assert.Equal(t, Low, pin.Read())
+	mem[pin.setReg] = 0
+	pin.High()
+	assert.Zero(t, mem[pin.setReg])
+	assert.ErrorIs(t, pin.TryWrite(High), ErrPinClosed)
+	pin.Output()
+	assert.Equal(t, Input, pin.Mode())
+	assert.ErrorIs(t, pin.TrySetMode(Output), ErrPinClosed)
+}
+
+func TestEmulatedPinCloseUnusable(t *testing.T) {
+	defer emulate(BCM2711)()
+	g := NewPinGroup(GPIO4, GPIO17)
+	pin, other := g.Pins()[0], g.Pins()[1]
+	pin.PullUp()
+	assert.Equal(t, PullUp, pin.Pull())
+	pin.Close()
+	assert.Equal(t, PullUnknown, pin.Pull())
+
+	// closed pins are not pulled.
+	mem[57] = 0
+	pin.PullDown()
+	assert.Zero(t, mem[57])
+	assert.Equal(t, PullUnknown, pin.Pull())
+
+	// nor protected pins.
+	uart := NewPin(GPIO14)
+	uart.PullUp()
+	assert.Equal(t, PullNone, uart.Pull())
+	assert.Zero(t, mem[57])
+
+	// closed pins are ignored by groups.
+	setLevels(1<<GPIO4 | 1<<GPIO17)
+	assert.Equal(t, uint(2), g.Read())
+	assert.Equal(t, Low, pin.Shadow())
+	g.Write(3)
+	assert.Equal(t, other.mask, mem[other.setReg])
+	assert.Zero(t, mem[pin.clearReg])
+	mem[other.setReg] = 0
+	assert.ErrorIs(t, g.TryWrite(3), ErrPinClosed)
+	assert.Zero(t, mem[other.setReg])
+}
+
+func TestEmulatedRequestPins(t *testing.T) {
+	defer emulate(BCM2835)()
+	defer releaseAll()
+	adc := []*Pin{NewPin(GPIO8), NewPin(GPIO9), NewPin(GPIO10), NewPin(GPIO11)}
+	require.Nil(t, RequestPins("adc", adc...))
+	assert.Equal(t, "adc", adc[0].Consumer())
+
+	lcd := []*Pin{NewPin(GPIO7), NewPin(GPIO10)}
+	err := RequestPins("lcd", lcd...)
+	assert.ErrorIs(t, err, ErrBusy)
+	var ce *ConflictError
+	require.ErrorAs(t, err, &ce)
+	assert.Equal(t, &ConflictError{Owner: "adc", Consumer: "lcd"}, ce)
+	assert.Equal(t, `request GPIO10: pin already in use by "adc", requested by "lcd"`, err.Error())
+	// none of the pins are requested.
+	assert.Equal(t, "", lcd[0].Consumer())
+
+	// prior claims are restored.
+	require.Nil(t, lcd[0].Request("led"))
+	assert.ErrorIs(t, RequestPins("lcd", lcd...), ErrBusy)
+	assert.Equal(t, "led", lcd[0].Consumer())
+	assert.ErrorIs(t, RequestPins("lcd", lcd[0], NewPin(GPIO7)), ErrBusy)
+	assert.Equal(t, "led", lcd[0].Consumer())
+
+	ReleasePins(adc...)
+	assert.Nil(t, RequestPins("lcd", lcd...))
+	assert.Equal(t, "lcd", lcd[1].Consumer())
+}
+
+func TestEmulatedBits(t *testing.T) {
+	defer emulate(BCM2835)()
+	pins := []*Pin{NewPin(GPIO22), NewPin(GPIO23), NewPin(GPIO24)}
+	assert.Zero(t, ReadBits(pins, 0))
+	assert.Equal(t, uint(0x7), ReadBits(pins, ^uint(0)))
+	setLevels(1<<22 | 1<<24)
+	assert.Equal(t, uint(0x5), ReadBits(pins, 0))
+	assert.Equal(t, uint(0x4), ReadBits(pins, 0x1))
+
+	values := make(chan uint, 10)
+	bw, err := WatchBits(pins, 0x2, time.Millisecond, func(v uint) { values <- v })
+	if err == ErrNotSupported {
+		t.Skip(err)
+	}
+	require.Nil(t, err)
+	defer bw.Unwatch()
+	select {
+	case v := <-values:
+		assert.Equal(t, uint(0x7), v)
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("missed initial value")
+	}
+	setLevels(1 << 23)
+	select {
+	case v := <-values:
+		assert.Equal(t, uint(0x0), v)
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("missed change")
+	}
+}
+
+func TestDecodeBCD(t *testing.T) {
+	patterns := []struct {
+		in  uint
+		out uint
+	}{
+		{0, 0},
+		{0x9, 9},
+		{0x12, 12},
+		{0x905, 905},
+	}
+	for _, p := range patterns {
+		v, err := DecodeBCD(p.in)
+		assert.Nil(t, err, p.in)
+		assert.Equal(t, p.out, v, p.in)
+	}
+	_, err := DecodeBCD(0x1a)
+	assert.Equal(t, ErrInvalidBCD, err)
+}
+
+func TestEmulatedExternalLoop(t *testing.T) {
+	defer emulate(BCM2835)()
+	w, err := NewPollWatcher(time.Millisecond, WithExternalLoop(), WithSerialDispatch())
+	if err == ErrNotSupported {
+		t.Skip(err)
+	}
+	require.Nil(t, err)
+	assert.GreaterOrEqual(t, w.Fd(), 0)
+	pin := NewPin(GPIO23)
+	events := make(chan Event, 10)
+	_, err = w.AddWatch(pin, EdgeBoth, func(evt Event) { events <- evt })
+	require.Nil(t, err)
+	// initial level
+	evt := <-events
+	assert.Equal(t, Low, evt.Level)
+
+	// edges are only detected by Process
+	setLevels(1 << 23)
+	select {
+	case evt = <-events:
+		t.Fatal("unexpected event", evt)
+	case <-time.After(10 * time.Millisecond):
+	}
+	assert.Nil(t, w.Process())
+	select {
+	case evt = <-events:
+		assert.Equal(t, High, evt.Level)
+	case <-time.After(100 * time.Millisecond):
+		t.Fatal("missed rising edge")
+	}
+
+	w.Close()
+	assert.Equal(t, ErrClosed, w.Process())
+
+	w, err = NewPollWatcher(time.Millisecond)
+	require.Nil(t, err)
+	defer w.Close()
+	assert.Equal(t, ErrNotExternal, w.Process())
+}
+
+func TestClockDivisor(t *testing.T) {
+	patterns := []struct {
+		name string
+		chip Chipset
+		freq uint
+		src  uint32
+		div  uint32
+		err  error
+	}{
+		{"zero", BCM2835, 0, 0, 0, ErrInvalidFrequency},
+		{"too low", BCM2835, 1000, 0, 0, ErrInvalidFrequency},
+		{"osc integer", BCM2835, 10000, cmSrcOsc, 1920 << 12, nil},
+		{"osc fraction", BCM2835, 3000000, cmSrcOsc, 6<<12 | 1638, nil},
+		{"osc large remainder", BCM2835, 5000000, cmSrcOsc, 3<<12 | 3440, nil},
+		{"plld", BCM2835, 25000000, cmSrcPLLD, 20 << 12, nil},
+		{"2711 osc integer", BCM2711, 3000000, cmSrcOsc, 18 << 12, nil},
+		{"2711 osc fraction", BCM2711, 7000000, cmSrcOsc, 7<<12 | 2925, nil},
+	}
+	for _, p := range patterns {
+		t.Run(p.name, func(t *testing.T) {
+			defer emulate(p.chip)()
+			src, div, err := clockDivisor(p.freq)
+			assert.Equal(t, p.err, err)
+			assert.Equal(t, p.src, src)
+			assert.Equal(t, p.div, div)
+		})
+	}
+}
+
+func TestEmulatedStale(t *testing.T) {
+	defer emulate(BCM2711)()
+	pin := NewPin(GPIO4)
+	assert.False(t, pin.Stale())
+
+	// emulate Close
+	atomic.AddUint32(&openGen, 1)
+	assert.True(t, pin.Stale())
+	assert.ErrorIs(t, pin.Request("led"), ErrNotOpen)
+	assert.ErrorIs(t, pin.Revalidate(), ErrNotOpen)
+	w, err := NewPollWatcher(time.Millisecond)
+	if err == nil {
+		_, err = w.AddWatch(pin, EdgeBoth, func(Event) {})
+		assert.ErrorIs(t, err, ErrNotOpen)
+		w.Close()
+	}
+
+	// emulate reopen, with the hardware changed while closed.
+	openlock.Lock()
+	refs++
+	openlock.Unlock()
+	defer func() {
+		openlock.Lock()
+		refs--
+		openlock.Unlock()
+	}()
+	setLevels(1 << 4)
+	mem[57] = uint32(PullDown) << 8
+	assert.Nil(t, pin.Revalidate())
+	assert.False(t, pin.Stale())
+	assert.Equal(t, High, pin.Shadow())
+	assert.Equal(t, PullUp, pin.Pull())
+	assert.Nil(t, pin.Request("led"))
+	pin.Release()
+}
+
+func TestEmulatedTryAfterClose(t *testing.T) {
+	defer emulate(BCM2835)()
+	pin := NewPin(GPIO4)
+	g := NewPinGroup(GPIO4, GPIO17)
+
+	// emulate the final Close
+	openlock.Lock()
+	refs = 1
+	openlock.Unlock()
+	Close()
+	require.Empty(t, mem)
+
+	assert.ErrorIs(t, pin.TryWrite(High), ErrNotOpen)
+	assert.ErrorIs(t, pin.TrySetMode(Output), ErrNotOpen)
+	assert.ErrorIs(t, g.TryWrite(3), ErrNotOpen)
+	assert.NotPanics(t, func() {
+		pin.Write(High)
+		pin.SetMode(Output)
+	})
+}
+
+func TestToRegs(t *testing.T) {
+	buf := make([]uint32, memLength/4+1)
+	buf[0] = 0x12345678
+	buf[60] = 0x6770696f
+	mem8 := unsafe.Slice((*uint8)(unsafe.Pointer(&buf[0])), memLength+4)
+	regs, err := toRegs(mem8[:memLength])
+	require.Nil(t, err)
+	assert.Len(t, regs, memLength/4)
+	assert.Equal(t, uint32(0x12345678), regs[0])
+	assert.Equal(t, uint32(0x6770696f), regs[60])
+
+	// too short
+	_, err = toRegs(mem8[:minRegs*4-4])
+	assert.Equal(t, ErrInvalidMapping, err)
+	// partial register
+	_, err = toRegs(mem8[:memLength-1])
+	assert.Equal(t, ErrInvalidMapping, err)
+	// misaligned
+	_, err = toRegs(mem8[1 : memLength+1])
+	assert.Equal(t, ErrInvalidMapping, err)
+}
+
+func TestChipInfo(t *testing.T) {
+	assert.Equal(t, ChipInfo{Registers: 45, Base: 0x200000, NumPins: 54}, BCM2835.Info())
+	assert.Equal(t, ChipInfo{Registers: 61, Base: 0x200000, NumPins: 58}, BCM2711.Info())
+	unknown := Chipset(0).Info()
+	assert.Equal(t, minRegs, unknown.Registers)
+
+	// the mapping always covers the registers of all chipsets.
+	page := os.Getpagesize()
+	for _, c := range []Chipset{0, BCM2835, BCM2711} {
+		n := mapLength(c)
+		assert.GreaterOrEqual(t, n, minRegs*4)
+		assert.Zero(t, n%page)
+	}
+}
+
+func TestPCMWords(t *testing.T) {
+	assert.Empty(t, pcmWords(nil))
+	assert.Equal(t, []uint32{0x12345678}, pcmWords([]byte{0x12, 0x34, 0x56, 0x78}))
+	assert.Equal(t, []uint32{0x12345678, 0x9a000000},
+		pcmWords([]byte{0x12, 0x34, 0x56, 0x78, 0x9a}))
+}
+
+func TestEmulatedProtected(t *testing.T) {
+	defer emulate(BCM2835)()
+	defer atomic.StoreUint64(&protectedPins, atomic.LoadUint64(&protectedPins))
+	pin := NewPin(GPIO14)
+	other := NewPin(GPIO4)
+	g := NewPinGroup(GPIO4, GPIO14)
+
+	// protected by default
+	assert.ErrorIs(t, pin.TrySetMode(Output), ErrProtected)
+
+	Unprotect(GPIO14)
+	assert.Nil(t, pin.TrySetMode(Output))
+	assert.Equal(t, Output, pin.Mode())
+	assert.Nil(t, pin.TryWrite(High))
+	assert.Equal(t, pin.mask, mem[pin.setReg])
+	assert.Nil(t, g.TryWrite(3))
+
+	Protect(GPIO14)
+	mem[pin.setReg] = 0
+	mem[pin.clearReg] = 0
+	err := pin.TrySetMode(Input)
+	assert.ErrorIs(t, err, ErrProtected)
+	var pe *PinError
+	require.ErrorAs(t, err, &pe)
+	assert.Equal(t, GPIO14, pe.Pin)
+	assert.Equal(t, Output, pin.Mode())
+	r := &recorder{}
+	SetLogger(r)
+	pin.SetMode(Input)
+	SetLogger(nil)
+	assert.Equal(t, Output, pin.Mode())
+	assert.Equal(t, []string{"mode GPIO14: pin is protected"}, r.msgs)
+
+	assert.ErrorIs(t, pin.TryWrite(Low), ErrProtected)
+	pin.Write(Low)
+	assert.Zero(t, mem[pin.clearReg])
+
+	// group writes none if any pin is protected
+	assert.ErrorIs(t, g.TryWrite(0), ErrProtected)
+	assert.Zero(t, mem[other.clearReg])
+	// while Write skips the protected pin
+	g.Write(0)
+	assert.Equal(t, other.mask, mem[other.clearReg])
+	assert.Zero(t, mem[pin.clearReg]&pin.mask)
+}
+
+func TestEmulatedRealtimeReentrant(t *testing.T) {
+	defer emulate(BCM2835)()
+	w, err := NewPollWatcher(time.Millisecond, WithRealtime(1))
+	if err != nil {
+		t.Skip(err)
+	}
+	// not deferred, as closing a deadlocked Watcher would hang the test.
+	pin := NewPin(GPIO23)
+	first, err := w.AddWatch(pin, EdgeBoth, func(Event) {})
+	require.Nil(t, err)
+
+	// the initial event is dispatched directly, so the handler must be able
+	// to call back into the Watcher.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := w.AddWatch(pin, EdgeBoth, func(evt Event) {
+			first.Unwatch()
+			w.Stats()
+			w.Pins()
+			w.UnregisterPin(evt.Pin)
+		})
+		assert.Nil(t, err)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("deadlocked")
+	}
+	assert.Empty(t, w.Pins())
+	w.Close()
+}
+
+func TestEmulatedRealtimeCloseWatcher(t *testing.T) {
+	defer emulate(BCM2835)()
+	w, err := NewPollWatcher(time.Millisecond, WithRealtime(1))
+	if err != nil {
+		t.Skip(err)
+	}
+	memlock.Lock()
+	defaultWatcher = w
+	memlock.Unlock()
+	pin := NewPin(GPIO23)
+	entered := make(chan struct{})
+	_, err = pin.AddWatch(EdgeRising, func(evt Event) {
+		if evt.Level != High {
+			return
+		}
+		close(entered)
+		// give CloseWatcher time to start waiting for the watch goroutine.
+		time.Sleep(20 * time.Millisecond)
+		pin.SetMode(Input)
+		pin.PullUp()
+	})
+	require.Nil(t, err)
+	setLevels(1 << 23)
+	<-entered
+
+	// the handler must be able to take the memlock while the Watcher closes.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		CloseWatcher()
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("deadlocked")
+	}
+	setLevels(0)
+}
+
+func TestEmulatedMeasurePolled(t *testing.T) {
+	defer emulate(BCM2835)()
+	if sysfsAvailable() {
+		t.Skip("sysfs available")
+	}
+	pin := NewPin(GPIO23)
+	_, err := pin.MeasurePulse(time.Millisecond)
+	assert.ErrorIs(t, err, ErrNotSupported)
+	_, err = pin.Frequency(time.Millisecond)
+	assert.ErrorIs(t, err, ErrNotSupported)
+	var pe *PinError
+	assert.ErrorAs(t, err, &pe)
+}
@@ -0,0 +1,288 @@
+// Copyright © 2017 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Edge detection via epoll and the sysfs GPIO interface.
+
+//go:build linux
+// +build linux
+
+package gpio
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// openPoller creates the epoll and the pipe used for the shutdown handshake.
+func (w *Watcher) openPoller() error {
+	epfd, err := unix.EpollCreate1(0)
+	if err != nil {
+		return fmt.Errorf("unable to create epoll: %w", err)
+	}
+	p := []int{0, 0}
+	err = unix.Pipe2(p, unix.O_CLOEXEC)
+	if err != nil {
+		unix.Close(epfd)
+		return fmt.Errorf("unable to create pipe: %w", err)
+	}
+	epv := unix.EpollEvent{Events: unix.EPOLLIN, Fd: int32(p[0])}
+	err = unix.EpollCtl(epfd, unix.EPOLL_CTL_ADD, int(p[0]), &epv)
+	if err != nil {
+		unix.Close(epfd)
+		unix.Close(p[0])
+		unix.Close(p[1])
+		return fmt.Errorf("unable to add pipe to epoll: %w", err)
+	}
+	w.epfd = epfd
+	w.donefds = p
+	return nil
+}
+
+// wake signals the watch goroutine to exit.
+func (w *Watcher) wake() {
+	unix.Write(w.donefds[1], []byte("bye"))
+}
+
+// releasePoller releases the epoll and the read end of the shutdown pipe.
+func (w *Watcher) releasePoller() {
+	unix.Close(w.epfd)
+	unix.Close(w.donefds[0])
+}
+
+// kick wakes the watch goroutine so it re-evaluates whether to poll.
+//
+// Assumes the caller holds the lock.
+func (w *Watcher) kick() {
+	unix.Write(w.donefds[1], []byte("k"))
+}
+
+// closePoller releases the write end of the shutdown pipe, the remainder
+// being released by the watch goroutine.
+func (w *Watcher) closePoller() {
+	unix.Close(w.donefds[1])
+}
+
+// watch processes events until the watcher is closed.
+func (w *Watcher) watch() {
+	defer close(w.doneCh)
+	for w.process(true) {
+	}
+}
+
+// process waits for events and dispatches them, then polls any polled pins.
+//
+// If block is false then only events already pending are processed,
+// otherwise it waits for an event, or the poll period if pins are polled.
+// Returns false once the watcher is closed.
+func (w *Watcher) process(block bool) bool {
+	var epollEvents [MaxGPIOInterrupt]unix.EpollEvent
+	w.Lock()
+	polling := w.npolled > 0
+	w.Unlock()
+	timeout := 0
+	if block {
+		timeout = -1
+		if polling {
+			timeout = int((w.pollPeriod + time.Millisecond - 1) / time.Millisecond)
+		}
+	}
+	n, err := unix.EpollWait(w.epfd, epollEvents[:], timeout)
+	if err != nil {
+		if err == unix.EBADF || err == unix.EINVAL {
+			// fd closed so exit
+			return false
+		}
+		if err == unix.EINTR {
+			return true
+		}
+		panic(fmt.Sprintf("EpollWait error: %v", err))
+	}
+	now := time.Now()
+	for i := 0; i < n; i++ {
+		event := epollEvents[i]
+		if event.Fd == int32(w.donefds[0]) {
+			// drain any kicks, then exit if closed.
+			var buf [16]byte
+			unix.Read(w.donefds[0], buf[:])
+			w.Lock()
+			closed := w.closed
+			w.Unlock()
+			if !closed {
+				continue
+			}
+			if !w.external {
+				w.releasePoller()
+			}
+			return false
+		}
+		w.Lock()
+		irq, ok := w.fds[int(event.Fd)]
+		var t trigger
+		if ok {
+			// the first event is the initial sync and goes to all watches.
+			t = irq.trigger(Event{Level: irq.pin.level(), Time: now}, !irq.synced)
+			irq.synced = true
+		}
+		w.Unlock()
+		t.dispatch()
+	}
+	if polling {
+		w.poll(now)
+	}
+	return true
+}
+
+// addSysfs exports the pin and adds its value file to the epoll.
+//
+// Assumes the caller holds the lock.
+func (w *Watcher) addSysfs(irq *interrupt) (err error) {
+	pin := irq.pin
+	if !w.shareExports || !isExported(pin) {
+		if err = export(pin); err != nil {
+			return err
+		}
+		irq.exported = true
+	}
+	defer func() {
+		if err != nil && irq.exported {
+			unexport(pin)
+		}
+	}()
+	if err = setEdge(pin, irq.edge); err != nil {
+		return err
+	}
+	valueFile, err := openValue(pin)
+	if err != nil {
+		return err
+	}
+	pinFd := int(valueFile.Fd())
+
+	event := unix.EpollEvent{Events: unix.EPOLLET & 0xffffffff}
+	if err = unix.SetNonblock(pinFd, true); err != nil {
+		valueFile.Close()
+		return pinError("epoll", pin.pin, err)
+	}
+	event.Fd = int32(pinFd)
+	if err = unix.EpollCtl(w.epfd, unix.EPOLL_CTL_ADD, pinFd, &event); err != nil {
+		valueFile.Close()
+		return pinError("epoll", pin.pin, err)
+	}
+	irq.valueFile = valueFile
+	w.fds[pinFd] = irq
+	logf("GPIO%d: added to epoll", pin.pin)
+	return nil
+}
+
+// removeSysfs removes the pin value file from the epoll and unexports the pin.
+//
+// Assumes the caller holds the lock.
+func (w *Watcher) removeSysfs(irq *interrupt) {
+	pinFd := int(irq.valueFile.Fd())
+	delete(w.fds, pinFd)
+	unix.EpollCtl(w.epfd, unix.EPOLL_CTL_DEL, pinFd, nil)
+	unix.SetNonblock(pinFd, false)
+	logf("GPIO%d: removed from epoll", irq.pin.pin)
+	releaseSysfs(irq)
+}
+
+// releaseSysfs closes the pin value file and unexports the pin, if the
+// Watcher exported it.
+func releaseSysfs(irq *interrupt) {
+	irq.valueFile.Close()
+	if irq.exported {
+		unexport(irq.pin)
+	}
+}
+
+func sysfsAvailable() bool {
+	_, err := os.Stat("/sys/class/gpio/export")
+	return !os.IsNotExist(err)
+}
+
+func waitWriteable(path string) error {
+	try := 0
+	for unix.Access(path, unix.W_OK) != nil {
+		try++
+		if try > 10 {
+			return ErrTimeout
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return nil
+}
+
+func export(p *Pin) error {
+	file, err := os.OpenFile("/sys/class/gpio/export", os.O_WRONLY, os.ModeExclusive)
+	if err != nil {
+		return pinError("export", p.pin, err)
+	}
+	defer file.Close()
+	_, err = file.WriteString(strconv.Itoa(int(p.pin)))
+	if e, ok := err.(*os.PathError); ok && e.Err == unix.EBUSY {
+		return pinError("export", p.pin, &BusyError{Consumer: lineConsumer(p.pin)})
+	}
+	if err != nil {
+		return pinError("export", p.pin, err)
+	}
+	// wait for pin to be exported on sysfs - can take > 100ms on older Pis
+	if err = waitExported(p); err != nil {
+		return pinError("export", p.pin, err)
+	}
+	logf("GPIO%d: exported", p.pin)
+	return nil
+}
+
+// isExported returns true if the pin is already exported to sysfs.
+func isExported(p *Pin) bool {
+	_, err := os.Stat(fmt.Sprintf("/sys/class/gpio/gpio%v", p.pin))
+	return err == nil
+}
+
+func openValue(p *Pin) (*os.File, error) {
+	path := fmt.Sprintf("/sys/class/gpio/gpio%v/value", p.pin)
+	f, err := os.OpenFile(path, os.O_RDWR, os.ModeExclusive)
+	return f, pinError("open value", p.pin, err)
+}
+
+func setEdge(p *Pin, edge Edge) error {
+	path := fmt.Sprintf("/sys/class/gpio/gpio%v/edge", p.pin)
+	file, err := os.OpenFile(path, os.O_RDWR, os.ModeExclusive)
+	if err != nil {
+		return pinError("set edge", p.pin, err)
+	}
+	defer file.Close()
+	if _, err = file.Write([]byte(edge)); err != nil {
+		return pinError("set edge", p.pin, err)
+	}
+	logf("GPIO%d: edge %s", p.pin, edge)
+	return nil
+}
+
+func unexport(p *Pin) error {
+	file, err := os.OpenFile("/sys/class/gpio/unexport", os.O_WRONLY, os.ModeExclusive)
+	if err != nil {
+		return pinError("unexport", p.pin, err)
+	}
+	defer file.Close()
+	if _, err = file.WriteString(strconv.Itoa(int(p.pin))); err != nil {
+		return pinError("unexport", p.pin, err)
+	}
+	logf("GPIO%d: unexported", p.pin)
+	return nil
+}
+
+// Wait for the sysfs GPIO files to become writable.
+func waitExported(p *Pin) error {
+	path := fmt.Sprintf("/sys/class/gpio/gpio%v/value", p.pin)
+	if err := waitWriteable(path); err != nil {
+		return err
+	}
+	path = fmt.Sprintf("/sys/class/gpio/gpio%v/edge", p.pin)
+	return waitWriteable(path)
+}
@@ -0,0 +1,78 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && hardware
+// +build linux,hardware
+
+// Test suite for epoll module.
+//
+// Tests use Raspberry Pi J8 pins 15 and 16 which must be jumpered together.
+package gpio
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnexportOnClose(t *testing.T) {
+	pinIn, pinOut, watcher := setupIntr(t)
+	defer teardownIntr(pinIn, pinOut, watcher)
+	w, err := NewWatcher()
+	require.Nil(t, err)
+	wt, err := w.AddWatch(pinIn, EdgeBoth, func(Event) {})
+	require.Nil(t, err)
+	assert.Equal(t, WatchSysfs, wt.Mechanism())
+	assert.True(t, isExported(pinIn))
+	w.Close()
+	assert.False(t, isExported(pinIn))
+}
+
+func TestSharedExports(t *testing.T) {
+	pinIn, pinOut, watcher := setupIntr(t)
+	defer teardownIntr(pinIn, pinOut, watcher)
+	require.Nil(t, export(pinIn))
+	defer unexport(pinIn)
+
+	// not shared, so polled
+	w, err := NewWatcher()
+	require.Nil(t, err)
+	wt, err := w.AddWatch(pinIn, EdgeBoth, func(Event) {})
+	require.Nil(t, err)
+	assert.Equal(t, WatchPoll, wt.Mechanism())
+	assert.ErrorIs(t, wt.SysfsErr(), ErrBusy)
+	w.Close()
+	assert.True(t, isExported(pinIn))
+
+	// shared, so watched but left exported
+	w, err = NewWatcher(WithSharedExports())
+	require.Nil(t, err)
+	wt, err = w.AddWatch(pinIn, EdgeBoth, func(Event) {})
+	require.Nil(t, err)
+	assert.Equal(t, WatchSysfs, wt.Mechanism())
+	wt.Unwatch()
+	assert.True(t, isExported(pinIn))
+	w.Close()
+	assert.True(t, isExported(pinIn))
+}
+
+func TestDetach(t *testing.T) {
+	pinIn, pinOut, watcher := setupIntr(t)
+	defer teardownIntr(pinIn, pinOut, watcher)
+	w, err := NewWatcher()
+	require.Nil(t, err)
+	defer w.Close()
+	wt, err := w.AddWatch(pinIn, EdgeBoth, func(Event) {})
+	require.Nil(t, err)
+	assert.Equal(t, WatchSysfs, wt.Mechanism())
+	w.Detach(pinIn)
+	assert.Empty(t, w.Pins())
+	assert.True(t, isExported(pinIn))
+	unexport(pinIn)
+
+	// unwatched
+	w.Detach(pinIn)
+}
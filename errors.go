@@ -0,0 +1,47 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Errors identifying the pin and operation that failed.
+
+package gpio
+
+import (
+	"fmt"
+)
+
+// PinError records an error and the operation and pin that caused it.
+//
+// The underlying error, which may be one of the package errors, such as
+// ErrTimeout, or a syscall error, is available from Unwrap, so may be tested
+// with errors.Is and errors.As.
+type PinError struct {
+	// The operation that failed, such as "export" or "watch".
+	Op string
+
+	// The BCM GPIO number of the pin.
+	Pin int
+
+	// The underlying error.
+	Err error
+}
+
+func (e *PinError) Error() string {
+	return fmt.Sprintf("%s GPIO%d: %s", e.Op, e.Pin, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *PinError) Unwrap() error {
+	return e.Err
+}
+
+// pinError wraps err in a PinError, and logs it, or returns nil if err is nil.
+func pinError(op string, pin int, err error) error {
+	if err == nil {
+		return nil
+	}
+	e := &PinError{Op: op, Pin: pin, Err: err}
+	logf("%s", e)
+	return e
+}
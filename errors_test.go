@@ -0,0 +1,32 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Test suite for errors module.
+//
+// Tests do not use any pins.
+package gpio
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPinError(t *testing.T) {
+	assert.Nil(t, pinError("export", 4, nil))
+
+	err := pinError("export", 4, ErrTimeout)
+	assert.Equal(t, "export GPIO4: timeout", err.Error())
+	assert.True(t, errors.Is(err, ErrTimeout))
+	var pe *PinError
+	assert.True(t, errors.As(err, &pe))
+	assert.Equal(t, &PinError{Op: "export", Pin: 4, Err: ErrTimeout}, pe)
+
+	err = pinError("set edge", 17, os.ErrPermission)
+	assert.True(t, errors.Is(err, os.ErrPermission))
+	assert.Equal(t, "set edge GPIO17: permission denied", err.Error())
+}
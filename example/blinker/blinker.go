@@ -25,7 +25,7 @@ func main() {
 	}
 	defer gpio.Close()
 	pin := gpio.NewPin(gpio.GPIO4)
-	defer pin.Input()
+	pin.Cleanup((*gpio.Pin).Input)
 	pin.Output()
 	// capture exit signals to ensure pin is reverted to input on exit.
 	quit := make(chan os.Signal, 1)
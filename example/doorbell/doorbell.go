@@ -0,0 +1,47 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/warthog618/gpio"
+	"github.com/warthog618/gpio/triggers"
+)
+
+// This example watches a doorbell button on GPIO 17 (J8 11), which is pulled
+// up and shorted to ground when pressed, and pulses a chime driven by GPIO 27
+// (J8 13) for 200ms each time the button is pressed.
+// Do not run this on a Raspberry Pi which has GPIO 27 externally driven.
+func main() {
+	err := gpio.Open()
+	if err != nil {
+		panic(err)
+	}
+	defer gpio.Close()
+	gpio.NewPin(gpio.GPIO17).PullUp()
+	t, err := triggers.New(gpio.GPIO17, gpio.GPIO27, 200*time.Millisecond,
+		triggers.WithCooldown(2*time.Second),
+		triggers.WithHandler(func(at time.Time) {
+			fmt.Println("Ding dong at", at.Format(time.Kitchen))
+		}))
+	if err != nil {
+		panic(err)
+	}
+	defer gpio.NewPin(gpio.GPIO27).Input()
+	defer t.Close()
+
+	// capture exit signals to ensure resources are released on exit.
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(quit)
+	fmt.Println("Waiting for visitors...")
+	<-quit
+}
@@ -0,0 +1,62 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/warthog618/gpio"
+	"github.com/warthog618/gpio/dma"
+)
+
+// This example drives a hobby servo on GPIO 18 (J8 12) using DMA, sweeping it
+// between its end stops.  The servo is sent a 1-2ms pulse every 20ms.
+// Must be run as root.
+// Do not run this on a Raspberry Pi which has GPIO 18 externally driven.
+func main() {
+	err := gpio.Open()
+	if err != nil {
+		panic(err)
+	}
+	defer gpio.Close()
+	pin := gpio.NewPin(gpio.GPIO18)
+	pin.Low()
+	pin.Output()
+	defer pin.Input()
+
+	// 10µs ticks, so 2000 samples is 20ms.
+	w, err := dma.New(5, 10*time.Microsecond, 2000)
+	if err != nil {
+		panic(err)
+	}
+	defer w.Close()
+	w.SetPulse(gpio.GPIO18, 0, 150)
+	w.Start()
+
+	// capture exit signals to ensure resources are released on exit.
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(quit)
+	fmt.Println("Sweeping...")
+	width := 100
+	step := 10
+	for {
+		select {
+		case <-time.After(100 * time.Millisecond):
+			w.SetPulse(gpio.GPIO18, 0, width)
+			if width+step > 200 || width+step < 100 {
+				step = -step
+			}
+			width += step
+		case <-quit:
+			return
+		}
+	}
+}
@@ -45,8 +45,14 @@ func main() {
 		cfg.MustGet("di").Int(),
 		cfg.MustGet("do").Int())
 	defer a.Close()
-	ch0 := a.Read(0)
-	ch1 := a.Read(1)
+	ch0, err := a.Read(0)
+	if err != nil {
+		panic(err)
+	}
+	ch1, err := a.Read(1)
+	if err != nil {
+		panic(err)
+	}
 	fmt.Printf("ch0=0x%02x, ch1=0x%02x\n", ch0, ch1)
 }
 
@@ -41,7 +41,10 @@ func main() {
 		cfg.MustGet("miso").Int())
 	defer adc.Close()
 	for ch := 0; ch < 8; ch++ {
-		d := adc.Read(ch)
+		d, err := adc.Read(ch)
+		if err != nil {
+			panic(err)
+		}
 		fmt.Printf("ch%d=0x%04x (%08b)\n", ch, d, d>>4)
 	}
 }
@@ -0,0 +1,161 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package exporter publishes the levels of pins, their edge counts and the
+// dispatch latency of their edge events as Prometheus metrics.
+//
+// The dispatch latency is the time from an edge event being timestamped by
+// the Watcher to its handler being called, so it does not include the time
+// taken to detect the edge.
+//
+// The metrics are served in the Prometheus text exposition format, so no
+// Prometheus client library is required.
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/warthog618/gpio"
+)
+
+// latencyBuckets are the upper bounds of the latency histogram buckets, in
+// seconds.
+var latencyBuckets = []float64{
+	0.00001, 0.000025, 0.00005, 0.0001, 0.00025, 0.0005,
+	0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1,
+}
+
+// Exporter watches a set of pins and serves their metrics over HTTP.
+type Exporter struct {
+	pins    map[int]*gpio.Pin
+	watches []*gpio.Watch
+
+	// Guards the following.
+	mu      sync.Mutex
+	rising  map[int]uint64
+	falling map[int]uint64
+	// cumulative counts of the latency histogram buckets, with +Inf last.
+	buckets []uint64
+	sum     float64
+	count   uint64
+}
+
+// New creates an Exporter that watches the pins using the watcher.
+//
+// The pins should be inputs.  The watcher should use serial dispatch, so the
+// initial sync event for each pin is received first and not counted as an
+// edge.
+func New(w *gpio.Watcher, pins ...int) (*Exporter, error) {
+	e := &Exporter{
+		pins:    make(map[int]*gpio.Pin),
+		rising:  make(map[int]uint64),
+		falling: make(map[int]uint64),
+		buckets: make([]uint64, len(latencyBuckets)+1),
+	}
+	for _, o := range pins {
+		pin := gpio.NewPin(o)
+		if pin == nil {
+			e.Close()
+			return nil, fmt.Errorf("invalid pin %d", o)
+		}
+		e.pins[o] = pin
+		synced := false
+		var mu sync.Mutex
+		wt, err := w.AddWatch(pin, gpio.EdgeBoth, func(evt gpio.Event) {
+			mu.Lock()
+			first := !synced
+			synced = true
+			mu.Unlock()
+			if !first {
+				e.record(evt)
+			}
+		})
+		if err != nil {
+			e.Close()
+			return nil, err
+		}
+		e.watches = append(e.watches, wt)
+	}
+	return e, nil
+}
+
+// Close removes the watches on the pins.
+func (e *Exporter) Close() {
+	for _, wt := range e.watches {
+		wt.Unwatch()
+	}
+	e.watches = nil
+}
+
+func (e *Exporter) record(evt gpio.Event) {
+	latency := time.Since(evt.Time).Seconds()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if evt.Level == gpio.High {
+		e.rising[evt.Pin.Pin()]++
+	} else {
+		e.falling[evt.Pin.Pin()]++
+	}
+	for i, le := range latencyBuckets {
+		if latency <= le {
+			e.buckets[i]++
+		}
+	}
+	e.buckets[len(latencyBuckets)]++
+	e.sum += latency
+	e.count++
+}
+
+// ServeHTTP serves the metrics.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	e.Write(w)
+}
+
+// Write writes the metrics in the Prometheus text exposition format.
+func (e *Exporter) Write(w io.Writer) error {
+	pins := make([]int, 0, len(e.pins))
+	for o := range e.pins {
+		pins = append(pins, o)
+	}
+	sort.Ints(pins)
+	var err error
+	printf := func(format string, args ...interface{}) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, args...)
+		}
+	}
+	printf("# HELP gpio_pin_level The current level of the pin.\n")
+	printf("# TYPE gpio_pin_level gauge\n")
+	for _, o := range pins {
+		level := 0
+		if e.pins[o].Read() == gpio.High {
+			level = 1
+		}
+		printf("gpio_pin_level{pin=\"%d\"} %d\n", o, level)
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	printf("# HELP gpio_pin_edges_total The number of edges detected on the pin.\n")
+	printf("# TYPE gpio_pin_edges_total counter\n")
+	for _, o := range pins {
+		printf("gpio_pin_edges_total{pin=\"%d\",edge=\"rising\"} %d\n", o, e.rising[o])
+		printf("gpio_pin_edges_total{pin=\"%d\",edge=\"falling\"} %d\n", o, e.falling[o])
+	}
+	printf("# HELP gpio_dispatch_latency_seconds The time from an edge being timestamped to its handler being called.\n")
+	printf("# TYPE gpio_dispatch_latency_seconds histogram\n")
+	for i, le := range latencyBuckets {
+		printf("gpio_dispatch_latency_seconds_bucket{le=\"%g\"} %d\n", le, e.buckets[i])
+	}
+	printf("gpio_dispatch_latency_seconds_bucket{le=\"+Inf\"} %d\n", e.buckets[len(latencyBuckets)])
+	printf("gpio_dispatch_latency_seconds_sum %g\n", e.sum)
+	printf("gpio_dispatch_latency_seconds_count %d\n", e.count)
+	return err
+}
@@ -5,6 +5,7 @@ require (
 	github.com/stretchr/testify v1.8.1
 	github.com/warthog618/config v0.5.1
 	golang.org/x/sys v0.3.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -16,7 +17,6 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
 	gopkg.in/check.v1 v1.0.0-20190902080502-41f04d3bba15 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 go 1.17
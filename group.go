@@ -0,0 +1,153 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package gpio
+
+// PinGroup represents an ordered set of pins that are read and written
+// together.
+//
+// Values are mapped to the pins in order, with the first pin being the least
+// significant bit.  Writes to pins in the same bank are performed with a
+// single register write for each level, so the pins change level together.
+type PinGroup struct {
+	pins []*Pin
+}
+
+// NewPinGroup creates a new pin group object.
+// The pin numbers provided are the BCM GPIO numbers.
+//
+// Returns nil if any of the pins is invalid.
+func NewPinGroup(pins ...int) *PinGroup {
+	pp := make([]*Pin, len(pins))
+	for i, pin := range pins {
+		pp[i] = NewPin(pin)
+		if pp[i] == nil {
+			return nil
+		}
+	}
+	return &PinGroup{pins: pp}
+}
+
+// Pins returns the pins in the group.
+func (g *PinGroup) Pins() []*Pin {
+	return g.pins
+}
+
+// Input sets all pins in the group as Input.
+func (g *PinGroup) Input() {
+	g.SetMode(Input)
+}
+
+// Output sets all pins in the group as Output.
+func (g *PinGroup) Output() {
+	g.SetMode(Output)
+}
+
+// SetMode sets the Mode of all pins in the group.
+func (g *PinGroup) SetMode(mode Mode) {
+	for _, pin := range g.pins {
+		pin.SetMode(mode)
+	}
+}
+
+// Read returns the levels of the pins in the group.
+//
+// Each bank is read with a single register read.
+// Pins that are closed or stale read as Low.
+func (g *PinGroup) Read() uint {
+	var levels [2]uint32
+	var read [2]bool
+	var value uint
+	for i, pin := range g.pins {
+		if pin.usable() != nil {
+			// the registers may no longer be mapped.
+			continue
+		}
+		if !read[pin.bank] {
+			levels[pin.bank] = ReadBank(pin.bank)
+			read[pin.bank] = true
+		}
+		if levels[pin.bank]&pin.mask != 0 {
+			pin.shadow = High
+			value |= 1 << uint(i)
+		} else {
+			pin.shadow = Low
+		}
+	}
+	return value
+}
+
+// Write sets the levels of the pins in the group.
+//
+// Pins that are closed, stale, or protected are not written - use TryWrite to
+// detect that.
+func (g *PinGroup) Write(value uint) {
+	g.write(value)
+}
+
+// TryWrite sets the levels of the pins in the group.
+//
+// Returns ErrPinClosed, ErrNotOpen, or ErrProtected, and writes none of the
+// pins, if any pin in the group is closed, stale, or protected.
+func (g *PinGroup) TryWrite(value uint) error {
+	for _, pin := range g.pins {
+		if err := pin.usable(); err != nil {
+			return pinError("write", pin.pin, err)
+		}
+		if Protected(pin.pin) {
+			return pinError("write", pin.pin, ErrProtected)
+		}
+	}
+	g.write(value)
+	return nil
+}
+
+// write sets the levels of the pins in the group, skipping pins that are not
+// usable or are protected.
+func (g *PinGroup) write(value uint) {
+	var set, clear [2]uint32
+	for i, pin := range g.pins {
+		if pin.usable() != nil || Protected(pin.pin) {
+			continue
+		}
+		pin.touch()
+		if value&(1<<uint(i)) != 0 {
+			set[pin.bank] |= pin.mask
+			pin.shadow = High
+		} else {
+			clear[pin.bank] |= pin.mask
+			pin.shadow = Low
+		}
+	}
+	for bank := 0; bank < g.banks(); bank++ {
+		if clear[bank] != 0 {
+			mem[10+bank] = clear[bank]
+		}
+		if set[bank] != 0 {
+			mem[7+bank] = set[bank]
+		}
+	}
+}
+
+// Shadow returns the levels of the pins in the group from their shadows.
+func (g *PinGroup) Shadow() uint {
+	var value uint
+	for i, pin := range g.pins {
+		if pin.shadow {
+			value |= 1 << uint(i)
+		}
+	}
+	return value
+}
+
+func (g *PinGroup) banks() int {
+	banks := 1
+	for _, pin := range g.pins {
+		if pin.bank >= banks {
+			banks = pin.bank + 1
+		}
+	}
+	return banks
+}
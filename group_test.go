@@ -0,0 +1,75 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && hardware
+// +build linux,hardware
+
+// Test suite for group module.
+//
+// Tests use J8 pins 7, and 15 and 16 (for looped tests).
+package gpio_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warthog618/gpio"
+)
+
+func TestNewPinGroup(t *testing.T) {
+	setupDIO(t)
+	defer teardownDIO()
+	g := gpio.NewPinGroup(gpio.J8p7, gpio.MaxGPIOPin)
+	assert.Nil(t, g)
+	g = gpio.NewPinGroup(gpio.J8p7, gpio.J8p16)
+	assert.NotNil(t, g)
+	pp := g.Pins()
+	assert.Equal(t, 2, len(pp))
+	assert.Equal(t, gpio.J8p7, pp[0].Pin())
+	assert.Equal(t, gpio.J8p16, pp[1].Pin())
+}
+
+func TestPinGroupMode(t *testing.T) {
+	setupDIO(t)
+	defer teardownDIO()
+	g := gpio.NewPinGroup(gpio.J8p7, gpio.J8p16)
+	defer g.Input()
+	g.Output()
+	for _, pin := range g.Pins() {
+		assert.Equal(t, gpio.Output, pin.Mode())
+	}
+	g.Input()
+	for _, pin := range g.Pins() {
+		assert.Equal(t, gpio.Input, pin.Mode())
+	}
+}
+
+func TestPinGroupWrite(t *testing.T) {
+	setupDIO(t)
+	defer teardownDIO()
+	g := gpio.NewPinGroup(gpio.J8p7, gpio.J8p16)
+	defer g.Input()
+	g.Write(0)
+	g.Output()
+	for v := uint(0); v < 4; v++ {
+		g.Write(v)
+		assert.Equal(t, v, g.Shadow())
+		assert.Equal(t, v, g.Read())
+	}
+}
+
+// Looped tests require a jumper across Raspberry Pi J8 pins 15 and 16.
+func TestPinGroupReadLooped(t *testing.T) {
+	setupDIO(t)
+	defer teardownDIO()
+	in := gpio.NewPinGroup(gpio.J8p15, gpio.J8p16)
+	out := gpio.NewPin(gpio.J8p16)
+	defer out.Input()
+	out.Low()
+	out.Output()
+	assert.Equal(t, uint(0), in.Read())
+	out.High()
+	assert.Equal(t, uint(3), in.Read())
+}
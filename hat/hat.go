@@ -0,0 +1,249 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package hat reads the ID EEPROM of a Raspberry Pi HAT, which identifies the
+// HAT and declares the GPIO pins it uses.
+//
+// The EEPROM is read over a bit bashed I2C bus on the ID_SD and ID_SC pins,
+// GPIO0 and GPIO1, in the format defined by the Raspberry Pi HAT
+// specification.
+package hat
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/warthog618/gpio"
+	"github.com/warthog618/gpio/i2c"
+)
+
+const (
+	// The I2C address of the ID EEPROM.
+	eepromAddr = 0x50
+
+	// The maximum length of the EEPROM contents read.
+	maxLength = 64 * 1024
+
+	headerLength     = 12
+	atomHeaderLength = 8
+
+	atomVendorInfo = 0x0001
+	atomGPIOMap    = 0x0002
+)
+
+// The pins of the ID EEPROM I2C bus.
+const (
+	idSD = 0
+	idSC = 1
+)
+
+// HAT describes a HAT, as declared in its ID EEPROM.
+type HAT struct {
+	// The unique identifier of the HAT.
+	UUID [16]byte
+
+	// The product ID and version, as assigned by the vendor.
+	ProductID      uint16
+	ProductVersion uint16
+
+	// The names of the vendor and product.
+	Vendor  string
+	Product string
+
+	// The pins used by the HAT, in order of BCM GPIO number.
+	Pins []PinConfig
+}
+
+// PinConfig is the configuration the HAT declares for one of its pins.
+type PinConfig struct {
+	// The BCM GPIO number of the pin.
+	Pin int
+
+	// The mode the pin is to be set to.
+	Mode gpio.Mode
+
+	// The pull the pin is to be set to, or PullUnknown to leave the default.
+	Pull gpio.Pull
+}
+
+// Read reads the ID EEPROM of the attached HAT.
+//
+// If GPIO0 or GPIO1 are protected, as they are by default, each is
+// unprotected while the EEPROM is read, and protected again afterwards.
+// Protection applies to the whole process, so the protection of those pins
+// should not be altered elsewhere while the EEPROM is read.
+// Returns ErrNotFound if there is no EEPROM, such as when no HAT is attached.
+// The GPIO must be open.
+func Read() (*HAT, error) {
+	for _, pin := range []int{idSD, idSC} {
+		if gpio.Protected(pin) {
+			gpio.Unprotect(pin)
+			defer gpio.Protect(pin)
+		}
+	}
+	bus, err := i2c.New(idSC, idSD)
+	if err != nil {
+		return nil, err
+	}
+	defer bus.Close()
+	return ReadFrom(bus)
+}
+
+// ReadFrom reads an ID EEPROM on the bus.
+//
+// Returns ErrNotFound if the EEPROM does not respond.
+func ReadFrom(bus *i2c.I2C) (*HAT, error) {
+	hdr := make([]byte, headerLength)
+	if err := bus.Tx(eepromAddr, []byte{0, 0}, hdr); err != nil {
+		if errors.Is(err, i2c.ErrNack) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if string(hdr[:4]) != "R-Pi" {
+		return nil, ErrInvalidEEPROM
+	}
+	n := binary.LittleEndian.Uint32(hdr[8:])
+	if n < headerLength || n > maxLength {
+		return nil, ErrInvalidEEPROM
+	}
+	data := make([]byte, n)
+	if err := bus.Tx(eepromAddr, []byte{0, 0}, data); err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse parses the contents of an ID EEPROM, such as read from the EEPROM or
+// an image created by the eepmake tool.
+//
+// Only the vendor info and GPIO map atoms are parsed, and other atoms, such
+// as the device tree overlay, are skipped.
+// Returns ErrInvalidEEPROM if the contents are malformed, and ErrChecksum if
+// an atom is corrupted.
+func Parse(data []byte) (*HAT, error) {
+	if len(data) < headerLength || string(data[:4]) != "R-Pi" {
+		return nil, ErrInvalidEEPROM
+	}
+	if n := binary.LittleEndian.Uint32(data[8:]); int(n) < len(data) {
+		data = data[:n]
+	}
+	natoms := int(binary.LittleEndian.Uint16(data[6:]))
+	h := &HAT{}
+	off := headerLength
+	for i := 0; i < natoms; i++ {
+		if off+atomHeaderLength > len(data) {
+			return nil, ErrInvalidEEPROM
+		}
+		typ := binary.LittleEndian.Uint16(data[off:])
+		dlen := int(binary.LittleEndian.Uint32(data[off+4:]))
+		end := off + atomHeaderLength + dlen
+		if dlen < 2 || end > len(data) {
+			return nil, ErrInvalidEEPROM
+		}
+		atom := data[off : end-2]
+		if crc16(atom) != binary.LittleEndian.Uint16(data[end-2:]) {
+			return nil, fmt.Errorf("atom %d: %w", i, ErrChecksum)
+		}
+		body := atom[atomHeaderLength:]
+		var err error
+		switch typ {
+		case atomVendorInfo:
+			err = h.parseVendorInfo(body)
+		case atomGPIOMap:
+			err = h.parseGPIOMap(body)
+		}
+		if err != nil {
+			return nil, err
+		}
+		off = end
+	}
+	return h, nil
+}
+
+// Used returns the BCM GPIO numbers of the pins used by the HAT.
+func (h *HAT) Used() []int {
+	pins := make([]int, len(h.Pins))
+	for i, pc := range h.Pins {
+		pins[i] = pc.Pin
+	}
+	return pins
+}
+
+// Reserve protects the pins used by the HAT, so they cannot be changed
+// inadvertently, as per gpio.Protect.
+func (h *HAT) Reserve() {
+	gpio.Protect(h.Used()...)
+}
+
+func (h *HAT) parseVendorInfo(b []byte) error {
+	// uuid, pid, pver, vslen, pslen
+	if len(b) < 22 {
+		return ErrInvalidEEPROM
+	}
+	copy(h.UUID[:], b)
+	h.ProductID = binary.LittleEndian.Uint16(b[16:])
+	h.ProductVersion = binary.LittleEndian.Uint16(b[18:])
+	vslen, pslen := int(b[20]), int(b[21])
+	b = b[22:]
+	if len(b) < vslen+pslen {
+		return ErrInvalidEEPROM
+	}
+	h.Vendor = string(b[:vslen])
+	h.Product = string(b[vslen : vslen+pslen])
+	return nil
+}
+
+func (h *HAT) parseGPIOMap(b []byte) error {
+	// bank drive, power, then a byte for each of GPIO0 to GPIO27
+	if len(b) < 2+gpio.MaxGPIOPin {
+		return ErrInvalidEEPROM
+	}
+	h.Pins = h.Pins[:0]
+	for pin, v := range b[2 : 2+gpio.MaxGPIOPin] {
+		if v&0x80 == 0 {
+			continue
+		}
+		h.Pins = append(h.Pins, PinConfig{
+			Pin:  pin,
+			Mode: gpio.Mode(v & 0x7),
+			Pull: pulls[(v>>5)&0x3],
+		})
+	}
+	return nil
+}
+
+// pulls maps from the EEPROM pull type to Pull.
+var pulls = [4]gpio.Pull{gpio.PullUnknown, gpio.PullUp, gpio.PullDown, gpio.PullNone}
+
+// crc16 returns the CRC-16 of the data, with the reflected 0x8005 polynomial
+// used by the HAT EEPROM format.
+func crc16(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b)
+		for i := 0; i < 8; i++ {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ 0xa001
+			} else {
+				crc >>= 1
+			}
+		}
+	}
+	return crc
+}
+
+var (
+	// ErrChecksum indicates an atom in the EEPROM is corrupted.
+	ErrChecksum = errors.New("checksum mismatch")
+
+	// ErrInvalidEEPROM indicates the contents of the EEPROM are not in the
+	// HAT EEPROM format.
+	ErrInvalidEEPROM = errors.New("invalid EEPROM")
+
+	// ErrNotFound indicates there is no ID EEPROM, so no HAT is attached.
+	ErrNotFound = errors.New("no HAT EEPROM found")
+)
@@ -0,0 +1,162 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Test suite for the hat module.
+//
+// Tests parse EEPROM images built in memory, so can be run on any machine.
+package hat
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/warthog618/gpio"
+)
+
+func TestCRC16(t *testing.T) {
+	patterns := []struct {
+		name string
+		data []byte
+		crc  uint16
+	}{
+		{"empty", nil, 0},
+		{"zero", []byte{0}, 0},
+		{"check", []byte("123456789"), 0xbb3d},
+		{"a", []byte("A"), 0x30c0},
+	}
+	for _, p := range patterns {
+		t.Run(p.name, func(t *testing.T) {
+			assert.Equal(t, p.crc, crc16(p.data))
+		})
+	}
+}
+
+type atom struct {
+	typ  uint16
+	data []byte
+}
+
+// image builds an EEPROM image containing the atoms.
+func image(atoms ...atom) []byte {
+	b := make([]byte, headerLength)
+	copy(b, "R-Pi")
+	b[4] = 1
+	binary.LittleEndian.PutUint16(b[6:], uint16(len(atoms)))
+	for i, a := range atoms {
+		ah := make([]byte, atomHeaderLength)
+		binary.LittleEndian.PutUint16(ah, a.typ)
+		binary.LittleEndian.PutUint16(ah[2:], uint16(i))
+		binary.LittleEndian.PutUint32(ah[4:], uint32(len(a.data)+2))
+		ad := append(ah, a.data...)
+		b = append(b, ad...)
+		crc := make([]byte, 2)
+		binary.LittleEndian.PutUint16(crc, crc16(ad))
+		b = append(b, crc...)
+	}
+	binary.LittleEndian.PutUint32(b[8:], uint32(len(b)))
+	return b
+}
+
+func vendorInfo(uuid byte, pid, pver uint16, vendor, product string) []byte {
+	b := make([]byte, 22)
+	for i := 0; i < 16; i++ {
+		b[i] = uuid
+	}
+	binary.LittleEndian.PutUint16(b[16:], pid)
+	binary.LittleEndian.PutUint16(b[18:], pver)
+	b[20] = byte(len(vendor))
+	b[21] = byte(len(product))
+	b = append(b, vendor...)
+	return append(b, product...)
+}
+
+func gpioMap(pins map[int]byte) []byte {
+	b := make([]byte, 2+gpio.MaxGPIOPin)
+	for pin, v := range pins {
+		b[2+pin] = v
+	}
+	return b
+}
+
+func TestParse(t *testing.T) {
+	vi := vendorInfo(0xa5, 0x1234, 2, "acme", "widget")
+	gm := gpioMap(map[int]byte{
+		gpio.GPIO4:  0x80 | byte(gpio.Output),
+		gpio.GPIO17: 0x80 | 0x20 | byte(gpio.Input),
+		gpio.GPIO22: 0x80 | 0x40 | byte(gpio.Input),
+		gpio.GPIO27: 0x80 | 0x60 | byte(gpio.Alt0),
+		gpio.GPIO5:  byte(gpio.Output), // not used
+	})
+	uuid := [16]byte{}
+	for i := range uuid {
+		uuid[i] = 0xa5
+	}
+	full := &HAT{
+		UUID:           uuid,
+		ProductID:      0x1234,
+		ProductVersion: 2,
+		Vendor:         "acme",
+		Product:        "widget",
+		Pins: []PinConfig{
+			{gpio.GPIO4, gpio.Output, gpio.PullUnknown},
+			{gpio.GPIO17, gpio.Input, gpio.PullUp},
+			{gpio.GPIO22, gpio.Input, gpio.PullDown},
+			{gpio.GPIO27, gpio.Alt0, gpio.PullNone},
+		},
+	}
+	corrupt := image(atom{atomVendorInfo, vi})
+	corrupt[headerLength+atomHeaderLength] ^= 0xff
+	truncated := image(atom{atomVendorInfo, vi})
+	truncated = truncated[:len(truncated)-4]
+	badSig := image(atom{atomVendorInfo, vi})
+	badSig[0] = 'X'
+	patterns := []struct {
+		name string
+		data []byte
+		hat  *HAT
+		err  error
+	}{
+		{"full", image(atom{atomVendorInfo, vi}, atom{atomGPIOMap, gm}), full, nil},
+		{"empty", image(), &HAT{}, nil},
+		{"vendor only", image(atom{atomVendorInfo, vi}),
+			&HAT{UUID: uuid, ProductID: 0x1234, ProductVersion: 2, Vendor: "acme", Product: "widget"}, nil},
+		{"unknown atom", image(atom{0x0003, []byte("dtb")}), &HAT{}, nil},
+		{"trailing", append(image(), 0xff, 0xff), &HAT{}, nil},
+		{"short", []byte("R-Pi"), nil, ErrInvalidEEPROM},
+		{"bad signature", badSig, nil, ErrInvalidEEPROM},
+		{"truncated", truncated, nil, ErrInvalidEEPROM},
+		{"checksum", corrupt, nil, ErrChecksum},
+		{"short vendor info", image(atom{atomVendorInfo, vi[:21]}), nil, ErrInvalidEEPROM},
+		{"short strings", image(atom{atomVendorInfo, vi[:len(vi)-1]}), nil, ErrInvalidEEPROM},
+		{"short gpio map", image(atom{atomGPIOMap, gm[:len(gm)-1]}), nil, ErrInvalidEEPROM},
+	}
+	for _, p := range patterns {
+		t.Run(p.name, func(t *testing.T) {
+			h, err := Parse(p.data)
+			assert.ErrorIs(t, err, p.err)
+			if p.hat == nil {
+				assert.Nil(t, h)
+				return
+			}
+			require.NotNil(t, h)
+			if len(p.hat.Pins) == 0 {
+				assert.Empty(t, h.Pins)
+				h.Pins = nil
+			}
+			assert.Equal(t, p.hat, h)
+		})
+	}
+}
+
+func TestUsed(t *testing.T) {
+	h := &HAT{Pins: []PinConfig{
+		{Pin: gpio.GPIO4},
+		{Pin: gpio.GPIO17},
+	}}
+	assert.Equal(t, []int{gpio.GPIO4, gpio.GPIO17}, h.Used())
+	assert.Empty(t, (&HAT{}).Used())
+}
@@ -0,0 +1,129 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package hd44780
+
+import (
+	"sync"
+	"time"
+
+	"github.com/warthog618/gpio"
+	"github.com/warthog618/gpio/pcf8574"
+)
+
+// GPIOBus drives the controller directly from GPIO pins, with RW tied low.
+type GPIOBus struct {
+	rs *gpio.Pin
+	e  *gpio.Pin
+	d  [4]*gpio.Pin
+}
+
+// NewGPIOBus creates a GPIOBus on the RS, E and D4-D7 pins.
+//
+// The pins are set to outputs, and are requested until the GPIOBus is closed.
+// The GPIO must be open.
+func NewGPIOBus(rs, e, d4, d5, d6, d7 int) (*GPIOBus, error) {
+	b := &GPIOBus{
+		rs: gpio.NewPin(rs),
+		e:  gpio.NewPin(e),
+		d: [4]*gpio.Pin{
+			gpio.NewPin(d4),
+			gpio.NewPin(d5),
+			gpio.NewPin(d6),
+			gpio.NewPin(d7),
+		},
+	}
+	pins := append([]*gpio.Pin{b.rs, b.e}, b.d[:]...)
+	for _, pin := range pins {
+		if pin == nil {
+			return nil, ErrInvalidPin
+		}
+	}
+	if err := gpio.RequestPins("hd44780", pins...); err != nil {
+		return nil, err
+	}
+	for _, pin := range pins {
+		pin.Low()
+		pin.Output()
+	}
+	return b, nil
+}
+
+// Close returns the pins to inputs.
+func (b *GPIOBus) Close() {
+	b.rs.Input()
+	b.e.Input()
+	for _, pin := range b.d {
+		pin.Input()
+	}
+	gpio.ReleasePins(append([]*gpio.Pin{b.rs, b.e}, b.d[:]...)...)
+}
+
+// WriteNibble writes the nibble and strobes E.
+func (b *GPIOBus) WriteNibble(rs bool, nibble byte) error {
+	b.rs.Write(gpio.Level(rs))
+	for i, pin := range b.d {
+		pin.Write(nibble&(1<<uint(i)) != 0)
+	}
+	b.e.High()
+	gpio.Delay(time.Microsecond)
+	b.e.Low()
+	// allow for the execution time of most instructions.
+	gpio.Delay(50 * time.Microsecond)
+	return nil
+}
+
+// The expander pins of the common PCF8574 LCD backpacks.
+const (
+	backpackRS        = 0x01
+	backpackE         = 0x04
+	backpackBacklight = 0x08
+	// D4-D7 are on P4-P7.
+	backpackDataShift = 4
+)
+
+// Backpack drives the controller through a PCF8574 I2C backpack, with the
+// common wiring of RS on P0, RW on P1, E on P2, the backlight on P3 and D4-D7
+// on P4-P7.
+type Backpack struct {
+	exp *pcf8574.PCF8574
+
+	// Guards the following.
+	mu        sync.Mutex
+	backlight byte
+}
+
+// NewBackpack creates a Backpack on the expander, with the backlight on.
+func NewBackpack(exp *pcf8574.PCF8574) *Backpack {
+	return &Backpack{exp: exp, backlight: backpackBacklight}
+}
+
+// SetBacklight turns the backlight on or off.
+func (b *Backpack) SetBacklight(on bool) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.backlight = 0
+	if on {
+		b.backlight = backpackBacklight
+	}
+	return b.exp.Write(b.backlight)
+}
+
+// WriteNibble writes the nibble and strobes E.
+//
+// Each write to the expander takes around 100µs at the standard I2C clock, so
+// no further delays are required for most instructions.
+func (b *Backpack) WriteNibble(rs bool, nibble byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	v := nibble<<backpackDataShift | b.backlight
+	if rs {
+		v |= backpackRS
+	}
+	if err := b.exp.Write(v | backpackE); err != nil {
+		return err
+	}
+	return b.exp.Write(v)
+}
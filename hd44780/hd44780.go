@@ -0,0 +1,195 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package hd44780 drives character LCDs with HD44780 compatible controllers,
+// in 4-bit mode, either directly from GPIO pins or through a PCF8574 I2C
+// backpack.
+package hd44780
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Bus writes to the controller in 4-bit mode.
+type Bus interface {
+	// WriteNibble writes the lower 4 bits of the nibble to D4-D7, as data if
+	// rs is true or as an instruction if it is false, and strobes E.
+	WriteNibble(rs bool, nibble byte) error
+}
+
+const (
+	cmdClear        = 0x01
+	cmdHome         = 0x02
+	cmdEntryMode    = 0x04
+	cmdDisplay      = 0x08
+	cmdFunctionSet  = 0x20
+	cmdSetDDRAMAddr = 0x80
+
+	// entry mode - increment the address and don't shift.
+	entryIncrement = 0x02
+
+	// display control flags.
+	displayOn = 0x04
+	cursorOn  = 0x02
+	blinkOn   = 0x01
+
+	// function set - 4-bit, 2 line, 5x8 font.
+	function2Line = 0x08
+)
+
+// LCD is a character LCD.
+type LCD struct {
+	bus  Bus
+	cols int
+	rows int
+
+	// Guards the following.
+	mu      sync.Mutex
+	display byte
+}
+
+// New creates an LCD with the geometry on the bus, and initialises the
+// controller, leaving the display on and clear, with the cursor hidden.
+func New(bus Bus, cols, rows int) (*LCD, error) {
+	if cols < 1 || rows < 1 || rows > 4 {
+		return nil, ErrInvalidGeometry
+	}
+	l := &LCD{bus: bus, cols: cols, rows: rows, display: displayOn}
+	// the controller may be in 8 or 4-bit mode, or halfway through a 4-bit
+	// transfer, so force 8-bit mode before switching to 4-bit.
+	time.Sleep(50 * time.Millisecond)
+	for _, d := range []time.Duration{
+		5 * time.Millisecond,
+		200 * time.Microsecond,
+		200 * time.Microsecond,
+	} {
+		if err := bus.WriteNibble(false, 0x3); err != nil {
+			return nil, err
+		}
+		time.Sleep(d)
+	}
+	if err := bus.WriteNibble(false, 0x2); err != nil {
+		return nil, err
+	}
+	time.Sleep(200 * time.Microsecond)
+	function := byte(cmdFunctionSet)
+	if rows > 1 {
+		function |= function2Line
+	}
+	for _, cmd := range []byte{
+		function,
+		cmdDisplay | l.display,
+		cmdEntryMode | entryIncrement,
+	} {
+		if err := l.command(cmd); err != nil {
+			return nil, err
+		}
+	}
+	if err := l.Clear(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Clear clears the display and returns the cursor to the top left.
+func (l *LCD) Clear() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	err := l.command(cmdClear)
+	time.Sleep(2 * time.Millisecond)
+	return err
+}
+
+// Home returns the cursor to the top left.
+func (l *LCD) Home() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	err := l.command(cmdHome)
+	time.Sleep(2 * time.Millisecond)
+	return err
+}
+
+// SetCursor moves the cursor to the column and row, with the origin at the top
+// left.
+func (l *LCD) SetCursor(col, row int) error {
+	if col < 0 || col >= l.cols || row < 0 || row >= l.rows {
+		return ErrInvalidPosition
+	}
+	// rows 2 and 3 continue on from rows 0 and 1.
+	offsets := [4]int{0x00, 0x40, l.cols, 0x40 + l.cols}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.command(cmdSetDDRAMAddr | byte(offsets[row]+col))
+}
+
+// SetDisplay sets whether the display, the cursor and the blinking of the
+// cursor are on.
+//
+// The contents of the display are retained while it is off.
+func (l *LCD) SetDisplay(display, cursor, blink bool) error {
+	var d byte
+	if display {
+		d |= displayOn
+	}
+	if cursor {
+		d |= cursorOn
+	}
+	if blink {
+		d |= blinkOn
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.display = d
+	return l.command(cmdDisplay | d)
+}
+
+// Write writes the characters at the cursor.
+//
+// Characters are written as codes in the character ROM of the controller,
+// which matches ASCII for most printable characters.
+func (l *LCD) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, c := range p {
+		if err := l.write(true, c); err != nil {
+			return i, err
+		}
+	}
+	return len(p), nil
+}
+
+// WriteString writes the string at the cursor.
+func (l *LCD) WriteString(s string) (int, error) {
+	return l.Write([]byte(s))
+}
+
+// command writes an instruction.
+//
+// Assumes caller already holds the mu lock.
+func (l *LCD) command(cmd byte) error {
+	return l.write(false, cmd)
+}
+
+// write writes a byte as two nibbles, high nibble first.
+func (l *LCD) write(rs bool, b byte) error {
+	if err := l.bus.WriteNibble(rs, b>>4); err != nil {
+		return err
+	}
+	return l.bus.WriteNibble(rs, b&0x0f)
+}
+
+var (
+	// ErrInvalidGeometry indicates the number of columns or rows is not
+	// supported.
+	ErrInvalidGeometry = errors.New("invalid geometry")
+
+	// ErrInvalidPin indicates a pin is not a valid GPIO pin.
+	ErrInvalidPin = errors.New("invalid pin")
+
+	// ErrInvalidPosition indicates the cursor position is beyond the display.
+	ErrInvalidPosition = errors.New("invalid position")
+)
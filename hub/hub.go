@@ -0,0 +1,174 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package hub fans out the edge events on a set of pins to many independent
+// subscribers, each receiving a merged stream of the events on the pins it
+// subscribed to over a channel.
+package hub
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/warthog618/gpio"
+)
+
+// Hub distributes the edge events detected by a single Watcher to its
+// subscribers.
+type Hub struct {
+	watcher *gpio.Watcher
+
+	// Guards the following.
+	mu     sync.Mutex
+	pins   map[int]*gpio.Pin
+	subs   map[*Subscription]struct{}
+	closed bool
+}
+
+// New creates a Hub with its own Watcher, created with the options.
+//
+// Events are dispatched serially, so each subscriber receives the events on
+// a pin in order.
+func New(options ...gpio.WatcherOption) (*Hub, error) {
+	options = append([]gpio.WatcherOption{gpio.WithSerialDispatch()}, options...)
+	w, err := gpio.NewWatcher(options...)
+	if err != nil {
+		return nil, err
+	}
+	return &Hub{
+		watcher: w,
+		pins:    map[int]*gpio.Pin{},
+		subs:    map[*Subscription]struct{}{},
+	}, nil
+}
+
+// Close closes all the subscriptions and the Watcher.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return
+	}
+	h.closed = true
+	subs := h.subs
+	h.subs = nil
+	h.mu.Unlock()
+	for s := range subs {
+		s.close()
+	}
+	h.watcher.Close()
+}
+
+// Subscribe returns a Subscription to the events on the pins, on the edge,
+// buffered up to buffer events.
+//
+// The pins must be inputs, and the GPIO must be open.
+// The current level of each pin is delivered on subscribing, and then its
+// edges.  Events that arrive when the buffer is full are dropped, so a slow
+// subscriber cannot delay the others.
+func (h *Hub) Subscribe(pins []int, edge gpio.Edge, buffer int) (*Subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, ErrClosed
+	}
+	pp := make([]*gpio.Pin, 0, len(pins))
+	for _, pin := range pins {
+		p, ok := h.pins[pin]
+		if !ok {
+			if p = gpio.NewPin(pin); p == nil {
+				return nil, ErrInvalidPin
+			}
+		}
+		pp = append(pp, p)
+	}
+	s := &Subscription{
+		hub: h,
+		ch:  make(chan gpio.Event, buffer),
+	}
+	for _, p := range pp {
+		wt, err := h.watcher.AddWatch(p, edge, s.deliver)
+		if err != nil {
+			s.unwatch()
+			return nil, err
+		}
+		h.pins[p.Pin()] = p
+		s.watches = append(s.watches, wt)
+	}
+	h.subs[s] = struct{}{}
+	return s, nil
+}
+
+// Subscription is a stream of the events on a set of pins.
+type Subscription struct {
+	hub     *Hub
+	watches []*gpio.Watch
+
+	// Guards the following.
+	mu      sync.Mutex
+	ch      chan gpio.Event
+	dropped uint64
+	closed  bool
+}
+
+// C returns the channel the events are delivered on.
+//
+// The channel is closed when the Subscription, or its Hub, is closed.
+func (s *Subscription) C() <-chan gpio.Event {
+	return s.ch
+}
+
+// Dropped returns the number of events dropped as the buffer was full.
+func (s *Subscription) Dropped() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dropped
+}
+
+// Close stops the delivery of events and closes the channel.
+func (s *Subscription) Close() {
+	h := s.hub
+	h.mu.Lock()
+	delete(h.subs, s)
+	h.mu.Unlock()
+	s.close()
+}
+
+func (s *Subscription) close() {
+	s.unwatch()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.ch)
+	}
+}
+
+func (s *Subscription) unwatch() {
+	for _, wt := range s.watches {
+		wt.Unwatch()
+	}
+}
+
+func (s *Subscription) deliver(evt gpio.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.ch <- evt:
+	default:
+		s.dropped++
+	}
+}
+
+var (
+	// ErrClosed indicates the Hub has been closed.
+	ErrClosed = errors.New("hub closed")
+
+	// ErrInvalidPin indicates a pin is not a valid GPIO pin.
+	ErrInvalidPin = errors.New("invalid pin")
+)
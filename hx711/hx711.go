@@ -0,0 +1,276 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package hx711 reads load cells, and other bridge sensors, through an HX711
+// 24-bit ADC.
+//
+// The HX711 signals a conversion is ready by pulling DOUT low, and the
+// conversion is then clocked out with 24 pulses on PD_SCK, followed by 1 to 3
+// more pulses that select the channel and gain of the next conversion.
+// Holding PD_SCK high for more than 60µs powers down the HX711, so a read may
+// be corrupted if the reading thread is preempted mid-pulse.
+package hx711
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/warthog618/gpio"
+)
+
+// Gain selects the input channel and the gain applied to it.
+type Gain int
+
+const (
+	// GainA128 selects channel A with a gain of 128.
+	GainA128 Gain = iota
+
+	// GainB32 selects channel B with a gain of 32.
+	GainB32
+
+	// GainA64 selects channel A with a gain of 64.
+	GainA64
+)
+
+// pulses returns the number of clock pulses, following the data, that select
+// the gain.
+func (g Gain) pulses() int {
+	return int(g) + 1
+}
+
+// HX711 reads an HX711.
+type HX711 struct {
+	sck  *gpio.Pin
+	dout *gpio.Pin
+	// the maximum time to wait for a conversion.
+	timeout time.Duration
+
+	// Guards the following.
+	mu sync.Mutex
+	// the gain of the next conversion.
+	gain   Gain
+	offset float64
+	scale  float64
+}
+
+// Option modifies the configuration of an HX711.
+type Option func(*HX711)
+
+// WithGain sets the channel and gain of the conversions.
+//
+// The default is GainA128.
+func WithGain(g Gain) Option {
+	return func(h *HX711) {
+		h.gain = g
+	}
+}
+
+// WithTimeout sets the maximum time to wait for a conversion.
+//
+// The default is 500ms, which allows for the 10Hz output rate and the 400ms
+// settling time after power up.
+func WithTimeout(d time.Duration) Option {
+	return func(h *HX711) {
+		h.timeout = d
+	}
+}
+
+// New creates an HX711 on the clock and data pins.
+//
+// The clock pin is set to an output, powering up the HX711, and the data pin
+// to an input.  A conversion is read to set the gain of subsequent
+// conversions.  The pins are requested until the HX711 is closed.
+// The GPIO must be open.
+func New(sck, dout int, options ...Option) (*HX711, error) {
+	h := &HX711{
+		sck:     gpio.NewPin(sck),
+		dout:    gpio.NewPin(dout),
+		timeout: 500 * time.Millisecond,
+		scale:   1,
+	}
+	if h.sck == nil || h.dout == nil {
+		return nil, ErrInvalidPin
+	}
+	if err := gpio.RequestPins("hx711", h.sck, h.dout); err != nil {
+		return nil, err
+	}
+	for _, option := range options {
+		option(h)
+	}
+	h.dout.Input()
+	h.sck.Low()
+	h.sck.Output()
+	h.mu.Lock()
+	_, err := h.read()
+	h.mu.Unlock()
+	if err != nil {
+		gpio.ReleasePins(h.sck, h.dout)
+		return nil, err
+	}
+	return h, nil
+}
+
+// Close powers down the HX711 and returns the clock pin to an input.
+func (h *HX711) Close() {
+	h.PowerDown()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sck.Input()
+	gpio.ReleasePins(h.sck, h.dout)
+}
+
+// PowerDown puts the HX711 into its low power mode.
+func (h *HX711) PowerDown() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sck.Low()
+	h.sck.High()
+	gpio.Delay(100 * time.Microsecond)
+}
+
+// PowerUp returns the HX711 to normal operation.
+//
+// The HX711 resets to GainA128 when powered up, so a conversion is read to
+// restore the gain.
+func (h *HX711) PowerUp() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sck.Low()
+	_, err := h.read()
+	return err
+}
+
+// SetGain sets the channel and gain of subsequent conversions.
+//
+// As the gain is set while reading the preceding conversion, a conversion is
+// read, and discarded, to apply the gain.
+func (h *HX711) SetGain(g Gain) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.gain = g
+	_, err := h.read()
+	return err
+}
+
+// ReadRaw returns the next conversion, as a signed 24-bit value.
+func (h *HX711) ReadRaw() (int32, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.read()
+}
+
+// ReadAverage returns the mean of the next n conversions.
+func (h *HX711) ReadAverage(n int) (float64, error) {
+	if n < 1 {
+		n = 1
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.average(n)
+}
+
+// Tare sets the offset, subtracted from conversions by Read, to the mean of the
+// next n conversions, as when the load cell is unloaded.
+func (h *HX711) Tare(n int) error {
+	if n < 1 {
+		n = 1
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	offset, err := h.average(n)
+	if err != nil {
+		return err
+	}
+	h.offset = offset
+	return nil
+}
+
+// SetScale sets the scale, in counts per unit, applied to conversions by Read.
+//
+// The scale is typically determined by calibrating the tared load cell with a
+// known weight - it is the Read of the weight, with the default scale of 1,
+// divided by the weight.
+func (h *HX711) SetScale(scale float64) {
+	if scale == 0 {
+		return
+	}
+	h.mu.Lock()
+	h.scale = scale
+	h.mu.Unlock()
+}
+
+// Read returns the mean of the next n conversions, with the offset removed and
+// the scale applied.
+func (h *HX711) Read(n int) (float64, error) {
+	if n < 1 {
+		n = 1
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	v, err := h.average(n)
+	if err != nil {
+		return 0, err
+	}
+	return (v - h.offset) / h.scale, nil
+}
+
+// average returns the mean of the next n conversions.
+//
+// Assumes caller already holds the mu lock.
+func (h *HX711) average(n int) (float64, error) {
+	var sum int64
+	for i := 0; i < n; i++ {
+		v, err := h.read()
+		if err != nil {
+			return 0, err
+		}
+		sum += int64(v)
+	}
+	return float64(sum) / float64(n), nil
+}
+
+// read waits for a conversion and clocks it out, MSB first, followed by the
+// pulses selecting the gain of the next conversion.
+//
+// Assumes caller already holds the mu lock.
+func (h *HX711) read() (int32, error) {
+	deadline := time.Now().Add(h.timeout)
+	for h.dout.Read() == gpio.High {
+		if time.Now().After(deadline) {
+			return 0, ErrTimeout
+		}
+		time.Sleep(time.Millisecond)
+	}
+	var v int32
+	for i := 0; i < 24; i++ {
+		v <<= 1
+		if h.pulse() {
+			v |= 1
+		}
+	}
+	for i := 0; i < h.gain.pulses(); i++ {
+		h.pulse()
+	}
+	// sign extend the 24-bit two's complement value.
+	return v << 8 >> 8, nil
+}
+
+// pulse clocks the HX711 and returns the data bit it presents.
+func (h *HX711) pulse() gpio.Level {
+	h.sck.High()
+	gpio.Delay(time.Microsecond)
+	h.sck.Low()
+	gpio.Delay(time.Microsecond)
+	return h.dout.Read()
+}
+
+var (
+	// ErrInvalidPin indicates a pin is not a valid GPIO pin.
+	ErrInvalidPin = errors.New("invalid pin")
+
+	// ErrTimeout indicates the HX711 did not provide a conversion in time.
+	ErrTimeout = errors.New("timeout waiting for conversion")
+)
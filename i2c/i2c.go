@@ -0,0 +1,298 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package i2c provides a bit bashed I2C bus master using 2 GPIO pins.
+//
+// This is not related to the I2C device drivers provided by Linux, so may be
+// used on any pair of pins.  Both lines are open drain, so are driven low by
+// setting the pin to an output, and released high, by the bus pull-ups, by
+// setting the pin to an input.  Devices may stretch the clock by holding it
+// low.
+package i2c
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/warthog618/gpio"
+)
+
+// I2C is an I2C bus driven by a pair of GPIO pins.
+type I2C struct {
+	scl *gpio.Pin
+	sda *gpio.Pin
+	// time between clock edges (i.e. half the cycle time)
+	tclk time.Duration
+	// the maximum time a device may stretch the clock.
+	stretch time.Duration
+	// waits between clock edges.
+	delay func(time.Duration)
+
+	// Guards the bus.
+	mu sync.Mutex
+}
+
+// Option modifies the configuration of an I2C.
+type Option func(*I2C)
+
+// WithDelay sets the function used to wait between clock edges.
+//
+// The default is gpio.Delay, which busy waits for short delays.
+func WithDelay(delay func(time.Duration)) Option {
+	return func(bus *I2C) {
+		bus.delay = delay
+	}
+}
+
+// WithClockPeriod sets the period of the clock.
+//
+// The default is 10µs, the 100kHz standard mode supported by all devices.
+func WithClockPeriod(d time.Duration) Option {
+	return func(bus *I2C) {
+		if d > 0 {
+			bus.tclk = d / 2
+		}
+	}
+}
+
+// WithClockStretch sets the maximum time a device may hold the clock low
+// before the transfer fails with ErrTimeout.
+//
+// The default is 10ms.
+func WithClockStretch(d time.Duration) Option {
+	return func(bus *I2C) {
+		bus.stretch = d
+	}
+}
+
+// New creates an I2C bus on the clock and data pins.
+//
+// The pins are set to inputs, pulled up, releasing the bus.  The internal
+// pull-ups are weak, so external pull-ups are recommended, though most
+// modules provide them.  The pins are requested until the bus is closed.
+// The GPIO must be open.
+func New(scl, sda int, options ...Option) (*I2C, error) {
+	bus := &I2C{
+		scl:     gpio.NewPin(scl),
+		sda:     gpio.NewPin(sda),
+		tclk:    5 * time.Microsecond,
+		stretch: 10 * time.Millisecond,
+		delay:   gpio.Delay,
+	}
+	if bus.scl == nil || bus.sda == nil {
+		return nil, ErrInvalidPin
+	}
+	if err := gpio.RequestPins("i2c", bus.scl, bus.sda); err != nil {
+		return nil, err
+	}
+	for _, option := range options {
+		option(bus)
+	}
+	for _, pin := range []*gpio.Pin{bus.scl, bus.sda} {
+		// released, and only ever driven low.
+		pin.Input()
+		pin.PullUp()
+		pin.Low()
+	}
+	return bus, nil
+}
+
+// Close releases the bus.
+func (bus *I2C) Close() {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	bus.scl.Input()
+	bus.sda.Input()
+	gpio.ReleasePins(bus.scl, bus.sda)
+}
+
+// Write writes the data to the device at the 7-bit address.
+func (bus *I2C) Write(addr uint8, data []byte) error {
+	return bus.Tx(addr, data, nil)
+}
+
+// Read reads data from the device at the 7-bit address, filling the buffer.
+func (bus *I2C) Read(addr uint8, data []byte) error {
+	return bus.Tx(addr, nil, data)
+}
+
+// Tx writes the w data to the device at the 7-bit address, and then reads
+// into the r buffer after a repeated start, as a single transaction.
+//
+// This is typically used to write a register address and read the registers
+// from that address.  Either w or r may be empty, but not both.
+func (bus *I2C) Tx(addr uint8, w, r []byte) (err error) {
+	if addr > 0x7f {
+		return ErrInvalidAddress
+	}
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	defer func() {
+		if serr := bus.stop(); err == nil {
+			err = serr
+		}
+	}()
+	if len(w) > 0 || len(r) == 0 {
+		if err = bus.start(); err != nil {
+			return err
+		}
+		if err = bus.writeBytes(append([]byte{addr << 1}, w...)); err != nil {
+			return err
+		}
+	}
+	if len(r) == 0 {
+		return nil
+	}
+	if err = bus.start(); err != nil {
+		return err
+	}
+	if err = bus.writeBytes([]byte{addr<<1 | 1}); err != nil {
+		return err
+	}
+	for i := range r {
+		// the master acknowledges all but the last byte.
+		if r[i], err = bus.readByte(i < len(r)-1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// release allows the pin to be pulled high.
+func release(pin *gpio.Pin) {
+	pin.Input()
+}
+
+// drive pulls the pin low.
+func drive(pin *gpio.Pin) {
+	pin.Output()
+}
+
+// releaseClock releases the clock and waits for any clock stretching by the
+// device to end.
+func (bus *I2C) releaseClock() error {
+	release(bus.scl)
+	if bus.scl.Read() == gpio.High {
+		return nil
+	}
+	deadline := time.Now().Add(bus.stretch)
+	for bus.scl.Read() == gpio.Low {
+		if time.Now().After(deadline) {
+			return ErrTimeout
+		}
+	}
+	return nil
+}
+
+// start signals a start, or a repeated start - a falling data edge while the
+// clock is high - and leaves the clock low.
+func (bus *I2C) start() error {
+	release(bus.sda)
+	bus.delay(bus.tclk)
+	if err := bus.releaseClock(); err != nil {
+		return err
+	}
+	bus.delay(bus.tclk)
+	drive(bus.sda)
+	bus.delay(bus.tclk)
+	drive(bus.scl)
+	return nil
+}
+
+// stop signals a stop - a rising data edge while the clock is high - and
+// leaves the bus released.
+func (bus *I2C) stop() error {
+	drive(bus.sda)
+	bus.delay(bus.tclk)
+	err := bus.releaseClock()
+	bus.delay(bus.tclk)
+	release(bus.sda)
+	bus.delay(bus.tclk)
+	return err
+}
+
+// writeBit clocks out a bit.
+//
+// Assumes the clock starts low, and leaves it low.
+func (bus *I2C) writeBit(level gpio.Level) error {
+	if level == gpio.High {
+		release(bus.sda)
+	} else {
+		drive(bus.sda)
+	}
+	bus.delay(bus.tclk)
+	if err := bus.releaseClock(); err != nil {
+		return err
+	}
+	bus.delay(bus.tclk)
+	drive(bus.scl)
+	return nil
+}
+
+// readBit clocks in a bit.
+//
+// Assumes the clock starts low, and leaves it low.
+func (bus *I2C) readBit() (gpio.Level, error) {
+	release(bus.sda)
+	bus.delay(bus.tclk)
+	if err := bus.releaseClock(); err != nil {
+		return gpio.Low, err
+	}
+	bus.delay(bus.tclk)
+	level := bus.sda.Read()
+	drive(bus.scl)
+	return level, nil
+}
+
+// writeBytes clocks out the bytes, MSB first, checking that the device
+// acknowledges each.
+func (bus *I2C) writeBytes(data []byte) error {
+	for _, b := range data {
+		for i := uint(0); i < 8; i++ {
+			if err := bus.writeBit(b&(0x80>>i) != 0); err != nil {
+				return err
+			}
+		}
+		nack, err := bus.readBit()
+		if err != nil {
+			return err
+		}
+		if nack == gpio.High {
+			return ErrNack
+		}
+	}
+	return nil
+}
+
+// readByte clocks in a byte, MSB first, and acknowledges it if ack is true.
+func (bus *I2C) readByte(ack bool) (byte, error) {
+	var b byte
+	for i := 0; i < 8; i++ {
+		level, err := bus.readBit()
+		if err != nil {
+			return 0, err
+		}
+		b <<= 1
+		if level {
+			b |= 1
+		}
+	}
+	return b, bus.writeBit(gpio.Level(!ack))
+}
+
+var (
+	// ErrInvalidAddress indicates the address is not a 7-bit address.
+	ErrInvalidAddress = errors.New("invalid address")
+
+	// ErrInvalidPin indicates a pin is not a valid GPIO pin.
+	ErrInvalidPin = errors.New("invalid pin")
+
+	// ErrNack indicates the device did not acknowledge its address or data.
+	ErrNack = errors.New("not acknowledged")
+
+	// ErrTimeout indicates a device held the clock low for too long.
+	ErrTimeout = errors.New("clock stretch timeout")
+)
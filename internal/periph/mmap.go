@@ -0,0 +1,51 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build linux
+// +build linux
+
+package periph
+
+import (
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// MapPhys maps the physical memory at the address.
+//
+// The address must be page aligned.
+func MapPhys(addr int64, length int) (*Block, error) {
+	file, err := os.OpenFile("/dev/mem", os.O_RDWR|os.O_SYNC, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	mem8, err := unix.Mmap(
+		int(file.Fd()),
+		addr,
+		length,
+		unix.PROT_READ|unix.PROT_WRITE,
+		unix.MAP_SHARED)
+	if err != nil {
+		return nil, err
+	}
+	return &Block{
+		Regs: unsafe.Slice((*uint32)(unsafe.Pointer(&mem8[0])), len(mem8)/4),
+		mem8: mem8,
+	}, nil
+}
+
+// Close unmaps the block.
+func (b *Block) Close() error {
+	if b.mem8 == nil {
+		return nil
+	}
+	b.Regs = nil
+	err := unix.Munmap(b.mem8)
+	b.mem8 = nil
+	return err
+}
@@ -0,0 +1,66 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package periph provides access to the BCM283x peripheral registers via
+// /dev/mem, for peripherals not covered by /dev/gpiomem.
+//
+// Access to /dev/mem requires root privileges.
+package periph
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+)
+
+// BusBase is the address of the peripherals as seen by the DMA controller.
+const BusBase = 0x7e000000
+
+// Block is a mapped block of physical memory.
+type Block struct {
+	// Regs is the block as 32-bit registers.
+	Regs []uint32
+
+	mem8 []byte
+}
+
+var (
+	// ErrBase indicates the peripheral base address could not be determined.
+	ErrBase = errors.New("unable to determine peripheral base")
+
+	// ErrNotSupported indicates /dev/mem is not supported on the platform.
+	ErrNotSupported = errors.New("not supported on this platform")
+)
+
+// Base returns the physical address of the peripherals.
+//
+// The address is read from the device-tree, as per bcm_host_get_peripheral_address.
+func Base() (uint32, error) {
+	ranges, err := os.ReadFile("/proc/device-tree/soc/ranges")
+	if err != nil {
+		return 0, err
+	}
+	if len(ranges) < 8 {
+		return 0, ErrBase
+	}
+	base := binary.BigEndian.Uint32(ranges[4:8])
+	if base == 0 && len(ranges) >= 12 {
+		// 64-bit parent address, as on the BCM2711.
+		base = binary.BigEndian.Uint32(ranges[8:12])
+	}
+	if base == 0 {
+		return 0, ErrBase
+	}
+	return base, nil
+}
+
+// Map maps the peripheral registers at the offset from the peripheral base.
+func Map(offset, length int) (*Block, error) {
+	base, err := Base()
+	if err != nil {
+		return nil, err
+	}
+	return MapPhys(int64(base)+int64(offset), length)
+}
@@ -0,0 +1,19 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+// +build !linux
+
+package periph
+
+// MapPhys returns ErrNotSupported, as /dev/mem is only supported on Linux.
+func MapPhys(addr int64, length int) (*Block, error) {
+	return nil, ErrNotSupported
+}
+
+// Close unmaps the block.
+func (b *Block) Close() error {
+	return nil
+}
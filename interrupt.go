@@ -5,20 +5,18 @@
 
 // Interrupt capabilities for DIO Pins.
 
-//go:build linux
-// +build linux
-
 package gpio
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
-	"strconv"
+	"runtime"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
-
-	"golang.org/x/sys/unix"
 )
 
 const (
@@ -43,10 +41,160 @@ const (
 	EdgeBoth Edge = "both"
 )
 
+// WatchMechanism identifies the mechanism used by a Watcher to detect edges.
+type WatchMechanism int
+
+const (
+	// WatchSysfs indicates edges are detected by interrupts via the sysfs GPIO
+	// interface.
+	WatchSysfs WatchMechanism = iota
+
+	// WatchPoll indicates edges are detected by polling the level registers.
+	//
+	// This is used when the sysfs GPIO interface is not available, and for
+	// any pin that cannot be exported via sysfs.
+	WatchPoll
+)
+
+// The default period between polls of the level registers when using
+// WatchPoll.
+const pollPeriod = time.Millisecond
+
+// The default depth of the per-pin event queue when using serial dispatch.
+const queueDepth = 64
+
+// OverflowPolicy determines how events are handled when a pin's event queue is
+// full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock blocks the Watcher until there is space in the queue.
+	//
+	// No events are lost, but the delivery of events for other pins is delayed.
+	OverflowBlock OverflowPolicy = iota
+
+	// OverflowDropOldest discards the oldest event in the queue to make space
+	// for the new event.
+	OverflowDropOldest
+
+	// OverflowDropNewest discards the new event.
+	OverflowDropNewest
+)
+
+// PinStats contains the statistics for a watched pin.
+type PinStats struct {
+	// The number of events detected, including any dropped.
+	Events uint64
+
+	// The number of events discarded due to queue overflow.
+	Dropped uint64
+
+	// The number of events discarded as they exceeded the maximum rate of
+	// their watch.
+	Throttled uint64
+
+	// The number of handler calls that panicked.
+	//
+	// This is only counted if the Watcher has a panic handler.
+	HandlerPanics uint64
+}
+
+// Event describes a level transition on a watched Pin.
+type Event struct {
+	// The pin that triggered the event.
+	Pin *Pin
+
+	// The level of the pin when the event was detected.
+	Level Level
+
+	// The time the event was detected.
+	//
+	// This includes a monotonic clock reading so is suitable for measuring
+	// the time between events.
+	Time time.Time
+}
+
+// interrupt contains the state of the watches on a single pin.
 type interrupt struct {
-	pin       *Pin
-	handler   func(*Pin)
+	pin *Pin
+
+	// The edge detected by the kernel, or by polling.
+	// This is the union of the edges of the watches.
+	edge Edge
+
+	// The watches on the pin.
+	// This is replaced, not modified in place, so may be read after unlocking.
+	watches []*Watch
+
+	// true if the pin was registered via RegisterPin so cannot be shared.
+	exclusive bool
+
+	// valueFile and synced are only used by WatchSysfs.
 	valueFile *os.File
+	// true once the initial sysfs event has been received.
+	synced bool
+	// true if the Watcher exported the pin, so should unexport it.
+	exported bool
+
+	// true if the pin is polled rather than watched via sysfs.
+	polled bool
+
+	// the reason the pin could not be watched via sysfs, if polled.
+	sysfsErr error
+
+	// level is only used by WatchPoll.
+	level Level
+}
+
+// Watch is a single watch on a pin.
+//
+// Multiple watches may be added to a pin using AddWatch, and each may be
+// removed independently.
+type Watch struct {
+	// Accessed atomically so must be first to ensure 64-bit alignment.
+	events    uint64
+	dropped   uint64
+	panics    uint64
+	throttled uint64
+	// The time of the last event passed by the rate limit, relative to start.
+	last int64
+	// The number of edges counted, for counting watches.
+	count uint64
+	// set once the initial level has been seen, for counting watches.
+	synced int32
+
+	// true if edges are counted rather than delivered to a handler.
+	counting bool
+
+	// The minimum period between events, or 0 if the rate is not limited.
+	interval time.Duration
+	start    time.Time
+
+	watcher *Watcher
+	irq     *interrupt
+	pin     *Pin
+	edge    Edge
+	handler func(Event)
+	// queue and policy are only used by serial dispatch.
+	queue  chan Event
+	policy OverflowPolicy
+	// closed when the watch is removed.
+	done chan struct{}
+	// called with the value recovered from a panicking handler.
+	onPanic func(Event, interface{})
+}
+
+// dispatcher delivers the queued events to the handler, in order, until the
+// watch is removed.
+func (wt *Watch) dispatcher() {
+	for {
+		select {
+		case evt := <-wt.queue:
+			wt.call(evt)
+		case <-wt.done:
+			return
+		}
+	}
 }
 
 // Watcher monitors the pins for level transitions that trigger interrupts.
@@ -56,15 +204,60 @@ type Watcher struct {
 
 	epfd int
 
-	// Map from pin to value Fd.
-	interruptFds map[int]int
-
-	// Map from pin Fd to interrupt
+	// Map from pin to interrupt.
 	interrupts map[int]*interrupt
 
+	// Map from pin value Fd to interrupt, for WatchSysfs.
+	fds map[int]*interrupt
+
+	// The mechanism used to detect edges.
+	mechanism WatchMechanism
+
+	// The period between polls of polled pins.
+	pollPeriod time.Duration
+
+	// The number of polled pins.
+	npolled int
+
+	// true if events are delivered by a dispatcher goroutine per watch.
+	serial bool
+
+	// The depth of the per-watch event queue for serial dispatch.
+	queueDepth int
+
+	// The policy applied when a per-watch event queue is full.
+	policy OverflowPolicy
+
+	// called with the value recovered from a panicking handler.
+	onPanic func(Event, interface{})
+
+	// true if output pins are set to inputs when watched.
+	autoInput bool
+
+	// true if pins already exported to sysfs are watched via sysfs.
+	shareExports bool
+
+	// true if the watch goroutine is locked to its OS thread.
+	lockThread bool
+
+	// The SCHED_FIFO priority of the watch goroutine thread, or 0 to leave
+	// the thread with the default scheduling policy.
+	priority int
+
+	// true if handlers are called directly from the watch goroutine, and
+	// memory is locked and pre-faulted.
+	realtime bool
+
+	// true if events are processed by the application's event loop, via
+	// Process, rather than by a watch goroutine.
+	external bool
+
 	// closed when the watcher exits.
 	doneCh chan struct{}
 
+	// closed to stop the poll loop, for WatchPoll.
+	stopCh chan struct{}
+
 	// fds of the pipe for the shutdown handshake.
 	donefds []int
 
@@ -74,80 +267,609 @@ type Watcher struct {
 
 var defaultWatcher *Watcher
 
-func getDefaultWatcher() *Watcher {
+func getDefaultWatcher() (*Watcher, error) {
 	memlock.Lock()
+	defer memlock.Unlock()
 	if defaultWatcher == nil {
-		defaultWatcher = NewWatcher()
+		w, err := NewWatcher()
+		if err != nil {
+			return nil, err
+		}
+		defaultWatcher = w
+	}
+	return defaultWatcher, nil
+}
+
+// WatcherOption modifies the configuration of a Watcher.
+type WatcherOption func(*Watcher)
+
+// WithExternalLoop has the Watcher driven by the application's own event
+// loop, rather than by a dedicated watch goroutine.
+//
+// The application waits for Fd to become readable, such as with its own
+// epoll or poll, then calls Process to dispatch the pending events.
+// Polled pins are only sampled when Process is called, so it should also be
+// called at least every poll period while pins are polled.
+//
+// The options that configure the watch goroutine, such as WithLockedThread
+// and WithRealtime, have no effect.
+func WithExternalLoop() WatcherOption {
+	return func(w *Watcher) {
+		w.external = true
+	}
+}
+
+// WithSerialDispatch delivers the events for each watch sequentially, in
+// order, from a dispatcher goroutine dedicated to that watch.
+//
+// By default each event is delivered by a new goroutine, so events may be
+// delivered out of order and handlers may be called concurrently.
+//
+// With serial dispatch the handler for a watch is never called concurrently,
+// but a slow handler will delay the delivery of events for all pins once its
+// queue is full.
+//
+// This is equivalent to WithEventQueue with a depth of 64 and OverflowBlock.
+func WithSerialDispatch() WatcherOption {
+	return WithEventQueue(queueDepth, OverflowBlock)
+}
+
+// WatchOption modifies the configuration of a Watch.
+type WatchOption func(*Watch)
+
+// WithMaxRate limits the rate of events delivered to the watch to rate events
+// per second, so a noisy input, such as a failing sensor, cannot consume all
+// the CPU calling handlers.
+//
+// Events that arrive within 1/rate of the last delivered event are discarded,
+// and counted in the Throttled field of Stats.
+// A rate of zero or less does not limit the rate.
+func WithMaxRate(rate float64) WatchOption {
+	return func(wt *Watch) {
+		if rate > 0 {
+			wt.interval = time.Duration(float64(time.Second) / rate)
+		}
+	}
+}
+
+// WithEventQueue delivers the events for each watch sequentially, as per
+// WithSerialDispatch, with a queue of the given depth and the policy to apply
+// when the queue is full.
+//
+// The number of events dropped due to overflow is available from Stats.
+func WithEventQueue(depth int, policy OverflowPolicy) WatcherOption {
+	return func(w *Watcher) {
+		if depth < 1 {
+			depth = 1
+		}
+		w.serial = true
+		w.queueDepth = depth
+		w.policy = policy
+	}
+}
+
+// WithPanicHandler recovers panics in handlers and passes the event being
+// handled and the recovered value to the panic handler.
+//
+// By default handler panics are not recovered.
+func WithPanicHandler(ph func(evt Event, r interface{})) WatcherOption {
+	return func(w *Watcher) {
+		w.onPanic = ph
+	}
+}
+
+// WithPollPeriod sets the period between polls of the level registers for
+// pins that are polled.
+//
+// Other than for a Watcher created by NewPollWatcher, the period is rounded up
+// to a whole number of milliseconds.
+// The default is 1ms.
+func WithPollPeriod(period time.Duration) WatcherOption {
+	return func(w *Watcher) {
+		if period > 0 {
+			w.pollPeriod = period
+		}
+	}
+}
+
+// WithAutoInput sets output pins to inputs when they are watched, rather than
+// failing with ErrNotInput.
+func WithAutoInput() WatcherOption {
+	return func(w *Watcher) {
+		w.autoInput = true
+	}
+}
+
+// WithSharedExports watches pins already exported to sysfs, such as by a
+// script or another process, via sysfs rather than polling them.
+//
+// The Watcher only unexports the pins it exported itself, so pins exported
+// externally remain exported when unwatched or the Watcher is closed, though
+// their edge is changed while watched.
+func WithSharedExports() WatcherOption {
+	return func(w *Watcher) {
+		w.shareExports = true
+	}
+}
+
+// WithLockedThread locks the watch goroutine to its own OS thread, so it is
+// not delayed by the scheduling of other goroutines.
+func WithLockedThread() WatcherOption {
+	return func(w *Watcher) {
+		w.lockThread = true
+	}
+}
+
+// WithPriority runs the watch goroutine on a locked OS thread with the
+// SCHED_FIFO real-time scheduling policy at the given priority, from 1 to 99,
+// reducing the latency between an edge and its detection.
+//
+// Setting the priority requires CAP_SYS_NICE, or a suitable RLIMIT_RTPRIO,
+// and NewWatcher returns an error if the priority cannot be set.
+//
+// Only the watch goroutine is affected - handlers are called from other
+// goroutines.
+func WithPriority(priority int) WatcherOption {
+	return func(w *Watcher) {
+		w.lockThread = true
+		w.priority = priority
+	}
+}
+
+// WithRealtime calls the handlers directly from the watch goroutine, running
+// on a locked OS thread with the SCHED_FIFO real-time scheduling policy at the
+// given priority, from 1 to 99.
+//
+// The memory of the process is locked, and the stack of the watch goroutine
+// pre-faulted, so handling an event does not incur page faults.
+//
+// This minimises the jitter between an edge and its handler being called, but
+// handlers must return promptly as no further events are detected until they
+// do, and must not Close the Watcher.  Handlers may change pins, such as with
+// SetMode and SetPull, including while the Watcher is being closed by
+// CloseWatcher or Close.  Serial dispatch options are ignored.
+//
+// Requires CAP_SYS_NICE and CAP_IPC_LOCK, or suitable resource limits, and
+// NewWatcher returns an error if the thread cannot be configured.
+func WithRealtime(priority int) WatcherOption {
+	return func(w *Watcher) {
+		w.lockThread = true
+		w.priority = priority
+		w.realtime = true
 	}
-	memlock.Unlock()
-	return defaultWatcher
 }
 
 // NewWatcher creates a goroutine that watches Pins for transitions that trigger
 // interrupts.
-func NewWatcher() *Watcher {
-	epfd, err := unix.EpollCreate1(0)
-	if err != nil {
-		panic(fmt.Sprintf("Unable to create epoll: %v", err))
-	}
-	p := []int{0, 0}
-	err = unix.Pipe2(p, unix.O_CLOEXEC)
-	if err != nil {
-		panic(fmt.Sprintf("Unable to create pipe: %v", err))
+//
+// If the sysfs GPIO interface is not available then the Watcher falls back to
+// polling the pin levels.  Similarly, any pin that cannot be watched via sysfs,
+// such as a pin the kernel refuses to export, is polled.
+//
+// Returns an error if the resources required by the Watcher cannot be
+// allocated.
+func NewWatcher(options ...WatcherOption) (*Watcher, error) {
+	mechanism := WatchSysfs
+	if !sysfsAvailable() {
+		mechanism = WatchPoll
 	}
-	epv := unix.EpollEvent{Events: unix.EPOLLIN, Fd: int32(p[0])}
-	unix.EpollCtl(epfd, unix.EPOLL_CTL_ADD, int(p[0]), &epv)
+	return newWatcher(mechanism, options...)
+}
+
+func newWatcher(mechanism WatchMechanism, options ...WatcherOption) (*Watcher, error) {
 	w := &Watcher{
-		epfd:         epfd,
-		interruptFds: make(map[int]int),
-		interrupts:   make(map[int]*interrupt),
-		doneCh:       make(chan struct{}),
-		donefds:      p,
+		interrupts: make(map[int]*interrupt),
+		fds:        make(map[int]*interrupt),
+		doneCh:     make(chan struct{}),
+		stopCh:     make(chan struct{}),
+		mechanism:  mechanism,
+		pollPeriod: pollPeriod,
+	}
+	if err := w.openPoller(); err != nil {
+		return nil, err
+	}
+	for _, option := range options {
+		option(w)
+	}
+	if w.external {
+		return w, nil
+	}
+	ready := make(chan error, 1)
+	go w.run(ready)
+	if err := <-ready; err != nil {
+		w.Close()
+		return nil, err
 	}
-	go w.watch()
+	return w, nil
+}
 
-	return w
+// run prepares the thread of the watch goroutine, reporting the result on
+// ready, then watches for events.
+func (w *Watcher) run(ready chan<- error) {
+	if w.lockThread {
+		// never unlocked, so the thread, and any priority applied to it,
+		// is discarded when the watcher exits.
+		runtime.LockOSThread()
+	}
+	var err error
+	if w.priority != 0 || w.realtime {
+		err = setRealtime(w.priority)
+	}
+	if err == nil && w.realtime {
+		prefaultStack()
+		err = lockMemory()
+	}
+	ready <- err
+	if w.mechanism == WatchPoll {
+		w.pollLoop()
+		return
+	}
+	w.watch()
 }
 
-func (w *Watcher) watch() {
-	var epollEvents [MaxGPIOInterrupt]unix.EpollEvent
+// pollLoop polls the pins every poll period until the watcher is closed.
+func (w *Watcher) pollLoop() {
 	defer close(w.doneCh)
+	defer w.releasePoller()
+	ticker := time.NewTicker(w.pollPeriod)
+	defer ticker.Stop()
 	for {
-		n, err := unix.EpollWait(w.epfd, epollEvents[:], -1)
-		if err != nil {
-			if err == unix.EBADF || err == unix.EINVAL {
-				// fd closed so exit
+		select {
+		case now := <-ticker.C:
+			w.poll(now)
+		case <-w.stopCh:
+			return
+		}
+	}
+}
+
+// NewPollWatcher creates a Watcher that detects edges by sampling the level
+// registers of the watched pins every period.
+//
+// The sysfs GPIO interface is not used, so this works for pins the kernel
+// refuses to export, and the latency between an edge and its detection is
+// bounded by the period, so is more predictable than with interrupts.
+// Edges of pulses shorter than the period may be missed.
+//
+// Periods below 1ms are supported, but the CPU load increases accordingly.
+// Returns ErrInvalidPeriod if the period is not positive.
+func NewPollWatcher(period time.Duration, options ...WatcherOption) (*Watcher, error) {
+	if period <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+	options = append([]WatcherOption{WithPollPeriod(period)}, options...)
+	return newWatcher(WatchPoll, options...)
+}
+
+// NewWatcherCtx creates a Watcher that is closed when the context is done.
+func NewWatcherCtx(ctx context.Context, options ...WatcherOption) (*Watcher, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	w, err := NewWatcher(options...)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			w.Close()
+		case <-w.doneCh:
+		}
+	}()
+	return w, nil
+}
+
+// Mechanism returns the mechanism the Watcher uses to detect edges.
+//
+// Individual pins may be polled even if this is WatchSysfs - the mechanism
+// used for a particular pin is available from the Mechanism of its watches.
+func (w *Watcher) Mechanism() WatchMechanism {
+	return w.mechanism
+}
+
+// Mechanism returns the mechanism used to detect edges on the watched pin.
+func (wt *Watch) Mechanism() WatchMechanism {
+	if wt.irq.polled {
+		return WatchPoll
+	}
+	return WatchSysfs
+}
+
+// SysfsErr returns the reason the pin is polled rather than watched via sysfs,
+// or nil if it is watched via sysfs or the Watcher only polls.
+//
+// If the pin is held by another consumer, such as a kernel driver or another
+// process, this wraps a *BusyError identifying the consumer.
+func (wt *Watch) SysfsErr() error {
+	return wt.irq.sysfsErr
+}
+
+// BusyError indicates the pin could not be exported to sysfs as it is held by
+// another consumer.
+//
+// It matches ErrBusy when tested with errors.Is.
+type BusyError struct {
+	// The label of the kernel consumer holding the pin, or empty if it could
+	// not be determined.
+	Consumer string
+}
+
+func (e *BusyError) Error() string {
+	if e.Consumer == "" {
+		return ErrBusy.Error()
+	}
+	return fmt.Sprintf("%s by %q", ErrBusy, e.Consumer)
+}
+
+// Is returns true if target is ErrBusy.
+func (e *BusyError) Is(target error) bool {
+	return target == ErrBusy
+}
+
+// trigger is an event to be dispatched to the watches on a pin.
+type trigger struct {
+	watches []*Watch
+	evt     Event
+}
+
+// trigger determines the watches interested in the event.
+//
+// If sync is true then the event goes to all watches.
+//
+// Assumes the caller holds the lock.
+func (irq *interrupt) trigger(evt Event, sync bool) trigger {
+	t := trigger{evt: evt}
+	for _, wt := range irq.watches {
+		// if the watch edge differs from the detected edge then it is more
+		// restrictive so filter on level.
+		if sync || wt.edge == irq.edge || wt.edge.triggeredBy(evt.Level) {
+			t.watches = append(t.watches, wt)
+		}
+	}
+	return t
+}
+
+// dispatch delivers the event to the watches.
+//
+// Performed outside the lock as serial dispatch may block.
+func (t trigger) dispatch() {
+	for _, wt := range t.watches {
+		wt.dispatch(t.evt)
+	}
+}
+
+// poll checks the levels of the polled pins and calls the handlers of any
+// that have seen a triggering edge since the last poll.
+func (w *Watcher) poll(now time.Time) {
+	var triggers []trigger
+	w.Lock()
+	for _, irq := range w.interrupts {
+		if !irq.polled {
+			continue
+		}
+		level := irq.pin.level()
+		if level == irq.level {
+			continue
+		}
+		irq.level = level
+		if irq.edge.triggeredBy(level) {
+			triggers = append(triggers, irq.trigger(Event{Level: level, Time: now}, false))
+		}
+	}
+	w.Unlock()
+	for _, t := range triggers {
+		t.dispatch()
+	}
+}
+
+// triggeredBy returns true if a transition to the level is one of the edges.
+func (edge Edge) triggeredBy(level Level) bool {
+	switch edge {
+	case EdgeBoth:
+		return true
+	case EdgeRising:
+		return level == High
+	case EdgeFalling:
+		return level == Low
+	}
+	return false
+}
+
+// unionEdge returns the edge that covers both edges.
+func unionEdge(a, b Edge) Edge {
+	switch {
+	case a == b, b == EdgeNone:
+		return a
+	case a == EdgeNone:
+		return b
+	}
+	return EdgeBoth
+}
+
+// prefaultStack grows the stack of the calling goroutine so it need not be
+// grown while handling events.
+//
+//go:noinline
+func prefaultStack() {
+	var stack [prefaultStackSize]byte
+	for i := range stack {
+		stack[i] = 1
+	}
+}
+
+// prefaultStackSize is the number of bytes of stack touched by prefaultStack.
+const prefaultStackSize = 64 * 1024
+
+// dispatch delivers the event to the handler.
+func (wt *Watch) dispatch(evt Event) {
+	evt.Pin = wt.pin
+	atomic.AddUint64(&wt.events, 1)
+	if wt.counting {
+		// the first event is the initial level, not an edge.
+		if !atomic.CompareAndSwapInt32(&wt.synced, 0, 1) {
+			atomic.AddUint64(&wt.count, 1)
+		}
+		return
+	}
+	if wt.interval > 0 && !wt.allow(evt.Time) {
+		atomic.AddUint64(&wt.throttled, 1)
+		return
+	}
+	if wt.watcher.realtime {
+		wt.call(evt)
+		return
+	}
+	if wt.queue == nil {
+		go wt.call(evt)
+		return
+	}
+	switch wt.policy {
+	case OverflowDropNewest:
+		select {
+		case wt.queue <- evt:
+		default:
+			atomic.AddUint64(&wt.dropped, 1)
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case wt.queue <- evt:
 				return
+			default:
 			}
-			if err == unix.EINTR {
-				continue
+			select {
+			case <-wt.queue:
+				atomic.AddUint64(&wt.dropped, 1)
+			default:
 			}
-			panic(fmt.Sprintf("EpollWait error: %v", err))
 		}
-		for i := 0; i < n; i++ {
-			event := epollEvents[i]
-			if event.Fd == int32(w.donefds[0]) {
-				unix.Close(w.epfd)
-				unix.Close(w.donefds[0])
-				return
-			}
-			w.Lock()
-			irq, ok := w.interrupts[int(event.Fd)]
-			w.Unlock()
-			if ok {
-				go irq.handler(irq.pin)
+	default:
+		select {
+		case wt.queue <- evt:
+		case <-wt.done:
+		}
+	}
+}
+
+// allow returns true if an event at time t does not exceed the maximum rate.
+func (wt *Watch) allow(t time.Time) bool {
+	now := int64(t.Sub(wt.start))
+	for {
+		last := atomic.LoadInt64(&wt.last)
+		if now-last < int64(wt.interval) {
+			return false
+		}
+		if atomic.CompareAndSwapInt64(&wt.last, last, now) {
+			return true
+		}
+	}
+}
+
+// call calls the handler, recovering any panic if there is a panic handler.
+func (wt *Watch) call(evt Event) {
+	if wt.onPanic != nil {
+		defer func() {
+			if r := recover(); r != nil {
+				atomic.AddUint64(&wt.panics, 1)
+				wt.onPanic(evt, r)
 			}
+		}()
+	}
+	wt.handler(evt)
+}
+
+// newWatch creates a watch on the interrupt, starting its dispatcher if
+// required.
+func (w *Watcher) newWatch(irq *interrupt, pin *Pin, edge Edge, handler func(Event), options []WatchOption) *Watch {
+	wt := &Watch{
+		watcher: w,
+		irq:     irq,
+		pin:     pin,
+		edge:    edge,
+		handler: handler,
+		done:    make(chan struct{}),
+		onPanic: w.onPanic,
+		start:   time.Now(),
+	}
+	for _, option := range options {
+		option(wt)
+	}
+	// so the first event is always delivered.
+	wt.last = -int64(wt.interval)
+	if w.serial && !w.realtime && !wt.counting {
+		wt.queue = make(chan Event, w.queueDepth)
+		wt.policy = w.policy
+		go wt.dispatcher()
+	}
+	return wt
+}
+
+// Stats returns the statistics for the currently watched pins, keyed by pin
+// number.
+//
+// Where a pin has several watches the statistics are the totals for all of
+// them.
+func (w *Watcher) Stats() map[int]PinStats {
+	w.Lock()
+	defer w.Unlock()
+	stats := make(map[int]PinStats)
+	for pin, irq := range w.interrupts {
+		var s PinStats
+		for _, wt := range irq.watches {
+			s.Events += atomic.LoadUint64(&wt.events)
+			s.Dropped += atomic.LoadUint64(&wt.dropped)
+			s.Throttled += atomic.LoadUint64(&wt.throttled)
+			s.HandlerPanics += atomic.LoadUint64(&wt.panics)
 		}
+		stats[pin] = s
 	}
+	return stats
 }
 
+// Pins returns the numbers of the currently watched pins, in ascending order.
+func (w *Watcher) Pins() []int {
+	w.Lock()
+	pins := make([]int, 0, len(w.interrupts))
+	for pin := range w.interrupts {
+		pins = append(pins, pin)
+	}
+	w.Unlock()
+	sort.Ints(pins)
+	return pins
+}
+
+// CloseWatcher closes the default Watcher, removing the watches added to pins
+// by Watch, WatchCtx and AddWatch, without closing the GPIO.
+//
+// A new default Watcher is created by the next watch.
+func CloseWatcher() {
+	closeInterrupts()
+}
+
+// watchesActive returns true if the default Watcher is watching any pins.
+func watchesActive() bool {
+	memlock.Lock()
+	watcher := defaultWatcher
+	memlock.Unlock()
+	return watcher != nil && len(watcher.Pins()) > 0
+}
+
+// closeInterrupts closes the default Watcher.
+//
+// The Watcher is closed after the memlock is released, as closing waits for
+// the watch goroutine, on which realtime handlers are called, and they may
+// take the memlock, such as by calling SetMode.
+//
+// Assumes the caller does not hold the memlock.
 func closeInterrupts() {
+	memlock.Lock()
 	watcher := defaultWatcher
-	if watcher == nil {
-		return
-	}
 	defaultWatcher = nil
-	watcher.Close()
+	memlock.Unlock()
+	if watcher != nil {
+		watcher.Close()
+	}
 }
 
 // Close - His watch has ended.
@@ -158,79 +880,325 @@ func (w *Watcher) Close() {
 		return
 	}
 	w.closed = true
-	unix.Write(w.donefds[1], []byte("bye"))
-	for fd := range w.interrupts {
-		intr := w.interrupts[fd]
-		intr.valueFile.Close()
-		unexport(intr.pin)
+	w.wake()
+	close(w.stopCh)
+	for _, irq := range w.interrupts {
+		for _, wt := range irq.watches {
+			close(wt.done)
+		}
+		irq.watches = nil
+		if irq.valueFile != nil {
+			releaseSysfs(irq)
+		}
 	}
 	w.interrupts = nil
-	w.interruptFds = nil
+	w.fds = nil
 	w.Unlock()
+	if w.external {
+		w.releasePoller()
+		close(w.doneCh)
+	}
 	<-w.doneCh
-	unix.Close(w.donefds[1])
+	w.closePoller()
+}
+
+// Fd returns the file descriptor that becomes readable when the Watcher has
+// events to process, for a Watcher created WithExternalLoop.
+//
+// The descriptor is owned by the Watcher and is closed by Close.
+func (w *Watcher) Fd() int {
+	return w.epfd
+}
+
+// Process dispatches any pending events, and samples any polled pins, for a
+// Watcher created WithExternalLoop.
+//
+// Process does not block, other than to queue events for handlers when using
+// serial dispatch.
+// Returns ErrNotExternal if the Watcher has its own watch goroutine, and
+// ErrClosed once the Watcher is closed.
+func (w *Watcher) Process() error {
+	if !w.external {
+		return ErrNotExternal
+	}
+	w.Lock()
+	closed := w.closed
+	w.Unlock()
+	if closed || !w.process(false) {
+		return ErrClosed
+	}
+	return nil
 }
 
 // RegisterPin creates a watch on the given pin.
 //
 // The pin can only be registered once.  Subsequent registers,
 // without an Unregister, will return an error.
-func (w *Watcher) RegisterPin(pin *Pin, edge Edge, handler func(*Pin)) (err error) {
+// Registration also fails if the pin has watches added by AddWatch.
+//
+// Returns ErrNotInput if the pin is an output, unless the Watcher was created
+// WithAutoInput.
+func (w *Watcher) RegisterPin(pin *Pin, edge Edge, handler func(*Pin)) error {
+	_, err := w.register(pin, edge, func(Event) { handler(pin) }, true, nil)
+	return err
+}
+
+// AddWatch adds a watch on the given pin.
+//
+// Unlike RegisterPin, a pin may have several watches added by AddWatch, each
+// with its own edge and handler.
+// The handler is called immediately, to allow the handler to initialise its
+// state with the current level, and then on the specified edges.
+// With serial dispatch the initial event is delivered before any edges, but
+// by default each event is delivered by its own goroutine, so handlers should
+// compare the level of events with their state rather than assume the first
+// event is the initial level.
+//
+// The watch is removed by its Unwatch method, or by UnregisterPin which
+// removes all watches on the pin.
+//
+// The options, such as WithMaxRate, apply to this watch only.
+//
+// Returns ErrNotInput if the pin is an output, unless the Watcher was created
+// WithAutoInput.
+func (w *Watcher) AddWatch(pin *Pin, edge Edge, handler func(Event), options ...WatchOption) (*Watch, error) {
+	return w.register(pin, edge, handler, false, options)
+}
+
+func (w *Watcher) register(pin *Pin, edge Edge, handler func(Event), exclusive bool, options []WatchOption) (*Watch, error) {
+	wt, initial, err := w.addInterrupt(pin, edge, handler, exclusive, options)
+	if initial != nil {
+		wt.dispatch(*initial)
+	}
+	return wt, err
+}
+
+// addInterrupt adds the watch to the interrupt for the pin, creating the
+// interrupt if necessary.
+//
+// The initial event, if any, is dispatched to the watch before the lock is
+// released, so it precedes any edges subsequently detected on the pin, other
+// than for realtime watches, for which the initial event is returned to be
+// dispatched after the lock is released.
+func (w *Watcher) addInterrupt(pin *Pin, edge Edge, handler func(Event), exclusive bool, options []WatchOption) (wt *Watch, initial *Event, err error) {
 	w.Lock()
 	defer w.Unlock()
 
-	_, ok := w.interruptFds[pin.pin]
-	if ok {
-		return ErrBusy
+	if w.closed {
+		return nil, nil, ErrClosed
 	}
-	if err = export(pin); err != nil {
-		return err
+	if err := pin.usable(); err != nil {
+		return nil, nil, pinError("watch", pin.pin, err)
 	}
-	defer func() {
-		if err != nil {
-			unexport(pin)
+	if pin.Mode() == Output && w.autoInput {
+		pin.Input()
+	}
+	if pin.Mode() == Output {
+		// the level would only reflect what the pin is driving.
+		return nil, nil, pinError("watch", pin.pin, ErrNotInput)
+	}
+	if irq, ok := w.interrupts[pin.pin]; ok {
+		if exclusive || irq.exclusive {
+			return nil, nil, pinError("watch", pin.pin, ErrBusy)
 		}
-	}()
-	if err = setEdge(pin, edge); err != nil {
-		return err
+		if err = w.setEdge(irq, unionEdge(irq.edge, edge)); err != nil {
+			return nil, nil, err
+		}
+		wt = w.newWatch(irq, pin, edge, handler, options)
+		irq.watches = append(append([]*Watch(nil), irq.watches...), wt)
+		if irq.polled || irq.synced {
+			initial = wt.queueInitial(Event{Level: pin.level(), Time: time.Now()})
+		}
+		return wt, initial, nil
 	}
-	valueFile, err := openValue(pin)
-	if err != nil {
-		return err
+	irq := &interrupt{pin: pin, edge: edge, exclusive: exclusive}
+	if w.mechanism != WatchPoll {
+		if irq.sysfsErr = w.addSysfs(irq); irq.sysfsErr != nil {
+			logf("GPIO%d: polling as sysfs unavailable", pin.pin)
+		}
+	}
+	if w.mechanism == WatchPoll || irq.sysfsErr != nil {
+		irq.polled = true
+		w.npolled++
+		if w.npolled == 1 && w.mechanism != WatchPoll {
+			// start polling
+			w.kick()
+		}
+		irq.level = pin.level()
+		wt = w.newWatch(irq, pin, edge, handler, options)
+		irq.watches = []*Watch{wt}
+		w.interrupts[pin.pin] = irq
+		// mirror the initial sysfs interrupt.
+		return wt, wt.queueInitial(Event{Level: irq.level, Time: time.Now()}), nil
+	}
+	wt = w.newWatch(irq, pin, edge, handler, options)
+	irq.watches = []*Watch{wt}
+	w.interrupts[pin.pin] = irq
+	return wt, nil, nil
+}
+
+// queueInitial dispatches the initial event to the watch, or returns it if
+// the watch is realtime.
+//
+// Realtime handlers are called directly, and may call back into the Watcher,
+// so must be called without the lock.  Other watches only queue the event, or
+// count it, so are dispatched with the lock held, ensuring the initial event
+// precedes any edges.
+//
+// Assumes the caller holds the lock.
+func (wt *Watch) queueInitial(evt Event) *Event {
+	if wt.watcher.realtime && !wt.counting {
+		return &evt
 	}
-	pinFd := int(valueFile.Fd())
+	wt.dispatch(evt)
+	return nil
+}
 
-	event := unix.EpollEvent{Events: unix.EPOLLET & 0xffffffff}
-	if err = unix.SetNonblock(pinFd, true); err != nil {
+// SetEdge changes the edge of the watches on the pin.
+//
+// The pin remains exported and registered with epoll, so this is much
+// quicker than unregistering and reregistering the pin.
+// Returns ErrNotWatched if the pin is not watched.
+func (w *Watcher) SetEdge(pin *Pin, edge Edge) error {
+	w.Lock()
+	defer w.Unlock()
+
+	if w.closed {
+		return ErrClosed
+	}
+	irq, ok := w.interrupts[pin.pin]
+	if !ok {
+		return pinError("set edge", pin.pin, ErrNotWatched)
+	}
+	if err := w.setEdge(irq, edge); err != nil {
 		return err
 	}
-	event.Fd = int32(pinFd)
-	if err := unix.EpollCtl(w.epfd, unix.EPOLL_CTL_ADD, pinFd, &event); err != nil {
+	for _, wt := range irq.watches {
+		wt.edge = edge
+	}
+	return nil
+}
+
+// SetEdge changes the edge of the watch.
+//
+// Returns ErrNotWatched if the watch has been removed.
+func (wt *Watch) SetEdge(edge Edge) error {
+	w := wt.watcher
+	w.Lock()
+	defer w.Unlock()
+
+	irq := wt.irq
+	if w.interrupts[irq.pin.pin] != irq {
+		return pinError("set edge", irq.pin.pin, ErrNotWatched)
+	}
+	union := edge
+	for _, v := range irq.watches {
+		if v != wt {
+			union = unionEdge(union, v.edge)
+		}
+	}
+	if err := w.setEdge(irq, union); err != nil {
 		return err
 	}
-	w.interruptFds[pin.pin] = pinFd
-	w.interrupts[pinFd] = &interrupt{pin: pin, handler: handler, valueFile: valueFile}
+	wt.edge = edge
+	return nil
+}
+
+// setEdge changes the edge detected for the interrupt.
+//
+// Assumes the caller holds the lock.
+func (w *Watcher) setEdge(irq *interrupt, edge Edge) error {
+	if edge == irq.edge {
+		return nil
+	}
+	if irq.valueFile != nil {
+		if err := setEdge(irq.pin, edge); err != nil {
+			return err
+		}
+	}
+	irq.edge = edge
 	return nil
 }
 
-// UnregisterPin removes any watch on the Pin.
+// UnregisterPin removes any watches on the Pin.
 func (w *Watcher) UnregisterPin(pin *Pin) {
 	w.Lock()
 	defer w.Unlock()
 
-	pinFd, ok := w.interruptFds[pin.pin]
+	irq, ok := w.interrupts[pin.pin]
 	if !ok {
 		return
 	}
-	delete(w.interruptFds, pin.pin)
-	unix.EpollCtl(w.epfd, unix.EPOLL_CTL_DEL, pinFd, nil)
-	unix.SetNonblock(pinFd, false)
-	intr, ok := w.interrupts[pinFd]
-	if ok {
-		delete(w.interrupts, pinFd)
-		intr.valueFile.Close()
+	for _, wt := range irq.watches {
+		close(wt.done)
 	}
-	unexport(pin)
+	irq.watches = nil
+	w.removeInterrupt(irq)
+}
+
+// Detach removes any watches on the Pin, like UnregisterPin, but leaves the
+// pin exported to sysfs, so it may be used by other processes.
+func (w *Watcher) Detach(pin *Pin) {
+	w.Lock()
+	defer w.Unlock()
+
+	irq, ok := w.interrupts[pin.pin]
+	if !ok {
+		return
+	}
+	for _, wt := range irq.watches {
+		close(wt.done)
+	}
+	irq.watches = nil
+	irq.exported = false
+	w.removeInterrupt(irq)
+}
+
+// Unwatch removes the watch.
+//
+// If this is the last watch on the pin then the pin is no longer watched,
+// else the edge watched is reduced to that required by the remaining watches.
+// An error is returned if the edge cannot be reduced, though the watch is
+// still removed.
+func (wt *Watch) Unwatch() error {
+	w := wt.watcher
+	w.Lock()
+	defer w.Unlock()
+
+	irq := wt.irq
+	watches := make([]*Watch, 0, len(irq.watches))
+	for _, v := range irq.watches {
+		if v != wt {
+			watches = append(watches, v)
+		}
+	}
+	if len(watches) == len(irq.watches) {
+		// already removed
+		return nil
+	}
+	close(wt.done)
+	irq.watches = watches
+	if len(watches) == 0 {
+		w.removeInterrupt(irq)
+		return nil
+	}
+	edge := EdgeNone
+	for _, v := range watches {
+		edge = unionEdge(edge, v.edge)
+	}
+	return w.setEdge(irq, edge)
+}
+
+// removeInterrupt stops watching the pin.
+//
+// Assumes the caller holds the lock.
+func (w *Watcher) removeInterrupt(irq *interrupt) {
+	delete(w.interrupts, irq.pin.pin)
+	if irq.polled {
+		w.npolled--
+		return
+	}
+	w.removeSysfs(irq)
 }
 
 // Watch the pin for changes to level.
@@ -239,80 +1207,104 @@ func (w *Watcher) UnregisterPin(pin *Pin) {
 // with the current level, and then on the specified edges.
 // The edge determines which edge to watch.
 // There can only be one watcher on the pin at a time.
+// Use AddWatch to share the pin with other watches.
 func (p *Pin) Watch(edge Edge, handler func(*Pin)) error {
-	watcher := getDefaultWatcher()
+	watcher, err := getDefaultWatcher()
+	if err != nil {
+		return err
+	}
 	return watcher.RegisterPin(p, edge, handler)
 }
 
-// Unwatch removes any watch from the pin.
-func (p *Pin) Unwatch() {
-	watcher := getDefaultWatcher()
-	watcher.UnregisterPin(p)
-}
-
-func waitWriteable(path string) error {
-	try := 0
-	for unix.Access(path, unix.W_OK) != nil {
-		try++
-		if try > 10 {
-			return ErrTimeout
-		}
-		time.Sleep(50 * time.Millisecond)
+// WatchCtx watches the pin for changes to level, until the context is done.
+//
+// The handler is called immediately, to allow the handler to initialise its
+// state with the current level, and then on the specified edges.
+// The watch is removed when the context is done, or by Unwatch.
+// There can only be one watcher on the pin at a time.
+func (p *Pin) WatchCtx(ctx context.Context, edge Edge, handler func(Event)) error {
+	if err := ctx.Err(); err != nil {
+		return err
 	}
-	return nil
-}
-
-func export(p *Pin) error {
-	file, err := os.OpenFile("/sys/class/gpio/export", os.O_WRONLY, os.ModeExclusive)
+	watcher, err := getDefaultWatcher()
 	if err != nil {
 		return err
 	}
-	defer file.Close()
-	_, err = file.WriteString(strconv.Itoa(int(p.pin)))
-	if e, ok := err.(*os.PathError); ok && e.Err == unix.EBUSY {
-		return ErrBusy
-	}
+	wt, err := watcher.register(p, edge, handler, true, nil)
 	if err != nil {
 		return err
 	}
-	// wait for pin to be exported on sysfs - can take > 100ms on older Pis
-	return waitExported(p)
+	go func() {
+		select {
+		case <-ctx.Done():
+			wt.Unwatch()
+		case <-wt.done:
+		}
+	}()
+	return nil
 }
 
-func openValue(p *Pin) (*os.File, error) {
-	path := fmt.Sprintf("/sys/class/gpio/gpio%v/value", p.pin)
-	return os.OpenFile(path, os.O_RDWR, os.ModeExclusive)
+// AddWatch adds a watch on the pin to the default Watcher.
+//
+// Unlike Watch, a pin may have several watches added by AddWatch, each with
+// its own edge and handler, and each removed by its own Unwatch.
+func (p *Pin) AddWatch(edge Edge, handler func(Event), options ...WatchOption) (*Watch, error) {
+	watcher, err := getDefaultWatcher()
+	if err != nil {
+		return nil, err
+	}
+	return watcher.AddWatch(p, edge, handler, options...)
 }
 
-func setEdge(p *Pin, edge Edge) error {
-	path := fmt.Sprintf("/sys/class/gpio/gpio%v/edge", p.pin)
-	file, err := os.OpenFile(path, os.O_RDWR, os.ModeExclusive)
+// WaitForEdge blocks until the next edge on the pin, and returns the event.
+//
+// The current level is not reported, only a subsequent edge.
+// Returns ErrTimeout if no edge is seen within the timeout.  A timeout of
+// zero or less waits indefinitely.
+//
+// The pin is watched by a temporary Watcher for the duration of the wait.
+func (p *Pin) WaitForEdge(edge Edge, timeout time.Duration) (Event, error) {
+	events, closer, err := p.watchEvents(edge, false)
 	if err != nil {
-		return err
+		return Event{}, err
+	}
+	defer closer()
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	select {
+	case evt := <-events:
+		return evt, nil
+	case <-deadline:
+		return Event{}, pinError("wait for edge", p.pin, ErrTimeout)
 	}
-	defer file.Close()
-	_, err = file.Write([]byte(edge))
-	return err
 }
 
-func unexport(p *Pin) error {
-	file, err := os.OpenFile("/sys/class/gpio/unexport", os.O_WRONLY, os.ModeExclusive)
-	if err != nil {
-		return err
+// Rewatch changes the edge of the watches on the pin in the default Watcher.
+//
+// The handlers are unchanged.
+// Returns ErrNotWatched if the pin is not watched.
+func (p *Pin) Rewatch(edge Edge) error {
+	memlock.Lock()
+	watcher := defaultWatcher
+	memlock.Unlock()
+	if watcher == nil {
+		return pinError("set edge", p.pin, ErrNotWatched)
 	}
-	defer file.Close()
-	_, err = file.WriteString(strconv.Itoa(int(p.pin)))
-	return err
+	return watcher.SetEdge(p, edge)
 }
 
-// Wait for the sysfs GPIO files to become writable.
-func waitExported(p *Pin) error {
-	path := fmt.Sprintf("/sys/class/gpio/gpio%v/value", p.pin)
-	if err := waitWriteable(path); err != nil {
-		return err
+// Unwatch removes any watches from the pin.
+func (p *Pin) Unwatch() {
+	memlock.Lock()
+	watcher := defaultWatcher
+	memlock.Unlock()
+	if watcher != nil {
+		watcher.UnregisterPin(p)
 	}
-	path = fmt.Sprintf("/sys/class/gpio/gpio%v/edge", p.pin)
-	return waitWriteable(path)
 }
 
 var (
@@ -322,4 +1314,28 @@ var (
 
 	// ErrBusy indicates the operation is already active on the pin.
 	ErrBusy = errors.New("pin already in use")
+
+	// ErrClosed indicates the Watcher has been closed.
+	ErrClosed = errors.New("watcher closed")
+
+	// ErrNotWatched indicates the pin, or watch, is not being watched.
+	ErrNotWatched = errors.New("not watched")
+
+	// ErrWatchesActive indicates the GPIO cannot be closed as the default
+	// Watcher is watching pins.
+	ErrWatchesActive = errors.New("watches active")
+
+	// ErrNotExternal indicates the Watcher processes its own events, so
+	// cannot be driven by an external event loop.
+	ErrNotExternal = errors.New("watcher not using an external loop")
+
+	// ErrNotInput indicates the pin is an output so cannot be watched.
+	ErrNotInput = errors.New("pin is an output")
+
+	// ErrInvalidPeriod indicates a poll period that is not positive.
+	ErrInvalidPeriod = errors.New("invalid period")
+
+	// ErrInvalidPriority indicates a real-time priority outside the range 1 to
+	// 99.
+	ErrInvalidPriority = errors.New("invalid priority")
 )
@@ -3,6 +3,9 @@
 // Use of this source code is governed by an MIT-style
 // license that can be found in the LICENSE file.
 
+//go:build linux && hardware
+// +build linux,hardware
+
 //
 // Test suite for interrupt module.
 //
@@ -11,6 +14,7 @@
 package gpio
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -31,7 +35,8 @@ func setupIntr(t *testing.T) (pinIn *Pin, pinOut *Pin, watcher *Watcher) {
 	assert.Nil(t, Open())
 	pinIn = NewPin(J8p15)
 	pinOut = NewPin(J8p16)
-	watcher = getDefaultWatcher()
+	watcher, err := getDefaultWatcher()
+	assert.Nil(t, err)
 	pinIn.SetMode(Input)
 	pinOut.Write(Low)
 	pinOut.SetMode(Output)
@@ -251,6 +256,126 @@ func TestCloseInterrupts(t *testing.T) {
 	assert.NotNil(t, err, "Interrupts still active after close")
 }
 
+func TestMechanism(t *testing.T) {
+	pinIn, pinOut, watcher := setupIntr(t)
+	defer teardownIntr(pinIn, pinOut, watcher)
+	// Assumes sysfs is available.
+	assert.Equal(t, WatchSysfs, watcher.Mechanism())
+	wt, err := watcher.AddWatch(pinIn, EdgeBoth, func(Event) {})
+	assert.Nil(t, err)
+	assert.Equal(t, WatchSysfs, wt.Mechanism())
+	wt.Unwatch()
+}
+
+func TestWatchOutput(t *testing.T) {
+	pinIn, pinOut, watcher := setupIntr(t)
+	defer teardownIntr(pinIn, pinOut, watcher)
+	_, err := watcher.AddWatch(pinOut, EdgeBoth, func(Event) {})
+	assert.ErrorIs(t, err, ErrNotInput)
+	assert.ErrorIs(t, watcher.RegisterPin(pinOut, EdgeBoth, func(*Pin) {}), ErrNotInput)
+	assert.Equal(t, Output, pinOut.Mode())
+	aw, err := NewWatcher(WithAutoInput())
+	assert.Nil(t, err)
+	defer aw.Close()
+	wt, err := aw.AddWatch(pinOut, EdgeBoth, func(Event) {})
+	assert.Nil(t, err)
+	assert.Equal(t, Input, pinOut.Mode())
+	wt.Unwatch()
+}
+
+func TestPollPeriod(t *testing.T) {
+	pinIn, pinOut, watcher := setupIntr(t)
+	defer teardownIntr(pinIn, pinOut, watcher)
+	pw, err := newWatcher(WatchPoll, WithPollPeriod(20*time.Millisecond))
+	assert.Nil(t, err)
+	defer pw.Close()
+	assert.Equal(t, 20*time.Millisecond, pw.pollPeriod)
+	ich := make(chan int)
+	wt, err := pw.AddWatch(pinIn, EdgeRising, func(evt Event) {
+		if evt.Level == High {
+			ich <- 1
+		}
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, WatchPoll, wt.Mechanism())
+	pinOut.High()
+	_, err = waitInterrupt(ich, 50*time.Millisecond)
+	assert.Nil(t, err)
+}
+
+func TestPollWatcher(t *testing.T) {
+	pinIn, pinOut, watcher := setupIntr(t)
+	defer teardownIntr(pinIn, pinOut, watcher)
+	pw, err := newWatcher(WatchPoll)
+	assert.Nil(t, err)
+	defer pw.Close()
+	assert.Equal(t, WatchPoll, pw.Mechanism())
+	ich := make(chan int)
+	assert.Nil(t, pw.RegisterPin(pinIn, EdgeRising, func(pin *Pin) {
+		if pin.Read() == High {
+			ich <- 1
+		} else {
+			ich <- 0
+		}
+	}))
+	assert.ErrorIs(t, pw.RegisterPin(pinIn, EdgeRising, func(pin *Pin) {}), ErrBusy)
+	v, err := waitInterrupt(ich, 10*time.Millisecond)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, v)
+	for i := 0; i < 10; i++ {
+		pinOut.High()
+		v, err := waitInterrupt(ich, 10*time.Millisecond)
+		if err != nil {
+			t.Error("Missed high at", i)
+		} else if v == 0 {
+			t.Error("Triggered while low at", i)
+		}
+		pinOut.Low()
+		_, err = waitInterrupt(ich, 10*time.Millisecond)
+		if err == nil {
+			t.Error("Spurious or delayed trigger at", i)
+		}
+	}
+	pw.UnregisterPin(pinIn)
+	pinOut.High()
+	_, err = waitInterrupt(ich, 10*time.Millisecond)
+	assert.NotNil(t, err, "Interrupt after unregister")
+}
+
+func TestNewPollWatcher(t *testing.T) {
+	pinIn, pinOut, watcher := setupIntr(t)
+	defer teardownIntr(pinIn, pinOut, watcher)
+	_, err := NewPollWatcher(0)
+	assert.Equal(t, ErrInvalidPeriod, err)
+	pw, err := NewPollWatcher(100 * time.Microsecond)
+	assert.Nil(t, err)
+	defer pw.Close()
+	assert.Equal(t, WatchPoll, pw.Mechanism())
+	ich := make(chan int, 1)
+	wt, err := pw.AddWatch(pinIn, EdgeBoth, func(evt Event) {
+		if evt.Level == High {
+			ich <- 1
+		} else {
+			ich <- 0
+		}
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, WatchPoll, wt.Mechanism())
+	v, err := waitInterrupt(ich, 10*time.Millisecond)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, v)
+	for i := 0; i < 10; i++ {
+		pinOut.High()
+		v, err = waitInterrupt(ich, 5*time.Millisecond)
+		assert.Nil(t, err)
+		assert.Equal(t, 1, v)
+		pinOut.Low()
+		v, err = waitInterrupt(ich, 5*time.Millisecond)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, v)
+	}
+}
+
 func TestWatchExists(t *testing.T) {
 	assert.Nil(t, Open())
 	defer Close()
@@ -303,6 +428,267 @@ func TestWatchLooped(t *testing.T) {
 	assert.False(t, called)
 }
 
+// Looped tests require a jumper across Raspberry Pi J8 pins 15 and 16.
+func TestSerialDispatchLooped(t *testing.T) {
+	pinIn, pinOut, watcher := setupIntr(t)
+	defer teardownIntr(pinIn, pinOut, watcher)
+	sw, err := NewWatcher(WithSerialDispatch())
+	assert.Nil(t, err)
+	defer sw.Close()
+	levels := make(chan Level, 32)
+	active := 0
+	overlapped := false
+	_, err = sw.AddWatch(pinIn, EdgeBoth, func(evt Event) {
+		active++
+		if active > 1 {
+			overlapped = true
+		}
+		// slow handler to force events to queue.
+		time.Sleep(5 * time.Millisecond)
+		levels <- evt.Level
+		active--
+	})
+	assert.Nil(t, err)
+	// absorb the sync event
+	<-levels
+	time.Sleep(time.Millisecond)
+	for i := 0; i < 5; i++ {
+		pinOut.High()
+		time.Sleep(time.Millisecond)
+		pinOut.Low()
+		time.Sleep(time.Millisecond)
+	}
+	expected := High
+	for i := 0; i < 10; i++ {
+		select {
+		case l := <-levels:
+			assert.Equal(t, expected, l, "out of order at %d", i)
+			expected = !expected
+		case <-time.After(50 * time.Millisecond):
+			t.Fatal("missed event", i)
+		}
+	}
+	assert.False(t, overlapped)
+}
+
+// Looped tests require a jumper across Raspberry Pi J8 pins 15 and 16.
+func TestEventQueueOverflowLooped(t *testing.T) {
+	pinIn, pinOut, watcher := setupIntr(t)
+	defer teardownIntr(pinIn, pinOut, watcher)
+	policies := []OverflowPolicy{OverflowDropNewest, OverflowDropOldest}
+	for _, policy := range policies {
+		qw, err := NewWatcher(WithEventQueue(2, policy))
+		assert.Nil(t, err)
+		release := make(chan struct{})
+		levels := make(chan Level, 32)
+		_, err = qw.AddWatch(pinIn, EdgeBoth, func(evt Event) {
+			<-release
+			levels <- evt.Level
+		})
+		assert.Nil(t, err)
+		time.Sleep(time.Millisecond)
+		// sync event is blocked in the handler, so these fill the queue...
+		for i := 0; i < 5; i++ {
+			pinOut.High()
+			time.Sleep(time.Millisecond)
+			pinOut.Low()
+			time.Sleep(time.Millisecond)
+		}
+		stats := qw.Stats()
+		assert.Equal(t, uint64(8), stats[pinIn.Pin()].Dropped, "policy %d", policy)
+		close(release)
+		// sync event
+		assert.Equal(t, Low, <-levels)
+		// either the first or last pair of edges, depending on policy.
+		assert.Equal(t, High, <-levels)
+		assert.Equal(t, Low, <-levels)
+		select {
+		case <-levels:
+			t.Error("unexpected event")
+		case <-time.After(10 * time.Millisecond):
+		}
+		qw.Close()
+	}
+}
+
+// Looped tests require a jumper across Raspberry Pi J8 pins 15 and 16.
+func TestStatsLooped(t *testing.T) {
+	pinIn, pinOut, watcher := setupIntr(t)
+	defer teardownIntr(pinIn, pinOut, watcher)
+	panics := make(chan interface{}, 4)
+	sw, err := NewWatcher(WithPanicHandler(func(evt Event, r interface{}) {
+		panics <- r
+	}))
+	assert.Nil(t, err)
+	defer sw.Close()
+	assert.Empty(t, sw.Pins())
+	assert.Empty(t, sw.Stats())
+	called := make(chan Level, 4)
+	_, err = sw.AddWatch(pinIn, EdgeBoth, func(evt Event) {
+		called <- evt.Level
+		if evt.Level == High {
+			panic("oops")
+		}
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []int{pinIn.Pin()}, sw.Pins())
+	<-called
+	pinOut.High()
+	assert.Equal(t, High, <-called)
+	select {
+	case r := <-panics:
+		assert.Equal(t, "oops", r)
+	case <-time.After(10 * time.Millisecond):
+		t.Error("panic not recovered")
+	}
+	stats := sw.Stats()[pinIn.Pin()]
+	assert.Equal(t, uint64(2), stats.Events)
+	assert.Equal(t, uint64(0), stats.Dropped)
+	assert.Equal(t, uint64(1), stats.HandlerPanics)
+	sw.UnregisterPin(pinIn)
+	assert.Empty(t, sw.Pins())
+}
+
+// Looped tests require a jumper across Raspberry Pi J8 pins 15 and 16.
+func TestAddWatchLooped(t *testing.T) {
+	pinIn, pinOut, watcher := setupIntr(t)
+	defer teardownIntr(pinIn, pinOut, watcher)
+	rising := make(chan Level, 4)
+	falling := make(chan Level, 4)
+	wr, err := watcher.AddWatch(pinIn, EdgeRising, func(evt Event) {
+		rising <- evt.Level
+	})
+	assert.Nil(t, err)
+	wf, err := watcher.AddWatch(pinIn, EdgeFalling, func(evt Event) {
+		falling <- evt.Level
+	})
+	assert.Nil(t, err)
+	// exclusive registration fails while watches exist.
+	assert.ErrorIs(t, watcher.RegisterPin(pinIn, EdgeBoth, func(*Pin) {}), ErrBusy)
+	// sync events
+	assert.Equal(t, Low, <-rising)
+	assert.Equal(t, Low, <-falling)
+	time.Sleep(time.Millisecond)
+	pinOut.High()
+	assert.Equal(t, High, <-rising)
+	pinOut.Low()
+	assert.Equal(t, Low, <-falling)
+	select {
+	case <-rising:
+		t.Error("rising watch triggered on falling edge")
+	case <-time.After(10 * time.Millisecond):
+	}
+	wr.Unwatch()
+	// and again just for coverage.
+	wr.Unwatch()
+	assert.Equal(t, []int{pinIn.Pin()}, watcher.Pins())
+	pinOut.High()
+	pinOut.Low()
+	assert.Equal(t, Low, <-falling)
+	select {
+	case <-rising:
+		t.Error("removed watch triggered")
+	case <-time.After(10 * time.Millisecond):
+	}
+	wf.Unwatch()
+	assert.Empty(t, watcher.Pins())
+}
+
+// Looped tests require a jumper across Raspberry Pi J8 pins 15 and 16.
+func TestSetEdgeLooped(t *testing.T) {
+	pinIn, pinOut, watcher := setupIntr(t)
+	defer teardownIntr(pinIn, pinOut, watcher)
+	assert.ErrorIs(t, watcher.SetEdge(pinIn, EdgeBoth), ErrNotWatched)
+	levels := make(chan Level, 4)
+	assert.Nil(t, watcher.RegisterPin(pinIn, EdgeRising, func(pin *Pin) {
+		levels <- pin.Read()
+	}))
+	// sync event
+	assert.Equal(t, Low, <-levels)
+	time.Sleep(time.Millisecond)
+	assert.Nil(t, watcher.SetEdge(pinIn, EdgeFalling))
+	pinOut.High()
+	select {
+	case <-levels:
+		t.Error("triggered on rising edge")
+	case <-time.After(10 * time.Millisecond):
+	}
+	pinOut.Low()
+	assert.Equal(t, Low, <-levels)
+	watcher.UnregisterPin(pinIn)
+	wt, err := watcher.AddWatch(pinIn, EdgeNone, func(Event) {})
+	assert.Nil(t, err)
+	wt.Unwatch()
+	assert.ErrorIs(t, wt.SetEdge(EdgeBoth), ErrNotWatched)
+}
+
+// Looped tests require a jumper across Raspberry Pi J8 pins 15 and 16.
+func TestWatchCtxLooped(t *testing.T) {
+	pinIn, pinOut, watcher := setupIntr(t)
+	defer teardownIntr(pinIn, pinOut, watcher)
+	ctx, cancel := context.WithCancel(context.Background())
+	ech := make(chan Event, 2)
+	assert.Nil(t, pinIn.WatchCtx(ctx, EdgeBoth, func(evt Event) {
+		ech <- evt
+	}))
+	assert.ErrorIs(t, pinIn.WatchCtx(ctx, EdgeBoth, func(evt Event) {}), ErrBusy)
+	select {
+	case evt := <-ech:
+		assert.Equal(t, pinIn, evt.Pin)
+		assert.Equal(t, Low, evt.Level)
+	case <-time.After(10 * time.Millisecond):
+		t.Error("Missing sync event")
+	}
+	start := time.Now()
+	pinOut.High()
+	select {
+	case evt := <-ech:
+		assert.Equal(t, High, evt.Level)
+		assert.False(t, evt.Time.Before(start))
+	case <-time.After(10 * time.Millisecond):
+		t.Error("Missed high")
+	}
+	cancel()
+	time.Sleep(time.Millisecond)
+	pinOut.Low()
+	select {
+	case <-ech:
+		t.Error("Event after cancel")
+	case <-time.After(10 * time.Millisecond):
+	}
+	// pin available to watch again
+	assert.Nil(t, watcher.RegisterPin(pinIn, EdgeBoth, func(*Pin) {}))
+	assert.Equal(t, context.Canceled, pinIn.WatchCtx(ctx, EdgeBoth, func(evt Event) {}))
+}
+
+func TestNewWatcherCtx(t *testing.T) {
+	pinIn, pinOut, watcher := setupIntr(t)
+	defer teardownIntr(pinIn, pinOut, watcher)
+	ctx, cancel := context.WithCancel(context.Background())
+	w, err := NewWatcherCtx(ctx)
+	assert.Nil(t, err)
+	assert.Nil(t, w.RegisterPin(pinIn, EdgeBoth, func(*Pin) {}))
+	cancel()
+	select {
+	case <-w.doneCh:
+	case <-time.After(10 * time.Millisecond):
+		t.Error("Watcher not closed")
+	}
+	assert.Equal(t, ErrClosed, w.RegisterPin(pinIn, EdgeBoth, func(*Pin) {}))
+	_, err = NewWatcherCtx(ctx)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestWatcherThreadOptions(t *testing.T) {
+	_, err := NewWatcher(WithPriority(100))
+	assert.Equal(t, ErrInvalidPriority, err)
+	_, err = NewWatcher(WithRealtime(0))
+	assert.Equal(t, ErrInvalidPriority, err)
+	w, err := NewWatcher(WithLockedThread())
+	assert.Nil(t, err)
+	w.Close()
+}
+
 // This provides a coarse estimate of the interrupt latency,
 // i.e. the time between an interrupt being triggered and handled.
 // There is some overhead in there due to the handshaking via a channel etc...
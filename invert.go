@@ -0,0 +1,152 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Active low wrapper for DIO Pins.
+
+package gpio
+
+import "context"
+
+// InvertedPin is a Pin with active low logic, so its levels and edges are the
+// inverse of the physical levels and edges of the pin.
+//
+// The Pin itself always reflects the physical levels, so active low wiring is
+// handled by wrapping the Pin rather than by configuring it.
+type InvertedPin struct {
+	pin *Pin
+}
+
+// NewInvertedPin creates an InvertedPin on the pin.
+//
+// Returns nil if the pin is not a valid GPIO pin.
+func NewInvertedPin(pin int) *InvertedPin {
+	p := NewPin(pin)
+	if p == nil {
+		return nil
+	}
+	return p.Inverted()
+}
+
+// Inverted returns an InvertedPin wrapping the pin.
+func (p *Pin) Inverted() *InvertedPin {
+	return &InvertedPin{pin: p}
+}
+
+// Raw returns the underlying Pin, with its physical levels.
+func (p *InvertedPin) Raw() *Pin {
+	return p.pin
+}
+
+// Pin returns the pin number that this InvertedPin represents.
+func (p *InvertedPin) Pin() int {
+	return p.pin.pin
+}
+
+// Input sets the pin to be an input.
+func (p *InvertedPin) Input() {
+	p.pin.Input()
+}
+
+// Output sets the pin to be an output.
+func (p *InvertedPin) Output() {
+	p.pin.Output()
+}
+
+// Mode returns the mode of the pin.
+func (p *InvertedPin) Mode() Mode {
+	return p.pin.Mode()
+}
+
+// SetMode sets the mode of the pin.
+func (p *InvertedPin) SetMode(mode Mode) {
+	p.pin.SetMode(mode)
+}
+
+// Read returns the logical level of the pin, which is High when the pin is
+// physically low.
+func (p *InvertedPin) Read() Level {
+	return !p.pin.Read()
+}
+
+// Write sets the logical level of the pin, so High drives the pin physically
+// low.
+func (p *InvertedPin) Write(level Level) {
+	p.pin.Write(!level)
+}
+
+// High sets the pin logically high, and so physically low.
+func (p *InvertedPin) High() {
+	p.pin.Low()
+}
+
+// Low sets the pin logically low, and so physically high.
+func (p *InvertedPin) Low() {
+	p.pin.High()
+}
+
+// Toggle inverts the level of the pin.
+func (p *InvertedPin) Toggle() {
+	p.pin.Toggle()
+}
+
+// Shadow returns the logical level last written to the pin or read from the
+// pin.
+func (p *InvertedPin) Shadow() Level {
+	return !p.pin.Shadow()
+}
+
+// Watch the pin for changes to its logical level.
+//
+// The edge is the logical edge, so EdgeRising is physically a falling edge.
+// Otherwise this behaves as per Pin.Watch.
+func (p *InvertedPin) Watch(edge Edge, handler func(*InvertedPin)) error {
+	return p.pin.Watch(edge.inverted(), func(*Pin) { handler(p) })
+}
+
+// WatchCtx watches the pin for changes to its logical level, until the context
+// is done.
+//
+// The edge, and the level in the events, are logical.
+// Otherwise this behaves as per Pin.WatchCtx.
+func (p *InvertedPin) WatchCtx(ctx context.Context, edge Edge, handler func(Event)) error {
+	return p.pin.WatchCtx(ctx, edge.inverted(), invertEvent(handler))
+}
+
+// AddWatch adds a watch on the pin to the default Watcher.
+//
+// The edge, and the level in the events, are logical.
+// Otherwise this behaves as per Pin.AddWatch.
+func (p *InvertedPin) AddWatch(edge Edge, handler func(Event), options ...WatchOption) (*Watch, error) {
+	return p.pin.AddWatch(edge.inverted(), invertEvent(handler), options...)
+}
+
+// Rewatch changes the logical edge watched on the pin.
+func (p *InvertedPin) Rewatch(edge Edge) error {
+	return p.pin.Rewatch(edge.inverted())
+}
+
+// Unwatch removes any watch from the pin.
+func (p *InvertedPin) Unwatch() {
+	p.pin.Unwatch()
+}
+
+// inverted returns the edge with rising and falling swapped.
+func (edge Edge) inverted() Edge {
+	switch edge {
+	case EdgeRising:
+		return EdgeFalling
+	case EdgeFalling:
+		return EdgeRising
+	}
+	return edge
+}
+
+// invertEvent wraps the handler to invert the level of the events passed to it.
+func invertEvent(handler func(Event)) func(Event) {
+	return func(evt Event) {
+		evt.Level = !evt.Level
+		handler(evt)
+	}
+}
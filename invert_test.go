@@ -0,0 +1,69 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && hardware
+// +build linux,hardware
+
+// Test suite for invert module.
+//
+// Tests use Raspberry Pi J8 pins 15 and 16 which must be jumpered together.
+package gpio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEdgeInverted(t *testing.T) {
+	patterns := []struct {
+		edge     Edge
+		expected Edge
+	}{
+		{EdgeNone, EdgeNone},
+		{EdgeRising, EdgeFalling},
+		{EdgeFalling, EdgeRising},
+		{EdgeBoth, EdgeBoth},
+	}
+	for _, p := range patterns {
+		assert.Equal(t, p.expected, p.edge.inverted(), p.edge)
+	}
+}
+
+// Looped tests require a jumper across Raspberry Pi J8 pins 15 and 16.
+func TestInvertedPinLooped(t *testing.T) {
+	pinIn, pinOut, watcher := setupIntr(t)
+	defer teardownIntr(pinIn, pinOut, watcher)
+	in := pinIn.Inverted()
+	out := pinOut.Inverted()
+	assert.Equal(t, pinIn, in.Raw())
+	assert.Equal(t, J8p15, in.Pin())
+	out.High()
+	assert.Equal(t, Low, pinOut.Shadow())
+	assert.Equal(t, High, out.Shadow())
+	assert.Equal(t, High, in.Read())
+	out.Write(Low)
+	assert.Equal(t, Low, in.Read())
+	levels := make(chan Level, 4)
+	wt, err := in.AddWatch(EdgeRising, func(evt Event) {
+		levels <- evt.Level
+	})
+	assert.Nil(t, err)
+	defer wt.Unwatch()
+	select {
+	case l := <-levels:
+		assert.Equal(t, Low, l, "sync")
+	case <-time.After(10 * time.Millisecond):
+		t.Error("missing sync")
+	}
+	out.High()
+	select {
+	case l := <-levels:
+		assert.Equal(t, High, l)
+	case <-time.After(10 * time.Millisecond):
+		t.Error("missing rising edge")
+	}
+}
@@ -0,0 +1,317 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package ir provides a receiver and transmitter for infrared remote control
+// frames, using the NEC and RC5 protocols.
+//
+// The receiver decodes frames from the edge timings of a demodulating IR
+// receiver module, such as a TSOP38238, connected to an input pin.
+//
+// The transmitter drives an IR LED connected to an output pin, modulating the
+// frame with a software generated carrier.
+package ir
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/warthog618/gpio"
+)
+
+// Protocol identifies the encoding of a frame.
+type Protocol int
+
+const (
+	// NEC is the NEC protocol, including extended 16-bit addresses.
+	NEC Protocol = iota
+	// RC5 is the Philips RC5 protocol, including the extended 7-bit command.
+	RC5
+)
+
+// Frame is a single IR remote control frame.
+type Frame struct {
+	Protocol Protocol
+	Address  uint16
+	Command  uint8
+
+	// Repeat indicates the frame is a repeat of the previous frame, as sent
+	// while a button is held.
+	//
+	// For NEC this is the repeat code.  For RC5 the toggle bit is unchanged.
+	Repeat bool
+
+	// Toggle is the state of the RC5 toggle bit.
+	Toggle bool
+}
+
+// ErrProtocol indicates the protocol is not supported.
+var ErrProtocol = errors.New("unsupported protocol")
+
+// gapTimeout is the time without an edge that indicates the end of a frame.
+const gapTimeout = 8 * time.Millisecond
+
+// config contains the options common to Receiver and Transmitter.
+type config struct {
+	active  gpio.Level
+	carrier float64
+}
+
+// Option modifies the configuration of a Receiver or Transmitter.
+type Option func(*config)
+
+// WithActiveLevel sets the level of the pin while the carrier is present.
+//
+// The default for a Receiver is Low, as demodulating receivers are active
+// low.  The default for a Transmitter is High.
+func WithActiveLevel(l gpio.Level) Option {
+	return func(c *config) {
+		c.active = l
+	}
+}
+
+// WithCarrier sets the frequency of the carrier generated by a Transmitter,
+// in Hz.
+//
+// The default is 38kHz.  A frequency of 0 disables the carrier, for LED
+// drivers that provide their own modulation.
+func WithCarrier(freq float64) Option {
+	return func(c *config) {
+		c.carrier = freq
+	}
+}
+
+// Receiver decodes IR frames from an input pin.
+type Receiver struct {
+	config
+	pin     *gpio.Pin
+	handler func(Frame)
+	watcher *gpio.Watcher
+
+	// Guards the following.
+	mu sync.Mutex
+	// alternating mark and space durations, starting with a mark.
+	pulses []time.Duration
+	// the time of the last edge.
+	last time.Time
+	// the time of the first edge of the frame.
+	start time.Time
+	// true while the carrier is present.
+	mark  bool
+	timer *time.Timer
+	// the last NEC frame, for repeat codes.
+	nec *Frame
+	// the start of the last NEC frame or repeat code.
+	necStart time.Time
+	// the last RC5 frame, to detect repeats.
+	rc5 *Frame
+	// true once the Receiver is closed.
+	closed bool
+}
+
+// NewReceiver creates a Receiver that decodes frames from the pin and passes
+// them to the handler.
+//
+// The pin is set to an input, and is requested until the Receiver is closed.
+func NewReceiver(pin int, handler func(Frame), options ...Option) (*Receiver, error) {
+	r := &Receiver{
+		config:  config{active: gpio.Low},
+		handler: handler,
+	}
+	for _, option := range options {
+		option(&r.config)
+	}
+	r.pin = gpio.NewPin(pin)
+	if err := r.pin.Request("ir"); err != nil {
+		return nil, err
+	}
+	w, err := gpio.NewWatcher(gpio.WithSerialDispatch())
+	if err != nil {
+		r.pin.Release()
+		return nil, err
+	}
+	r.watcher = w
+	r.pin.Input()
+	r.timer = time.AfterFunc(time.Hour, r.gap)
+	r.timer.Stop()
+	if _, err = w.AddWatch(r.pin, gpio.EdgeBoth, r.edge); err != nil {
+		w.Close()
+		r.pin.Release()
+		return nil, err
+	}
+	return r, nil
+}
+
+// Close stops the Receiver.
+//
+// The handler is not called once Close returns.
+func (r *Receiver) Close() {
+	r.mu.Lock()
+	r.closed = true
+	r.timer.Stop()
+	r.mu.Unlock()
+	r.watcher.Close()
+	r.pin.Release()
+}
+
+func (r *Receiver) edge(evt gpio.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	mark := evt.Level == r.active
+	if r.closed || mark == r.mark {
+		// sync event, or a missed edge.
+		return
+	}
+	r.mark = mark
+	if mark {
+		if len(r.pulses) > 0 {
+			r.pulses = append(r.pulses, evt.Time.Sub(r.last))
+		} else {
+			r.start = evt.Time
+		}
+	} else {
+		r.pulses = append(r.pulses, evt.Time.Sub(r.last))
+	}
+	r.last = evt.Time
+	r.timer.Reset(gapTimeout)
+}
+
+// gap is called when the pin has been idle long enough to end the frame.
+func (r *Receiver) gap() {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return
+	}
+	if r.mark {
+		// stuck on, so discard.
+		r.pulses = r.pulses[:0]
+		r.mu.Unlock()
+		return
+	}
+	pulses := r.pulses
+	r.pulses = nil
+	f, ok := r.decode(pulses, r.start)
+	r.mu.Unlock()
+	if ok {
+		r.handler(f)
+	}
+}
+
+// decode decodes the pulses, which began at start, using each of the
+// supported protocols.
+//
+// NEC repeat codes are only reported if they follow the previous frame or
+// repeat code within the repeat period, else a repeat code following a lost
+// frame would be reported as a repeat of an earlier frame.
+//
+// Assumes the caller holds the lock.
+func (r *Receiver) decode(pulses []time.Duration, start time.Time) (Frame, bool) {
+	if f, ok := decodeNEC(pulses); ok {
+		if f.Repeat {
+			if r.nec == nil || start.Sub(r.necStart) > necRepeatWindow {
+				r.nec = nil
+				return f, false
+			}
+			r.necStart = start
+			f.Address = r.nec.Address
+			f.Command = r.nec.Command
+			return f, true
+		}
+		r.nec = &f
+		r.necStart = start
+		return f, true
+	}
+	f, ok := decodeRC5(pulses)
+	if !ok {
+		return f, false
+	}
+	if last := r.rc5; last != nil {
+		f.Repeat = f.Toggle == last.Toggle && f.Address == last.Address && f.Command == last.Command
+	}
+	r.rc5 = &f
+	return f, true
+}
+
+// Transmitter sends IR frames on an output pin.
+type Transmitter struct {
+	config
+	pin *gpio.Pin
+
+	// Guards the following.
+	mu sync.Mutex
+	// the RC5 toggle bit.
+	toggle bool
+}
+
+// NewTransmitter creates a Transmitter that sends frames on the pin.
+//
+// The pin is set to an output at its inactive level.
+func NewTransmitter(pin int, options ...Option) *Transmitter {
+	t := &Transmitter{
+		config: config{active: gpio.High, carrier: 38000},
+		pin:    gpio.NewPin(pin),
+	}
+	for _, option := range options {
+		option(&t.config)
+	}
+	t.pin.Write(!t.active)
+	t.pin.Output()
+	return t
+}
+
+// Send sends the frame, and returns once the frame has been sent.
+//
+// For RC5 the toggle bit is managed by the Transmitter, and is flipped for
+// each frame that is not a Repeat.
+func (t *Transmitter) Send(f Frame) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var pulses []time.Duration
+	switch f.Protocol {
+	case NEC:
+		pulses = encodeNEC(f)
+	case RC5:
+		if !f.Repeat {
+			t.toggle = !t.toggle
+		}
+		f.Toggle = t.toggle
+		pulses = encodeRC5(f)
+	default:
+		return ErrProtocol
+	}
+	t.pin.PlayWave(t.steps(pulses), 1).Wait()
+	return nil
+}
+
+// steps converts the pulses to waveform steps, modulating the marks with the
+// carrier.
+func (t *Transmitter) steps(pulses []time.Duration) []gpio.Step {
+	var steps []gpio.Step
+	for i, d := range pulses {
+		if i%2 == 1 {
+			steps = append(steps, gpio.Step{Level: !t.active, Duration: d})
+			continue
+		}
+		if t.carrier <= 0 {
+			steps = append(steps, gpio.Step{Level: t.active, Duration: d})
+			continue
+		}
+		half := time.Duration(float64(time.Second) / t.carrier / 2)
+		for n := int(d / (2 * half)); n > 0; n-- {
+			steps = append(steps,
+				gpio.Step{Level: t.active, Duration: half},
+				gpio.Step{Level: !t.active, Duration: half})
+		}
+	}
+	// finish inactive.
+	return append(steps, gpio.Step{Level: !t.active})
+}
+
+// near returns true if the duration is within 30% of the expected duration.
+func near(d, expected time.Duration) bool {
+	tolerance := expected * 3 / 10
+	return d > expected-tolerance && d < expected+tolerance
+}
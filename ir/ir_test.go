@@ -0,0 +1,157 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Test suite for ir module.
+//
+// Tests only encode and decode pulse timings, so can be run on any machine.
+package ir
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNEC(t *testing.T) {
+	patterns := []struct {
+		name string
+		f    Frame
+	}{
+		{"zero", Frame{Protocol: NEC}},
+		{"address", Frame{Protocol: NEC, Address: 0x12, Command: 0x34}},
+		{"max", Frame{Protocol: NEC, Address: 0xff, Command: 0xff}},
+		{"extended", Frame{Protocol: NEC, Address: 0x1234, Command: 0x56}},
+		{"repeat", Frame{Protocol: NEC, Repeat: true}},
+	}
+	for _, p := range patterns {
+		t.Run(p.name, func(t *testing.T) {
+			pulses := encodeNEC(p.f)
+			f, ok := decodeNEC(pulses)
+			assert.True(t, ok)
+			assert.Equal(t, p.f, f)
+		})
+	}
+}
+
+func TestDecodeNECInvalid(t *testing.T) {
+	valid := encodeNEC(Frame{Protocol: NEC, Address: 0x12, Command: 0x34})
+	badCommand := append([]time.Duration(nil), valid...)
+	// flip the first bit of the command.
+	badCommand[3+2*16] = necOneSpace
+	patterns := []struct {
+		name   string
+		pulses []time.Duration
+	}{
+		{"empty", nil},
+		{"short", valid[:necRepeatPulses-1]},
+		{"leader", append([]time.Duration{necBitMark}, valid[1:]...)},
+		{"truncated", valid[:len(valid)-2]},
+		{"repeat space", []time.Duration{necLeaderMark, necLeaderSpace, necBitMark}},
+		{"command", badCommand},
+		{"rc5", encodeRC5(Frame{Protocol: RC5, Address: 1, Command: 2})},
+	}
+	for _, p := range patterns {
+		t.Run(p.name, func(t *testing.T) {
+			_, ok := decodeNEC(p.pulses)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestRC5(t *testing.T) {
+	patterns := []struct {
+		name string
+		f    Frame
+	}{
+		{"zero", Frame{Protocol: RC5}},
+		{"address", Frame{Protocol: RC5, Address: 0x05, Command: 0x0c}},
+		{"toggle", Frame{Protocol: RC5, Address: 0x05, Command: 0x0c, Toggle: true}},
+		{"extended", Frame{Protocol: RC5, Address: 0x10, Command: 0x41}},
+		{"max", Frame{Protocol: RC5, Address: 0x1f, Command: 0x7f, Toggle: true}},
+		{"ones", Frame{Protocol: RC5, Address: 0x1f, Command: 0x3f}},
+	}
+	for _, p := range patterns {
+		t.Run(p.name, func(t *testing.T) {
+			pulses := encodeRC5(p.f)
+			f, ok := decodeRC5(pulses)
+			assert.True(t, ok)
+			assert.Equal(t, p.f, f)
+		})
+	}
+}
+
+func TestDecodeRC5Invalid(t *testing.T) {
+	valid := encodeRC5(Frame{Protocol: RC5, Address: 0x05, Command: 0x0c})
+	long := append([]time.Duration(nil), valid...)
+	long[1] = 3 * rc5HalfBit
+	patterns := []struct {
+		name   string
+		pulses []time.Duration
+	}{
+		{"empty", nil},
+		{"truncated", valid[:len(valid)-2]},
+		{"extended", append(append([]time.Duration(nil), valid...), rc5HalfBit, rc5HalfBit)},
+		{"timing", long},
+		{"nec", encodeNEC(Frame{Protocol: NEC, Address: 1, Command: 2})},
+	}
+	for _, p := range patterns {
+		t.Run(p.name, func(t *testing.T) {
+			_, ok := decodeRC5(p.pulses)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestReceiverRepeat(t *testing.T) {
+	frame := Frame{Protocol: NEC, Address: 0x12, Command: 0x34}
+	repeat := encodeNEC(Frame{Protocol: NEC, Repeat: true})
+	start := time.Now()
+	patterns := []struct {
+		name  string
+		after []time.Duration
+		ok    bool
+	}{
+		{"prompt", []time.Duration{108 * time.Millisecond}, true},
+		{"held", []time.Duration{108 * time.Millisecond, 216 * time.Millisecond}, true},
+		{"late", []time.Duration{300 * time.Millisecond}, false},
+		{"lost", []time.Duration{108 * time.Millisecond, 324 * time.Millisecond}, false},
+	}
+	for _, p := range patterns {
+		t.Run(p.name, func(t *testing.T) {
+			r := Receiver{}
+			f, ok := r.decode(encodeNEC(frame), start)
+			assert.True(t, ok)
+			assert.Equal(t, frame, f)
+			for _, after := range p.after {
+				f, ok = r.decode(repeat, start.Add(after))
+			}
+			assert.Equal(t, p.ok, ok)
+			if ok {
+				expected := frame
+				expected.Repeat = true
+				assert.Equal(t, expected, f)
+			}
+		})
+	}
+	// no frame to repeat.
+	r := Receiver{}
+	_, ok := r.decode(repeat, start)
+	assert.False(t, ok)
+}
+
+func TestReceiverClosed(t *testing.T) {
+	var frames []Frame
+	r := Receiver{handler: func(f Frame) { frames = append(frames, f) }}
+	frame := Frame{Protocol: NEC, Address: 0x12, Command: 0x34}
+	r.pulses = encodeNEC(frame)
+	r.gap()
+	assert.Equal(t, []Frame{frame}, frames)
+
+	r.closed = true
+	r.pulses = encodeNEC(frame)
+	r.gap()
+	assert.Equal(t, []Frame{frame}, frames)
+}
@@ -0,0 +1,82 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package ir
+
+import "time"
+
+// NEC timings.
+const (
+	necLeaderMark   = 9000 * time.Microsecond
+	necLeaderSpace  = 4500 * time.Microsecond
+	necRepeatSpace  = 2250 * time.Microsecond
+	necBitMark      = 562500 * time.Nanosecond
+	necZeroSpace    = 562500 * time.Nanosecond
+	necOneSpace     = 1687500 * time.Nanosecond
+	necFrameBits    = 32
+	necFramePulses  = 2 + 2*necFrameBits + 1
+	necRepeatPulses = 3
+	// the time between the starts of a frame and its repeat codes is 108ms,
+	// so this allows for some latency in detecting the edges.
+	necRepeatWindow = 120 * time.Millisecond
+)
+
+// encodeNEC returns the alternating mark and space durations for the frame.
+//
+// Addresses above 0xff are sent as extended 16-bit addresses.
+func encodeNEC(f Frame) []time.Duration {
+	if f.Repeat {
+		return []time.Duration{necLeaderMark, necRepeatSpace, necBitMark}
+	}
+	addr := uint32(f.Address)
+	if f.Address <= 0xff {
+		addr |= uint32(^uint8(f.Address)) << 8
+	}
+	data := addr | uint32(f.Command)<<16 | uint32(^f.Command)<<24
+	pulses := make([]time.Duration, 0, necFramePulses)
+	pulses = append(pulses, necLeaderMark, necLeaderSpace)
+	for i := 0; i < necFrameBits; i++ {
+		space := necZeroSpace
+		if data&(1<<uint(i)) != 0 {
+			space = necOneSpace
+		}
+		pulses = append(pulses, necBitMark, space)
+	}
+	return append(pulses, necBitMark)
+}
+
+// decodeNEC decodes the alternating mark and space durations as an NEC frame.
+//
+// Repeat codes are returned as a Repeat frame with no address or command.
+func decodeNEC(pulses []time.Duration) (Frame, bool) {
+	f := Frame{Protocol: NEC}
+	if len(pulses) < necRepeatPulses || !near(pulses[0], necLeaderMark) {
+		return f, false
+	}
+	if len(pulses) == necRepeatPulses && near(pulses[1], necRepeatSpace) {
+		f.Repeat = true
+		return f, true
+	}
+	if len(pulses) != necFramePulses || !near(pulses[1], necLeaderSpace) {
+		return f, false
+	}
+	var data uint32
+	for i := 0; i < necFrameBits; i++ {
+		space := pulses[3+2*i]
+		if space > (necZeroSpace+necOneSpace)/2 {
+			data |= 1 << uint(i)
+		}
+	}
+	cmd := uint8(data >> 16)
+	if cmd != ^uint8(data>>24) {
+		return f, false
+	}
+	f.Command = cmd
+	f.Address = uint16(data)
+	if uint8(data) == ^uint8(data>>8) {
+		f.Address = uint16(uint8(data))
+	}
+	return f, true
+}
@@ -0,0 +1,91 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package ir
+
+import "time"
+
+// RC5 timings.
+const (
+	rc5HalfBit   = 889 * time.Microsecond
+	rc5FrameBits = 14
+)
+
+// encodeRC5 returns the alternating mark and space durations for the frame.
+//
+// The frame is Manchester encoded, with a 1 being a space then a mark.
+func encodeRC5(f Frame) []time.Duration {
+	// start, field (inverted command bit 6), toggle, 5 address, 6 command.
+	data := uint32(1)<<13 | uint32(f.Address&0x1f)<<6 | uint32(f.Command&0x3f)
+	if f.Command&0x40 == 0 {
+		data |= 1 << 12
+	}
+	if f.Toggle {
+		data |= 1 << 11
+	}
+	var halves []bool
+	for i := rc5FrameBits - 1; i >= 0; i-- {
+		one := data&(1<<uint(i)) != 0
+		halves = append(halves, !one, one)
+	}
+	// the leading space of the start bit is indistinguishable from idle.
+	halves = halves[1:]
+	var pulses []time.Duration
+	for i, mark := range halves {
+		if i > 0 && mark == halves[i-1] {
+			pulses[len(pulses)-1] += rc5HalfBit
+			continue
+		}
+		pulses = append(pulses, rc5HalfBit)
+	}
+	if len(pulses)%2 == 0 {
+		// drop the trailing space.
+		pulses = pulses[:len(pulses)-1]
+	}
+	return pulses
+}
+
+// decodeRC5 decodes the alternating mark and space durations as an RC5 frame.
+func decodeRC5(pulses []time.Duration) (Frame, bool) {
+	f := Frame{Protocol: RC5}
+	// the leading space of the start bit.
+	halves := []bool{false}
+	for i, d := range pulses {
+		mark := i%2 == 0
+		switch {
+		case near(d, rc5HalfBit):
+			halves = append(halves, mark)
+		case near(d, 2*rc5HalfBit):
+			halves = append(halves, mark, mark)
+		default:
+			return f, false
+		}
+	}
+	if len(halves) == 2*rc5FrameBits-1 {
+		// the trailing space of a final 0.
+		halves = append(halves, false)
+	}
+	if len(halves) != 2*rc5FrameBits {
+		return f, false
+	}
+	var data uint32
+	for i := 0; i < rc5FrameBits; i++ {
+		first, second := halves[2*i], halves[2*i+1]
+		if first == second {
+			return f, false
+		}
+		data <<= 1
+		if second {
+			data |= 1
+		}
+	}
+	f.Command = uint8(data & 0x3f)
+	if data&(1<<12) == 0 {
+		f.Command |= 0x40
+	}
+	f.Toggle = data&(1<<11) != 0
+	f.Address = uint16(data>>6) & 0x1f
+	return f, true
+}
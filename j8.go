@@ -0,0 +1,79 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Mapping between J8 header pins and BCM GPIO numbers.
+
+package gpio
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// j8Pins maps from BCM GPIO number to J8 header pin.
+var j8Pins = [MaxGPIOPin]int{
+	J8p3: 3, J8p5: 5, J8p7: 7, J8p8: 8, J8p10: 10,
+	J8p11: 11, J8p12: 12, J8p13: 13, J8p15: 15, J8p16: 16,
+	J8p18: 18, J8p19: 19, J8p21: 21, J8p22: 22, J8p23: 23,
+	J8p24: 24, J8p26: 26, J8p27: 27, J8p28: 28, J8p29: 29,
+	J8p31: 31, J8p32: 32, J8p33: 33, J8p35: 35, J8p36: 36,
+	J8p37: 37, J8p38: 38, J8p40: 40,
+}
+
+// J8ToBCM returns the BCM GPIO number of the J8 header pin.
+//
+// Returns ErrInvalidPin if the header pin is not a GPIO, such as a power or
+// ground pin.
+func J8ToBCM(j8 int) (int, error) {
+	for bcm, p := range j8Pins {
+		if p == j8 {
+			return bcm, nil
+		}
+	}
+	return 0, fmt.Errorf("J8 pin %d: %w", j8, ErrInvalidPin)
+}
+
+// BCMToJ8 returns the J8 header pin of the BCM GPIO.
+//
+// Returns ErrInvalidPin if the GPIO is not on the J8 header.
+func BCMToJ8(bcm int) (int, error) {
+	if bcm < 0 || bcm >= MaxGPIOPin {
+		return 0, fmt.Errorf("GPIO%d: %w", bcm, ErrInvalidPin)
+	}
+	return j8Pins[bcm], nil
+}
+
+// ParsePin returns the BCM GPIO number of the named pin.
+//
+// The name may be a J8 header pin, such as "J8p15", a BCM GPIO, such as
+// "GPIO22", or a BCM GPIO number, such as "22".  The prefixes are case
+// insensitive.
+// Returns ErrInvalidPin if the name is not a valid pin.
+func ParsePin(name string) (int, error) {
+	s := strings.ToUpper(name)
+	switch {
+	case strings.HasPrefix(s, "J8P"):
+		if n, err := strconv.ParseUint(s[len("J8P"):], 10, 8); err == nil {
+			if bcm, err := J8ToBCM(int(n)); err == nil {
+				return bcm, nil
+			}
+		}
+	case strings.HasPrefix(s, "GPIO"):
+		s = s[len("GPIO"):]
+		fallthrough
+	default:
+		if n, err := strconv.ParseUint(s, 10, 8); err == nil && n < MaxGPIOPin {
+			return int(n), nil
+		}
+	}
+	return 0, fmt.Errorf("%q: %w", name, ErrInvalidPin)
+}
+
+var (
+	// ErrInvalidPin indicates the pin is not a valid GPIO pin.
+	ErrInvalidPin = errors.New("invalid pin")
+)
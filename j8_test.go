@@ -0,0 +1,65 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Test suite for J8 mapping module.
+//
+// Tests do not use any pins.
+package gpio_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warthog618/gpio"
+)
+
+func TestJ8Mapping(t *testing.T) {
+	for bcm := 0; bcm < gpio.MaxGPIOPin; bcm++ {
+		j8, err := gpio.BCMToJ8(bcm)
+		assert.Nil(t, err, bcm)
+		v, err := gpio.J8ToBCM(j8)
+		assert.Nil(t, err, bcm)
+		assert.Equal(t, bcm, v)
+	}
+	j8, err := gpio.BCMToJ8(gpio.GPIO4)
+	assert.Nil(t, err)
+	assert.Equal(t, 7, j8)
+	bcm, err := gpio.J8ToBCM(13)
+	assert.Nil(t, err)
+	assert.Equal(t, gpio.GPIO27, bcm)
+
+	for _, j8 := range []int{0, 1, 2, 6, 39, 41} {
+		_, err := gpio.J8ToBCM(j8)
+		assert.ErrorIs(t, err, gpio.ErrInvalidPin, j8)
+	}
+	for _, bcm := range []int{-1, gpio.MaxGPIOPin} {
+		_, err := gpio.BCMToJ8(bcm)
+		assert.ErrorIs(t, err, gpio.ErrInvalidPin, bcm)
+	}
+}
+
+func TestParsePin(t *testing.T) {
+	patterns := []struct {
+		name string
+		pin  int
+	}{
+		{"J8p15", gpio.GPIO22},
+		{"j8p07", gpio.GPIO4},
+		{"J8P13", gpio.GPIO27},
+		{"GPIO22", gpio.GPIO22},
+		{"gpio4", gpio.GPIO4},
+		{"15", gpio.GPIO15},
+		{"0", 0},
+	}
+	for _, p := range patterns {
+		pin, err := gpio.ParsePin(p.name)
+		assert.Nil(t, err, p.name)
+		assert.Equal(t, p.pin, pin, p.name)
+	}
+	for _, name := range []string{"", "J8p1", "J8p", "GPIO", "GPIO28", "28", "-1", "led"} {
+		_, err := gpio.ParsePin(name)
+		assert.ErrorIs(t, err, gpio.ErrInvalidPin, name)
+	}
+}
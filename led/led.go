@@ -0,0 +1,253 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package led drives an LED on a pin, including blinking, breathing and
+// playing brightness patterns.
+//
+// Brightness is controlled by software PWM, played as a waveform on the pin,
+// so no PWM hardware is required.
+package led
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/warthog618/gpio"
+)
+
+// Step is a single step in a brightness pattern.
+type Step struct {
+	// The brightness of the LED for the step, from 0 (off) to 1 (fully on).
+	Brightness float64
+
+	// The time the brightness is held before the next step.
+	Duration time.Duration
+}
+
+// LED drives an LED connected to a pin.
+//
+// Each effect replaces the effect already playing.
+type LED struct {
+	pin    *gpio.Pin
+	on     gpio.Level
+	period time.Duration
+
+	// Guards the following.
+	mu     sync.Mutex
+	wave   *gpio.Wave
+	closed bool
+}
+
+// Option modifies the configuration of an LED.
+type Option func(*LED)
+
+// WithActiveLevel sets the level of the pin that turns the LED on.
+//
+// The default is High.
+func WithActiveLevel(level gpio.Level) Option {
+	return func(l *LED) {
+		l.on = level
+	}
+}
+
+// WithPWMPeriod sets the period of the software PWM used to control the
+// brightness.
+//
+// The default is 10ms, which is fast enough to avoid visible flicker.
+func WithPWMPeriod(d time.Duration) Option {
+	return func(l *LED) {
+		if d > 0 {
+			l.period = d
+		}
+	}
+}
+
+// New creates an LED on the pin.
+//
+// The pin is set to an output, with the LED off, and is requested until the
+// LED is closed.
+// The GPIO must be open.
+func New(pin int, options ...Option) (*LED, error) {
+	l := &LED{
+		pin:    gpio.NewPin(pin),
+		on:     gpio.High,
+		period: 10 * time.Millisecond,
+	}
+	if l.pin == nil {
+		return nil, ErrInvalidPin
+	}
+	if err := l.pin.Request("led"); err != nil {
+		return nil, err
+	}
+	for _, option := range options {
+		option(l)
+	}
+	l.pin.Write(!l.on)
+	l.pin.Output()
+	return l, nil
+}
+
+// Close stops any effect and turns the LED off.
+//
+// The pin is left as an output.
+func (l *LED) Close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stop()
+	l.pin.Write(!l.on)
+	l.pin.Release()
+	l.closed = true
+}
+
+// On turns the LED on.
+func (l *LED) On() {
+	l.set(l.on)
+}
+
+// Off turns the LED off.
+func (l *LED) Off() {
+	l.set(!l.on)
+}
+
+// SetBrightness holds the LED at the brightness, from 0 (off) to 1 (fully on).
+func (l *LED) SetBrightness(b float64) {
+	l.play(l.appendBrightness(nil, b, l.period), 0)
+}
+
+// Blink turns the LED on and off, with equal on and off times, repeating every
+// period.
+func (l *LED) Blink(period time.Duration) {
+	l.play([]gpio.Step{
+		{Level: l.on, Duration: period / 2},
+		{Level: !l.on, Duration: period - period/2},
+	}, 0)
+}
+
+// Breathe smoothly raises and lowers the brightness of the LED, repeating
+// every period.
+func (l *LED) Breathe(period time.Duration) {
+	n := int(period / l.period)
+	if n < 2 {
+		n = 2
+	}
+	steps := make([]Step, n)
+	for i := range steps {
+		// raised cosine, squared to better match perceived brightness.
+		b := (1 - math.Cos(2*math.Pi*float64(i)/float64(n))) / 2
+		steps[i] = Step{Brightness: b * b, Duration: period / time.Duration(n)}
+	}
+	l.Pattern(steps, 0)
+}
+
+// Pattern plays the brightness pattern on the LED.
+//
+// The steps are played repeat times, or indefinitely if repeat is 0.
+// The LED is left at the brightness of the final step, if that is 0 or 1, or
+// otherwise at its level at the end of the final PWM cycle.
+func (l *LED) Pattern(steps []Step, repeat int) {
+	var ws []gpio.Step
+	for _, s := range steps {
+		ws = l.appendBrightness(ws, s.Brightness, s.Duration)
+	}
+	l.play(ws, repeat)
+}
+
+// Stop stops any effect, leaving the LED in its current state.
+func (l *LED) Stop() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stop()
+}
+
+// Wait waits for the current effect to complete.
+//
+// Effects that repeat indefinitely only complete when stopped or replaced.
+func (l *LED) Wait() {
+	l.mu.Lock()
+	w := l.wave
+	l.mu.Unlock()
+	if w != nil {
+		w.Wait()
+	}
+}
+
+// set stops any effect and sets the pin to the level.
+func (l *LED) set(level gpio.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return
+	}
+	l.stop()
+	l.pin.Write(level)
+}
+
+// play replaces any effect with the waveform.
+func (l *LED) play(steps []gpio.Step, repeat int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return
+	}
+	l.stop()
+	if len(steps) == 1 {
+		// a constant level needs no player.
+		l.pin.Write(steps[0].Level)
+		return
+	}
+	l.wave = l.pin.PlayWave(steps, repeat)
+}
+
+// stop stops any effect.
+//
+// Assumes the caller holds the lock.
+func (l *LED) stop() {
+	if l.wave != nil {
+		l.wave.Stop()
+		l.wave = nil
+	}
+}
+
+// appendBrightness appends the waveform that holds the LED at the brightness
+// for the duration.
+func (l *LED) appendBrightness(steps []gpio.Step, b float64, d time.Duration) []gpio.Step {
+	if b <= 0 {
+		return appendStep(steps, !l.on, d)
+	}
+	if b >= 1 {
+		return appendStep(steps, l.on, d)
+	}
+	n := int(d / l.period)
+	if n < 1 {
+		n = 1
+	}
+	cycle := d / time.Duration(n)
+	high := time.Duration(float64(cycle) * b)
+	for i := 0; i < n; i++ {
+		steps = appendStep(steps, l.on, high)
+		steps = appendStep(steps, !l.on, cycle-high)
+	}
+	return steps
+}
+
+// appendStep appends the step to the waveform, merging it with the final step
+// if they have the same level, and dropping it if it has no duration.
+func appendStep(steps []gpio.Step, level gpio.Level, d time.Duration) []gpio.Step {
+	if d <= 0 {
+		return steps
+	}
+	if n := len(steps); n > 0 && steps[n-1].Level == level {
+		steps[n-1].Duration += d
+		return steps
+	}
+	return append(steps, gpio.Step{Level: level, Duration: d})
+}
+
+var (
+	// ErrInvalidPin indicates the pin is not a valid GPIO pin.
+	ErrInvalidPin = errors.New("invalid pin")
+)
@@ -0,0 +1,333 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package ledmatrix drives charlieplexed and row/column multiplexed LED
+// matrices, refreshing them from a framebuffer.
+//
+// Only one row of the matrix is lit at a time, with the rows lit in turn by a
+// background goroutine fast enough that persistence of vision makes the whole
+// matrix appear lit.  Pins not being driven are tri-stated by setting them to
+// inputs.
+package ledmatrix
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/warthog618/gpio"
+)
+
+// Matrix is an LED matrix refreshed from a framebuffer.
+//
+// Pixels are addressed by column, x, and row, y, with the origin at the top
+// left.
+type Matrix struct {
+	width  int
+	height int
+	// the time each row is lit.
+	linePeriod time.Duration
+	// lights row n of the matrix, with all other rows dark.
+	scan func(n int)
+	// the pins driving the matrix.
+	pins []*gpio.Pin
+
+	// Guards the following.
+	mu sync.Mutex
+	fb []bool
+
+	// closed to stop the refresh goroutine.
+	stopCh chan struct{}
+	// closed when the refresh goroutine exits.
+	doneCh chan struct{}
+}
+
+// Option modifies the configuration of a Matrix.
+type Option func(*config)
+
+type config struct {
+	frame time.Duration
+	row   gpio.Level
+	col   gpio.Level
+}
+
+// WithRefreshRate sets the number of times per second the whole matrix is
+// refreshed.
+//
+// The default is 100Hz, which is fast enough to avoid visible flicker.
+func WithRefreshRate(hz int) Option {
+	return func(c *config) {
+		if hz > 0 {
+			c.frame = time.Second / time.Duration(hz)
+		}
+	}
+}
+
+// WithActiveLevels sets the levels of the row and column pins of a
+// multiplexed matrix that light an LED.
+//
+// The default is High for rows and Low for columns, as for a matrix with
+// rows connected to the LED anodes and columns to the cathodes.
+//
+// This has no effect on charlieplexed matrices.
+func WithActiveLevels(row, col gpio.Level) Option {
+	return func(c *config) {
+		c.row = row
+		c.col = col
+	}
+}
+
+func newConfig(options []Option) config {
+	c := config{frame: 10 * time.Millisecond, row: gpio.High, col: gpio.Low}
+	for _, option := range options {
+		option(&c)
+	}
+	return c
+}
+
+// NewCharlieplex creates a Matrix driving a charlieplexed matrix on the pins
+// and starts refreshing it.
+//
+// A charlieplexed matrix on n pins has an LED between each pair of pins in
+// each direction, for n*(n-1) LEDs.  The matrix is n-1 columns wide and n rows
+// high, with the LEDs in row y having their anodes connected to pins[y], and
+// their cathodes connected to the remaining pins, in order.
+//
+// The pins are requested until the Matrix is closed.
+// The GPIO must be open.
+func NewCharlieplex(pins []int, options ...Option) (*Matrix, error) {
+	if len(pins) < 2 {
+		return nil, ErrTooFewPins
+	}
+	pp, err := newPins(pins)
+	if err != nil {
+		return nil, err
+	}
+	c := newConfig(options)
+	m := newMatrix(len(pins)-1, len(pins), pp, c)
+	m.scan = m.charlieplexScan
+	m.start()
+	return m, nil
+}
+
+// NewMultiplexed creates a Matrix driving a row/column multiplexed matrix and
+// starts refreshing it.
+//
+// The matrix is scanned by row, with the inactive rows tri-stated and the
+// columns driven to light the LEDs in the active row.  The row and column
+// pins may require drivers to source or sink the current for a whole row or
+// column.
+//
+// The pins are requested until the Matrix is closed.
+// The GPIO must be open.
+func NewMultiplexed(rows, cols []int, options ...Option) (*Matrix, error) {
+	if len(rows) == 0 || len(cols) == 0 {
+		return nil, ErrTooFewPins
+	}
+	pp, err := newPins(append(append([]int(nil), rows...), cols...))
+	if err != nil {
+		return nil, err
+	}
+	c := newConfig(options)
+	m := newMatrix(len(cols), len(rows), pp, c)
+	rp := pp[:len(rows)]
+	cp := pp[len(rows):]
+	m.scan = func(n int) {
+		m.multiplexScan(rp, cp, c, n)
+	}
+	m.start()
+	return m, nil
+}
+
+func newPins(pins []int) ([]*gpio.Pin, error) {
+	pp := make([]*gpio.Pin, len(pins))
+	for i, pin := range pins {
+		pp[i] = gpio.NewPin(pin)
+		if pp[i] == nil {
+			return nil, ErrInvalidPin
+		}
+	}
+	if err := gpio.RequestPins("ledmatrix", pp...); err != nil {
+		return nil, err
+	}
+	return pp, nil
+}
+
+func newMatrix(width, height int, pins []*gpio.Pin, c config) *Matrix {
+	m := &Matrix{
+		width:  width,
+		height: height,
+		pins:   pins,
+		fb:     make([]bool, width*height),
+		stopCh: make(chan struct{}),
+		doneCh: make(chan struct{}),
+	}
+	m.linePeriod = c.frame / time.Duration(height)
+	if m.linePeriod <= 0 {
+		m.linePeriod = 1
+	}
+	return m
+}
+
+// start tri-states all the pins and starts refreshing the matrix.
+func (m *Matrix) start() {
+	m.darken()
+	go m.run()
+}
+
+// Width returns the number of columns in the matrix.
+func (m *Matrix) Width() int {
+	return m.width
+}
+
+// Height returns the number of rows in the matrix.
+func (m *Matrix) Height() int {
+	return m.height
+}
+
+// Set sets the state of the LED at column x, row y.
+//
+// Coordinates outside the matrix are ignored.
+func (m *Matrix) Set(x, y int, on bool) {
+	if x < 0 || x >= m.width || y < 0 || y >= m.height {
+		return
+	}
+	m.mu.Lock()
+	m.fb[y*m.width+x] = on
+	m.mu.Unlock()
+}
+
+// Get returns the state of the LED at column x, row y.
+//
+// Coordinates outside the matrix return false.
+func (m *Matrix) Get(x, y int) bool {
+	if x < 0 || x >= m.width || y < 0 || y >= m.height {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.fb[y*m.width+x]
+}
+
+// SetFrame replaces the framebuffer with the frame, indexed by row and then
+// column.
+//
+// Rows and columns beyond the frame are turned off, and those beyond the
+// matrix are ignored.
+func (m *Matrix) SetFrame(frame [][]bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for y := 0; y < m.height; y++ {
+		for x := 0; x < m.width; x++ {
+			on := false
+			if y < len(frame) && x < len(frame[y]) {
+				on = frame[y][x]
+			}
+			m.fb[y*m.width+x] = on
+		}
+	}
+}
+
+// Clear turns off all the LEDs.
+func (m *Matrix) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.fb {
+		m.fb[i] = false
+	}
+}
+
+// Close stops refreshing the matrix and tri-states all the pins.
+func (m *Matrix) Close() {
+	close(m.stopCh)
+	<-m.doneCh
+	m.darken()
+	gpio.ReleasePins(m.pins...)
+}
+
+// run refreshes the matrix, lighting each row in turn.
+func (m *Matrix) run() {
+	defer close(m.doneCh)
+	ticker := time.NewTicker(m.linePeriod)
+	defer ticker.Stop()
+	n := 0
+	for {
+		select {
+		case <-ticker.C:
+			m.scan(n)
+			n = (n + 1) % m.height
+		case <-m.stopCh:
+			return
+		}
+	}
+}
+
+// darken tri-states all the pins.
+func (m *Matrix) darken() {
+	for _, pin := range m.pins {
+		pin.Input()
+	}
+}
+
+// row returns a copy of row y of the framebuffer.
+func (m *Matrix) row(y int) []bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]bool(nil), m.fb[y*m.width:(y+1)*m.width]...)
+}
+
+// charlieplexScan lights row n, driving its anode pin high and the cathode
+// pins of the lit LEDs low, with all other pins tri-stated.
+func (m *Matrix) charlieplexScan(n int) {
+	row := m.row(n)
+	m.darken()
+	anode := m.pins[n]
+	anode.High()
+	lit := false
+	for x, on := range row {
+		if !on {
+			continue
+		}
+		c := x
+		if c >= n {
+			// skip the anode pin.
+			c++
+		}
+		cathode := m.pins[c]
+		cathode.Low()
+		cathode.SetMode(gpio.Output)
+		lit = true
+	}
+	if lit {
+		anode.SetMode(gpio.Output)
+	}
+}
+
+// multiplexScan lights row n, driving the columns for the LEDs in the row and
+// then enabling the row, with all other rows tri-stated.
+func (m *Matrix) multiplexScan(rows, cols []*gpio.Pin, c config, n int) {
+	row := m.row(n)
+	for _, r := range rows {
+		r.SetMode(gpio.Input)
+	}
+	for x, on := range row {
+		if on {
+			cols[x].Write(c.col)
+		} else {
+			cols[x].Write(!c.col)
+		}
+		cols[x].SetMode(gpio.Output)
+	}
+	rows[n].Write(c.row)
+	rows[n].SetMode(gpio.Output)
+}
+
+var (
+	// ErrInvalidPin indicates a pin is not a valid GPIO pin.
+	ErrInvalidPin = errors.New("invalid pin")
+
+	// ErrTooFewPins indicates there are too few pins to form a matrix.
+	ErrTooFewPins = errors.New("too few pins")
+)
@@ -0,0 +1,126 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Lifecycle and accounting of Pins.
+
+package gpio
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	// pinsLock covers openPins.
+	pinsLock sync.Mutex
+	// openPins are the pins created by NewTrackedPin and not yet closed.
+	openPins = map[*Pin]struct{}{}
+)
+
+// PinCloseOption modifies the behaviour of Pin.Close.
+type PinCloseOption func(*pinCloseConfig)
+
+type pinCloseConfig struct {
+	input bool
+}
+
+// WithRevertToInput sets the pin to an input when it is closed, so it is no
+// longer driven.
+func WithRevertToInput() PinCloseOption {
+	return func(c *pinCloseConfig) {
+		c.input = true
+	}
+}
+
+// Close releases the resources held by the pin, removing any watches on the
+// pin from the default Watcher, which unexports the pin from sysfs if the
+// Watcher exported it, and releasing any claim made by Request.
+//
+// Pins that are no longer needed should be closed, as the exports otherwise
+// persist after the process exits and block other processes from watching
+// the pin.  Finalizers are not used to close pins automatically, as a watched
+// pin is referenced by its Watcher, and a requested pin by its claim, so
+// neither would be garbage collected.
+//
+// The closed pin is removed from OpenPins, its shadow level and pull are
+// cleared, and it is no longer usable - operations that return an error, such
+// as AddWatch, Request and TryWrite, return ErrPinClosed, while Read returns
+// Low, and SetMode, SetPull and Write have no effect.  The pin is also
+// ignored by any PinGroup containing it.
+//
+// Watches on the pin in other Watchers are not removed.
+// Closing a closed pin has no effect.
+func (pin *Pin) Close(options ...PinCloseOption) {
+	if !atomic.CompareAndSwapInt32(&pin.closed, 0, 1) {
+		return
+	}
+	cfg := pinCloseConfig{}
+	for _, option := range options {
+		option(&cfg)
+	}
+	pin.Unwatch()
+	pin.Release()
+	if cfg.input && !pin.Stale() && !Protected(pin.pin) {
+		pin.setMode(Input)
+	}
+	pin.shadow = Low
+	pin.pull = PullUnknown
+	pinsLock.Lock()
+	delete(openPins, pin)
+	pinsLock.Unlock()
+}
+
+// Closed returns true if the pin has been closed.
+func (pin *Pin) Closed() bool {
+	return atomic.LoadInt32(&pin.closed) != 0
+}
+
+// OpenPins returns the pins created by NewTrackedPin that have not been
+// closed, in pin order.
+//
+// Pins are also removed when the GPIO is closed, and are restored by
+// Revalidate.
+func OpenPins() []*Pin {
+	pinsLock.Lock()
+	pp := make([]*Pin, 0, len(openPins))
+	for pin := range openPins {
+		pp = append(pp, pin)
+	}
+	pinsLock.Unlock()
+	sort.Slice(pp, func(i, j int) bool { return pp[i].pin < pp[j].pin })
+	return pp
+}
+
+// usable returns an error if the pin is closed or stale.
+func (pin *Pin) usable() error {
+	if pin.Closed() {
+		return ErrPinClosed
+	}
+	if pin.Stale() {
+		return ErrNotOpen
+	}
+	return nil
+}
+
+func registerPin(pin *Pin) {
+	pinsLock.Lock()
+	openPins[pin] = struct{}{}
+	pinsLock.Unlock()
+}
+
+// clearOpenPins removes all pins from OpenPins, as they are stale once the
+// GPIO is closed.
+func clearOpenPins() {
+	pinsLock.Lock()
+	openPins = map[*Pin]struct{}{}
+	pinsLock.Unlock()
+}
+
+var (
+	// ErrPinClosed indicates the pin has been closed.
+	ErrPinClosed = errors.New("pin closed")
+)
@@ -0,0 +1,54 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Debug logging of pin and watcher activity.
+
+package gpio
+
+import (
+	"sync/atomic"
+)
+
+// Logger receives debug messages from the package.
+//
+// It is satisfied by *log.Logger, and is easily adapted to other logging
+// frameworks.
+type Logger interface {
+	Printf(format string, args ...interface{})
+}
+
+// loggerBox allows a nil Logger to be stored in an atomic.Value.
+type loggerBox struct {
+	l Logger
+}
+
+var logger atomic.Value
+
+// SetLogger sets the Logger that receives debug messages for sysfs exports,
+// epoll registrations, mode changes and errors.
+//
+// A nil Logger, the default, disables logging.
+// The Logger may be called from any goroutine, including those of Watchers,
+// so must be safe for concurrent use.
+func SetLogger(l Logger) {
+	logger.Store(loggerBox{l})
+}
+
+// getLogger returns the current Logger, or nil if logging is disabled.
+//
+// Callers on hot paths should check this before formatting any arguments.
+func getLogger() Logger {
+	if b, ok := logger.Load().(loggerBox); ok {
+		return b.l
+	}
+	return nil
+}
+
+// logf sends the message to the Logger, if any.
+func logf(format string, args ...interface{}) {
+	if l := getLogger(); l != nil {
+		l.Printf(format, args...)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Test suite for log module.
+//
+// Tests do not use any pins.
+package gpio
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recorder struct {
+	msgs []string
+}
+
+func (r *recorder) Printf(format string, args ...interface{}) {
+	r.msgs = append(r.msgs, fmt.Sprintf(format, args...))
+}
+
+func TestSetLogger(t *testing.T) {
+	defer SetLogger(nil)
+	assert.Nil(t, getLogger())
+	logf("dropped")
+
+	r := &recorder{}
+	SetLogger(r)
+	logf("GPIO%d: exported", 4)
+	pinError("export", 4, ErrTimeout)
+	assert.Equal(t, []string{"GPIO4: exported", "export GPIO4: timeout"}, r.msgs)
+
+	SetLogger(nil)
+	assert.Nil(t, getLogger())
+	logf("dropped")
+	assert.Len(t, r.msgs, 2)
+}
@@ -0,0 +1,127 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Watches on the combined levels of a PinGroup.
+
+package gpio
+
+import (
+	"sync"
+	"time"
+)
+
+// GroupWatch watches the pins of a PinGroup and calls a handler when their
+// combined levels match a pattern.
+type GroupWatch struct {
+	group    *PinGroup
+	invert   uint
+	mask     uint
+	value    uint
+	debounce time.Duration
+	handler  func(uint)
+	watches  []*Watch
+
+	// Guards the following.
+	mu      sync.Mutex
+	timer   *time.Timer
+	settled uint
+	synced  bool
+	closed  bool
+}
+
+// AddGroupWatch watches the pins of the group, and calls the handler with the
+// levels of the group, as per PinGroup.Read, whenever they change and the
+// levels of the pins in the mask equal the value.
+//
+// The group is debounced as a unit - the levels are only evaluated once no
+// pin in the group has changed for the debounce period.  The levels are also
+// evaluated when the watch is added, so the handler is called immediately if
+// they already match.
+//
+// A mask of 0 matches any levels, so the handler is called on every change.
+func (w *Watcher) AddGroupWatch(g *PinGroup, mask, value uint, debounce time.Duration, handler func(uint)) (*GroupWatch, error) {
+	return w.addGroupWatch(g, 0, mask, value, debounce, handler)
+}
+
+// addGroupWatch adds a GroupWatch with the levels of the pins in invert
+// inverted before being matched.
+func (w *Watcher) addGroupWatch(g *PinGroup, invert, mask, value uint, debounce time.Duration, handler func(uint)) (*GroupWatch, error) {
+	gw := &GroupWatch{
+		group:    g,
+		invert:   invert,
+		mask:     mask,
+		value:    value & mask,
+		debounce: debounce,
+		handler:  handler,
+	}
+	for _, pin := range g.pins {
+		wt, err := w.AddWatch(pin, EdgeBoth, gw.edge)
+		if err != nil {
+			gw.Unwatch()
+			return nil, err
+		}
+		gw.watches = append(gw.watches, wt)
+	}
+	return gw, nil
+}
+
+// Watch adds a GroupWatch on the group to the default Watcher.
+//
+// This behaves as per Watcher.AddGroupWatch.
+func (g *PinGroup) Watch(mask, value uint, debounce time.Duration, handler func(uint)) (*GroupWatch, error) {
+	watcher, err := getDefaultWatcher()
+	if err != nil {
+		return nil, err
+	}
+	return watcher.AddGroupWatch(g, mask, value, debounce, handler)
+}
+
+// Unwatch removes the watches on the pins of the group.
+func (gw *GroupWatch) Unwatch() {
+	gw.mu.Lock()
+	gw.closed = true
+	if gw.timer != nil {
+		gw.timer.Stop()
+	}
+	gw.mu.Unlock()
+	for _, wt := range gw.watches {
+		wt.Unwatch()
+	}
+}
+
+// edge restarts the debounce period on an edge on any pin in the group.
+func (gw *GroupWatch) edge(Event) {
+	gw.mu.Lock()
+	defer gw.mu.Unlock()
+	if gw.closed {
+		return
+	}
+	if gw.timer == nil {
+		gw.timer = time.AfterFunc(gw.debounce, gw.settle)
+		return
+	}
+	gw.timer.Reset(gw.debounce)
+}
+
+// settle evaluates the levels of the group once they have been stable for the
+// debounce period.
+func (gw *GroupWatch) settle() {
+	gw.mu.Lock()
+	if gw.closed {
+		gw.mu.Unlock()
+		return
+	}
+	v := gw.group.Read() ^ gw.invert
+	if gw.synced && v == gw.settled {
+		gw.mu.Unlock()
+		return
+	}
+	gw.synced = true
+	gw.settled = v
+	gw.mu.Unlock()
+	if v&gw.mask == gw.value {
+		gw.handler(v)
+	}
+}
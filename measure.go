@@ -0,0 +1,218 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Pulse measurement for DIO Pins.
+
+package gpio
+
+import (
+	"sort"
+	"time"
+)
+
+// MeasurePulse returns the width of the next high pulse on the pin.
+//
+// The width is the time between the rising and falling edges, as timestamped
+// by the watcher, so it is not affected by handler latency.  The timestamps
+// are taken when the watcher wakes from EpollWait, so do include the
+// interrupt and scheduling latency, which varies between edges.
+// Returns ErrTimeout if a complete pulse is not seen within the timeout.
+//
+// The pin is watched via sysfs for the duration of the measurement, so it
+// must not be watched elsewhere.  If the pin cannot be watched via sysfs, such
+// as when it has already been exported, an error is returned rather than
+// falling back to polling, which would only measure to the poll period.
+func (p *Pin) MeasurePulse(timeout time.Duration) (time.Duration, error) {
+	events, closer, err := p.watchEvents(EdgeBoth, true)
+	if err != nil {
+		return 0, err
+	}
+	defer closer()
+	deadline := time.After(timeout)
+	var rise time.Time
+	for {
+		select {
+		case evt := <-events:
+			if evt.Level == High {
+				rise = evt.Time
+			} else if !rise.IsZero() {
+				return evt.Time.Sub(rise), nil
+			}
+		case <-deadline:
+			return 0, pinError("measure pulse", p.pin, ErrTimeout)
+		}
+	}
+}
+
+// Frequency returns the frequency of the signal on the pin, in Hz, measured
+// over the window.
+//
+// The frequency is determined from the rising edges seen within the window,
+// using the time between the first and last, rather than the window itself.
+// The edges are timestamped as per MeasurePulse.
+// Returns 0 if fewer than two rising edges are seen.
+//
+// The pin is watched via sysfs for the duration of the measurement, as per
+// MeasurePulse.
+func (p *Pin) Frequency(window time.Duration) (float64, error) {
+	events, closer, err := p.watchEvents(EdgeRising, true)
+	if err != nil {
+		return 0, err
+	}
+	defer closer()
+	deadline := time.After(window)
+	var first, last time.Time
+	count := 0
+	for {
+		select {
+		case evt := <-events:
+			if count == 0 {
+				first = evt.Time
+			}
+			last = evt.Time
+			count++
+		case <-deadline:
+			if count < 2 {
+				return 0, nil
+			}
+			return float64(count-1) / last.Sub(first).Seconds(), nil
+		}
+	}
+}
+
+// watchEvents returns the triggering events on the pin, excluding the initial
+// sync event, and a function to stop the watch.
+//
+// A private Watcher with serial dispatch is used so the events are received
+// in order.  If sysfsOnly is set then an error is returned if the pin would be
+// polled, as polling is too coarse for measurements - the SysfsErr of the
+// watch if the pin could not be watched via sysfs, or ErrNotSupported if sysfs
+// is not available.
+func (p *Pin) watchEvents(edge Edge, sysfsOnly bool) (<-chan Event, func(), error) {
+	w, err := NewWatcher(WithEventQueue(queueDepth, OverflowDropNewest))
+	if err != nil {
+		return nil, nil, err
+	}
+	events := make(chan Event, queueDepth)
+	synced := false
+	wt, err := w.AddWatch(p, edge, func(evt Event) {
+		if !synced {
+			synced = true
+			return
+		}
+		select {
+		case events <- evt:
+		default:
+		}
+	})
+	if err != nil {
+		w.Close()
+		return nil, nil, err
+	}
+	if sysfsOnly && wt.Mechanism() == WatchPoll {
+		err = wt.SysfsErr()
+		if err == nil {
+			err = ErrNotSupported
+		}
+		w.Close()
+		return nil, nil, pinError("measure", p.pin, err)
+	}
+	return events, w.Close, nil
+}
+
+// LatencyStats summarises the interrupt latencies measured by
+// MeasureInterruptLatency.
+type LatencyStats struct {
+	// The number of edges measured.
+	Count int
+
+	// The number of edges not seen by the handler within the timeout.
+	Missed int
+
+	// The statistics of the measured latencies.
+	Min    time.Duration
+	Max    time.Duration
+	Mean   time.Duration
+	Median time.Duration
+	P99    time.Duration
+}
+
+// MeasureInterruptLatency measures the latency between driving an edge on
+// pinOut and the handler of a watch on pinIn being called, over n edges.
+//
+// The pins must be connected, e.g. by a jumper.  pinOut is set to an output
+// and pinIn to an input.
+// The options are applied to the Watcher used for the measurement, so the
+// effect of tuning options, such as WithPriority, can be quantified.
+//
+// Edges not seen within 100ms are counted as missed, and ErrTimeout is
+// returned if no edges are seen.
+func MeasureInterruptLatency(pinIn, pinOut *Pin, n int, options ...WatcherOption) (LatencyStats, error) {
+	var stats LatencyStats
+	if n < 1 {
+		return stats, nil
+	}
+	pinOut.Low()
+	pinOut.Output()
+	pinIn.Input()
+	options = append([]WatcherOption{WithEventQueue(queueDepth, OverflowDropNewest)}, options...)
+	w, err := NewWatcher(options...)
+	if err != nil {
+		return stats, err
+	}
+	defer w.Close()
+	type arrival struct {
+		level Level
+		time  time.Time
+	}
+	arrivals := make(chan arrival, queueDepth)
+	_, err = w.AddWatch(pinIn, EdgeBoth, func(evt Event) {
+		select {
+		case arrivals <- arrival{evt.Level, time.Now()}:
+		default:
+		}
+	})
+	if err != nil {
+		return stats, err
+	}
+	latencies := make([]time.Duration, 0, n)
+	level := Low
+	for i := 0; i < n; i++ {
+		level = !level
+		start := time.Now()
+		pinOut.Write(level)
+		deadline := time.After(100 * time.Millisecond)
+	wait:
+		for {
+			select {
+			case a := <-arrivals:
+				// ignore the initial sync and any stale events.
+				if a.level == level && a.time.After(start) {
+					latencies = append(latencies, a.time.Sub(start))
+					break wait
+				}
+			case <-deadline:
+				stats.Missed++
+				break wait
+			}
+		}
+	}
+	pinOut.Low()
+	if len(latencies) == 0 {
+		return stats, pinError("measure latency", pinIn.pin, ErrTimeout)
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	var sum time.Duration
+	for _, l := range latencies {
+		sum += l
+	}
+	stats.Count = len(latencies)
+	stats.Min = latencies[0]
+	stats.Max = latencies[len(latencies)-1]
+	stats.Mean = sum / time.Duration(len(latencies))
+	stats.Median = latencies[len(latencies)/2]
+	stats.P99 = latencies[len(latencies)*99/100]
+	return stats, nil
+}
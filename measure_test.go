@@ -0,0 +1,97 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && hardware
+// +build linux,hardware
+
+// Test suite for measure module.
+//
+// Tests use Raspberry Pi J8 pins 15 and 16 which must be jumpered together.
+package gpio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Looped tests require a jumper across Raspberry Pi J8 pins 15 and 16.
+func TestMeasurePulseLooped(t *testing.T) {
+	pinIn, pinOut, watcher := setupIntr(t)
+	defer teardownIntr(pinIn, pinOut, watcher)
+	_, err := pinIn.MeasurePulse(10 * time.Millisecond)
+	assert.ErrorIs(t, err, ErrTimeout)
+	done := make(chan struct{})
+	defer close(done)
+	// pulse repeatedly as the watch may take a while to start.
+	go func() {
+		for {
+			select {
+			case <-time.After(20 * time.Millisecond):
+				pinOut.High()
+				time.Sleep(5 * time.Millisecond)
+				pinOut.Low()
+			case <-done:
+				return
+			}
+		}
+	}()
+	width, err := pinIn.MeasurePulse(time.Second)
+	assert.Nil(t, err)
+	assert.InDelta(t, 5*time.Millisecond, width, float64(time.Millisecond))
+}
+
+// Looped tests require a jumper across Raspberry Pi J8 pins 15 and 16.
+func TestFrequencyLooped(t *testing.T) {
+	pinIn, pinOut, watcher := setupIntr(t)
+	defer teardownIntr(pinIn, pinOut, watcher)
+	f, err := pinIn.Frequency(10 * time.Millisecond)
+	assert.Nil(t, err)
+	assert.Equal(t, 0.0, f)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		tick := time.NewTicker(5 * time.Millisecond)
+		defer tick.Stop()
+		for {
+			select {
+			case <-tick.C:
+				pinOut.Toggle()
+			case <-done:
+				return
+			}
+		}
+	}()
+	// toggled every 5ms is 100Hz
+	f, err = pinIn.Frequency(200 * time.Millisecond)
+	assert.Nil(t, err)
+	assert.InDelta(t, 100, f, 10)
+}
+
+// Looped tests require a jumper across Raspberry Pi J8 pins 15 and 16.
+func TestMeasureInterruptLatencyLooped(t *testing.T) {
+	pinIn, pinOut, watcher := setupIntr(t)
+	defer teardownIntr(pinIn, pinOut, watcher)
+	stats, err := MeasureInterruptLatency(pinIn, pinOut, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, LatencyStats{}, stats)
+	stats, err = MeasureInterruptLatency(pinIn, pinOut, 20)
+	assert.Nil(t, err)
+	assert.Equal(t, 20, stats.Count+stats.Missed)
+	assert.True(t, stats.Min <= stats.Median)
+	assert.True(t, stats.Median <= stats.P99)
+	assert.True(t, stats.P99 <= stats.Max)
+}
+
+func TestMeasurePulseWatched(t *testing.T) {
+	pinIn, pinOut, watcher := setupIntr(t)
+	defer teardownIntr(pinIn, pinOut, watcher)
+	assert.Nil(t, watcher.RegisterPin(pinIn, EdgeBoth, func(*Pin) {}))
+	// the pin is already exported, so would only be polled.
+	_, err := pinIn.MeasurePulse(10 * time.Millisecond)
+	assert.NotNil(t, err)
+	assert.NotErrorIs(t, err, ErrTimeout)
+}
@@ -3,19 +3,15 @@
 // Use of this source code is governed by an MIT-style
 // license that can be found in the LICENSE file.
 
-//go:build linux
-// +build linux
-
 package gpio
 
 import (
 	"errors"
-	"os"
-	"reflect"
 	"sync"
+	"sync/atomic"
 	"unsafe"
 
-	"golang.org/x/sys/unix"
+	"github.com/warthog618/gpio/internal/periph"
 )
 
 // Chipset identifies the GPIO chip.
@@ -32,6 +28,10 @@ const (
 	BCM2711
 )
 
+// minRegs is the number of registers that must be mapped, up to and including
+// the last of the BCM2711 pull registers.
+const minRegs = 61
+
 // Arrays for 8 / 32 bit access to memory and a semaphore for write locking
 var (
 	chipset Chipset
@@ -42,53 +42,77 @@ var (
 	memlock sync.Mutex
 	mem     []uint32
 	mem8    []uint8
+
+	// The openlock covers refs, and is held for the duration of Open and Close.
+	openlock sync.Mutex
+	// refs is the number of calls to Open without a matching Close.
+	refs int
 )
 
 // Open and memory map GPIO memory range from /dev/gpiomem .
-// Some reflection magic is used to convert it to a unsafe []uint32 pointer
-func Open() (err error) {
-	if len(mem) != 0 {
-		return ErrAlreadyOpen
-	}
-	file, err := os.OpenFile(
-		"/dev/gpiomem",
-		os.O_RDWR|os.O_SYNC,
-		0)
-
-	if err != nil {
-		return
+//
+// If /dev/gpiomem does not exist, the GPIO memory range is mapped from /dev/mem
+// instead, using the peripheral base address from the device-tree.  That
+// requires root privileges.
+//
+// Returns ErrNotSupported on platforms other than Linux.
+//
+// Open is reference counted, so it may be called by independent users within
+// the process, and the memory remains mapped until each has called Close.
+func Open(options ...OpenOption) (err error) {
+	openlock.Lock()
+	defer openlock.Unlock()
+	if refs > 0 {
+		refs++
+		applyOpenOptions(options)
+		return nil
 	}
-	defer file.Close()
-
 	memlock.Lock()
 	defer memlock.Unlock()
 
-	// Memory map GPIO registers to byte array
-	mem8, err = unix.Mmap(
-		int(file.Fd()),
-		0,
-		memLength,
-		unix.PROT_READ|unix.PROT_WRITE,
-		unix.MAP_SHARED)
-
-	if err != nil {
+	chip := detectChip()
+	if err = mapMem(chip); err != nil {
 		return
 	}
+	if chip == 0 {
+		// identify the chip from the registers.
+		chip = BCM2711
+		if mem[60] == 0x6770696f {
+			chip = BCM2835
+		}
+	}
+	chipset = chip
+	protectBoard()
+	applyOpenOptions(options)
+	refs = 1
 
-	// Convert mapped byte memory to unsafe []uint32 pointer, adjust length as needed
-	header := *(*reflect.SliceHeader)(unsafe.Pointer(&mem8))
-	header.Len /= 4 // (32 bit = 4 bytes)
-	header.Cap /= 4
-
-	mem = *(*[]uint32)(unsafe.Pointer(&header))
+	return nil
+}
 
-	if mem[60] == 0x6770696f {
-		chipset = BCM2835
-	} else {
-		chipset = BCM2711
+// toRegs converts the mapped memory to registers, checking that the mapping
+// is aligned and covers all the registers.
+func toRegs(mem8 []uint8) ([]uint32, error) {
+	if len(mem8) < minRegs*4 || len(mem8)%4 != 0 {
+		return nil, ErrInvalidMapping
 	}
+	p := unsafe.Pointer(&mem8[0])
+	if uintptr(p)%4 != 0 {
+		return nil, ErrInvalidMapping
+	}
+	return unsafe.Slice((*uint32)(p), len(mem8)/4), nil
+}
 
-	return nil
+func applyOpenOptions(options []OpenOption) {
+	cfg := openConfig{}
+	for _, option := range options {
+		option(&cfg)
+	}
+	if cfg.revert {
+		atomic.StoreInt32(&reverting, 1)
+	}
+	if cfg.strict {
+		atomic.StoreInt32(&strictClose, 1)
+	}
 }
 
 // Chip identifies the chipset on the system.
@@ -98,16 +122,53 @@ func Chip() Chipset {
 	return chipset
 }
 
-// Close removes the interrupt handlers and unmaps GPIO memory
+// Close releases a reference taken by Open.
+//
+// When the last reference is released, Close calls the functions registered
+// with OnClose and Cleanup, reverts the pins if opened WithRevertOnClose,
+// removes the interrupt handlers and unmaps GPIO memory.
+//
+// If opened WithStrictClose, that Close instead returns ErrWatchesActive if the
+// default Watcher is watching any pins.
 func Close() error {
+	openlock.Lock()
+	defer openlock.Unlock()
+	if refs == 0 {
+		return nil
+	}
+	refs--
+	if refs > 0 {
+		return nil
+	}
+	if atomic.LoadInt32(&strictClose) != 0 && watchesActive() {
+		refs++
+		return ErrWatchesActive
+	}
+	atomic.StoreInt32(&strictClose, 0)
+	runCleanups()
+	releaseAll()
+	atomic.AddUint32(&openGen, 1)
+	clearOpenPins()
+	// closed before the registers are unmapped, as polled pins are read by
+	// the Watcher.
+	closeInterrupts()
 	memlock.Lock()
 	defer memlock.Unlock()
-	closeInterrupts()
 	mem = make([]uint32, 0)
-	return unix.Munmap(mem8)
+	return unmapMem()
 }
 
 var (
 	// ErrAlreadyOpen indicates the mem is already open.
+	//
+	// Deprecated: Open is reference counted and no longer returns this error.
 	ErrAlreadyOpen = errors.New("already open")
+
+	// ErrInvalidMapping indicates the mapped GPIO memory is too short or
+	// misaligned to contain the GPIO registers.
+	ErrInvalidMapping = errors.New("invalid GPIO memory mapping")
+
+	// ErrNotSupported indicates the operation is not supported on the
+	// platform.
+	ErrNotSupported = periph.ErrNotSupported
 )
@@ -3,6 +3,9 @@
 // Use of this source code is governed by an MIT-style
 // license that can be found in the LICENSE file.
 
+//go:build linux && hardware
+// +build linux,hardware
+
 // Test suite for mem module.
 package gpio_test
 
@@ -21,7 +24,21 @@ func TestOpen(t *testing.T) {
 func TestOpenOpened(t *testing.T) {
 	assert.Nil(t, gpio.Open())
 	defer gpio.Close()
-	assert.NotNil(t, gpio.Open())
+	assert.Nil(t, gpio.Open())
+	assert.NotNil(t, gpio.NewPin(gpio.J8p7))
+	gpio.Close()
+	// still open
+	assert.NotNil(t, gpio.NewPin(gpio.J8p7))
+}
+
+func TestCloseClosed(t *testing.T) {
+	assert.Nil(t, gpio.Open())
+	assert.Nil(t, gpio.Close())
+	assert.Nil(t, gpio.Close())
+	// unbalanced Close is ignored
+	assert.Nil(t, gpio.Open())
+	defer gpio.Close()
+	assert.NotNil(t, gpio.NewPin(gpio.J8p7))
 }
 
 func TestReOpen(t *testing.T) {
@@ -30,3 +47,50 @@ func TestReOpen(t *testing.T) {
 	assert.Nil(t, gpio.Open())
 	defer gpio.Close()
 }
+
+func TestStrictClose(t *testing.T) {
+	assert.Nil(t, gpio.Open(gpio.WithStrictClose()))
+	pin := gpio.NewPin(gpio.J8p7)
+	wt, err := pin.AddWatch(gpio.EdgeBoth, func(gpio.Event) {})
+	assert.Nil(t, err)
+	assert.Equal(t, gpio.ErrWatchesActive, gpio.Close())
+	// still open
+	assert.NotNil(t, gpio.NewPin(gpio.J8p7))
+	wt.Unwatch()
+
+	assert.Nil(t, pin.Watch(gpio.EdgeBoth, func(*gpio.Pin) {}))
+	assert.Equal(t, gpio.ErrWatchesActive, gpio.Close())
+	gpio.CloseWatcher()
+	assert.Nil(t, gpio.Close())
+	assert.Panics(t, func() {
+		gpio.NewPin(gpio.J8p7)
+	})
+
+	// strict close does not persist after the GPIO is closed
+	assert.Nil(t, gpio.Open())
+	_, err = gpio.NewPin(gpio.J8p7).AddWatch(gpio.EdgeBoth, func(gpio.Event) {})
+	assert.Nil(t, err)
+	assert.Nil(t, gpio.Close())
+}
+
+func TestStalePin(t *testing.T) {
+	assert.Nil(t, gpio.Open())
+	pin := gpio.NewPin(gpio.J8p7)
+	assert.False(t, pin.Stale())
+	assert.Nil(t, gpio.Close())
+	assert.True(t, pin.Stale())
+	assert.ErrorIs(t, pin.Revalidate(), gpio.ErrNotOpen)
+
+	assert.Nil(t, gpio.Open())
+	assert.True(t, pin.Stale())
+	assert.ErrorIs(t, pin.Request("test"), gpio.ErrNotOpen)
+	_, err := pin.AddWatch(gpio.EdgeBoth, func(gpio.Event) {})
+	assert.ErrorIs(t, err, gpio.ErrNotOpen)
+	assert.Nil(t, gpio.Close())
+
+	assert.Nil(t, gpio.Reopen(pin))
+	defer gpio.Close()
+	assert.False(t, pin.Stale())
+	assert.Nil(t, pin.Request("test"))
+	pin.Release()
+}
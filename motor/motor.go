@@ -0,0 +1,262 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package motor drives DC motors through H-bridges, such as the L298N, with
+// two direction pins and a PWM enable pin per motor.
+//
+// Speed is controlled by software PWM, played as a waveform on the enable pin,
+// so no PWM hardware is required.
+package motor
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/warthog618/gpio"
+)
+
+// State is the state of the H-bridge driving a motor.
+type State int
+
+const (
+	// Coast leaves the motor free running, with the bridge disabled.
+	Coast State = iota
+
+	// Forward drives the motor forward.
+	Forward
+
+	// Reverse drives the motor in reverse.
+	Reverse
+
+	// Brake shorts the motor terminals, stopping it quickly.
+	Brake
+)
+
+// Motor drives a DC motor through one channel of an H-bridge.
+type Motor struct {
+	in1      *gpio.Pin
+	in2      *gpio.Pin
+	en       *gpio.Pin
+	period   time.Duration
+	deadTime time.Duration
+
+	// Guards the following.
+	mu     sync.Mutex
+	state  State
+	speed  float64
+	wave   *gpio.Wave
+	closed bool
+}
+
+// Option modifies the configuration of a Motor.
+type Option func(*Motor)
+
+// WithPWMPeriod sets the period of the software PWM on the enable pin.
+//
+// The default is 1ms.
+func WithPWMPeriod(d time.Duration) Option {
+	return func(m *Motor) {
+		if d > 0 {
+			m.period = d
+		}
+	}
+}
+
+// WithDeadTime sets the time the motor is left coasting when switching
+// between forward and reverse.
+//
+// The default is 20ms.
+func WithDeadTime(d time.Duration) Option {
+	return func(m *Motor) {
+		if d >= 0 {
+			m.deadTime = d
+		}
+	}
+}
+
+// New creates a Motor driven by the direction pins, in1 and in2, and the
+// enable pin.
+//
+// The pins are set to outputs, with the motor coasting, and are requested
+// until the Motor is closed.
+// The GPIO must be open.
+func New(in1, in2, en int, options ...Option) (*Motor, error) {
+	m := &Motor{
+		in1:      gpio.NewPin(in1),
+		in2:      gpio.NewPin(in2),
+		en:       gpio.NewPin(en),
+		period:   time.Millisecond,
+		deadTime: 20 * time.Millisecond,
+	}
+	if m.in1 == nil || m.in2 == nil || m.en == nil {
+		return nil, ErrInvalidPin
+	}
+	if err := gpio.RequestPins("motor", m.in1, m.in2, m.en); err != nil {
+		return nil, err
+	}
+	for _, option := range options {
+		option(m)
+	}
+	for _, pin := range []*gpio.Pin{m.en, m.in1, m.in2} {
+		pin.Low()
+		pin.Output()
+	}
+	return m, nil
+}
+
+// Close leaves the motor coasting.
+//
+// The pins are left as outputs.
+func (m *Motor) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.coast()
+	gpio.ReleasePins(m.in1, m.in2, m.en)
+	m.closed = true
+}
+
+// Forward drives the motor forward at the speed, from 0 to 1.
+//
+// If the motor is being driven in reverse it is first left coasting for the
+// dead time.
+func (m *Motor) Forward(speed float64) {
+	m.drive(Forward, speed)
+}
+
+// Reverse drives the motor in reverse at the speed, from 0 to 1.
+//
+// If the motor is being driven forward it is first left coasting for the dead
+// time.
+func (m *Motor) Reverse(speed float64) {
+	m.drive(Reverse, speed)
+}
+
+// Brake stops the motor quickly by shorting its terminals.
+func (m *Motor) Brake() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return
+	}
+	m.coast()
+	m.en.High()
+	m.state = Brake
+}
+
+// Coast leaves the motor free running.
+func (m *Motor) Coast() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return
+	}
+	m.coast()
+}
+
+// SetSpeed sets the speed, from 0 to 1, in the current direction.
+//
+// This has no effect on a motor that is braking or coasting.
+func (m *Motor) SetSpeed(speed float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed || (m.state != Forward && m.state != Reverse) {
+		return
+	}
+	m.setSpeed(speed)
+}
+
+// State returns the state of the motor.
+func (m *Motor) State() State {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.state
+}
+
+// Speed returns the speed of the motor, from 0 to 1, when being driven, and 0
+// otherwise.
+func (m *Motor) Speed() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.speed
+}
+
+// drive drives the motor in the direction at the speed.
+func (m *Motor) drive(dir State, speed float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return
+	}
+	if m.state == dir {
+		m.setSpeed(speed)
+		return
+	}
+	reversing := m.state == Forward || m.state == Reverse
+	m.coast()
+	if reversing {
+		// let the motor wind down, so the bridge is not driving against the
+		// back EMF.
+		time.Sleep(m.deadTime)
+	}
+	// the enable is low, so the direction pins can be changed safely.
+	m.in1.Write(dir == Forward)
+	m.in2.Write(dir == Reverse)
+	m.state = dir
+	m.setSpeed(speed)
+}
+
+// coast disables the bridge, and then returns the direction pins to low.
+//
+// Assumes the caller holds the lock.
+func (m *Motor) coast() {
+	m.stopWave()
+	m.en.Low()
+	m.in1.Low()
+	m.in2.Low()
+	m.state = Coast
+	m.speed = 0
+}
+
+// setSpeed sets the duty cycle of the enable pin.
+//
+// Assumes the caller holds the lock.
+func (m *Motor) setSpeed(speed float64) {
+	if speed < 0 {
+		speed = 0
+	}
+	if speed > 1 {
+		speed = 1
+	}
+	m.stopWave()
+	m.speed = speed
+	high := time.Duration(float64(m.period) * speed)
+	switch {
+	case high <= 0:
+		m.en.Low()
+	case high >= m.period:
+		m.en.High()
+	default:
+		m.wave = m.en.PlayWave([]gpio.Step{
+			{Level: gpio.High, Duration: high},
+			{Level: gpio.Low, Duration: m.period - high},
+		}, 0)
+	}
+}
+
+// stopWave stops any PWM on the enable pin.
+//
+// Assumes the caller holds the lock.
+func (m *Motor) stopWave() {
+	if m.wave != nil {
+		m.wave.Stop()
+		m.wave = nil
+	}
+}
+
+var (
+	// ErrInvalidPin indicates a pin is not a valid GPIO pin.
+	ErrInvalidPin = errors.New("invalid pin")
+)
@@ -0,0 +1,115 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Human readable names for modes, pulls, levels and edges.
+
+package gpio
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var modeNames = [...]string{
+	Input:  "input",
+	Output: "output",
+	Alt0:   "alt0",
+	Alt1:   "alt1",
+	Alt2:   "alt2",
+	Alt3:   "alt3",
+	Alt4:   "alt4",
+	Alt5:   "alt5",
+}
+
+// String returns the name of the mode, such as "input" or "alt0".
+func (m Mode) String() string {
+	if m >= 0 && int(m) < len(modeNames) {
+		return modeNames[m]
+	}
+	return strconv.Itoa(int(m))
+}
+
+// ParseMode returns the mode with the name, as returned by String.
+//
+// The name is case insensitive.
+func ParseMode(name string) (Mode, error) {
+	s := strings.ToLower(name)
+	for m, n := range modeNames {
+		if n == s {
+			return Mode(m), nil
+		}
+	}
+	return Input, fmt.Errorf("invalid mode '%s'", name)
+}
+
+// String returns the name of the pull, "up", "down", "none" or "unknown".
+func (p Pull) String() string {
+	switch p {
+	case PullUp:
+		return "up"
+	case PullDown:
+		return "down"
+	case PullNone:
+		return "none"
+	case PullUnknown:
+		return "unknown"
+	}
+	return strconv.Itoa(int(p))
+}
+
+// ParsePull returns the pull with the name, "up", "down" or "none".
+//
+// The name is case insensitive.
+func ParsePull(name string) (Pull, error) {
+	switch strings.ToLower(name) {
+	case "up":
+		return PullUp, nil
+	case "down":
+		return PullDown, nil
+	case "none":
+		return PullNone, nil
+	}
+	return PullNone, fmt.Errorf("invalid pull '%s'", name)
+}
+
+// String returns the name of the level, "high" or "low".
+func (l Level) String() string {
+	if l {
+		return "high"
+	}
+	return "low"
+}
+
+// ParseLevel returns the level with the name.
+//
+// As well as "high" and "low", the names "hi", "true" and "1", and "lo",
+// "false" and "0", are accepted.  The name is case insensitive.
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToLower(name) {
+	case "high", "hi", "true", "1":
+		return High, nil
+	case "low", "lo", "false", "0":
+		return Low, nil
+	}
+	return Low, fmt.Errorf("invalid level '%s'", name)
+}
+
+// String returns the name of the edge, such as "rising".
+func (edge Edge) String() string {
+	return string(edge)
+}
+
+// ParseEdge returns the edge with the name, "rising", "falling", "both" or
+// "none".
+//
+// The name is case insensitive.
+func ParseEdge(name string) (Edge, error) {
+	switch e := Edge(strings.ToLower(name)); e {
+	case EdgeRising, EdgeFalling, EdgeBoth, EdgeNone:
+		return e, nil
+	}
+	return EdgeNone, fmt.Errorf("invalid edge '%s'", name)
+}
@@ -0,0 +1,78 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Test suite for names module.
+//
+// Tests do not use any pins.
+package gpio_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warthog618/gpio"
+)
+
+func TestModeNames(t *testing.T) {
+	modes := []gpio.Mode{gpio.Input, gpio.Output,
+		gpio.Alt0, gpio.Alt1, gpio.Alt2, gpio.Alt3, gpio.Alt4, gpio.Alt5}
+	for _, m := range modes {
+		v, err := gpio.ParseMode(m.String())
+		assert.Nil(t, err, m)
+		assert.Equal(t, m, v)
+	}
+	assert.Equal(t, "input", gpio.Input.String())
+	assert.Equal(t, "alt5", fmt.Sprint(gpio.Alt5))
+	assert.Equal(t, "8", gpio.Mode(8).String())
+	m, err := gpio.ParseMode("ALT0")
+	assert.Nil(t, err)
+	assert.Equal(t, gpio.Alt0, m)
+	_, err = gpio.ParseMode("alt6")
+	assert.NotNil(t, err)
+}
+
+func TestPullNames(t *testing.T) {
+	for _, p := range []gpio.Pull{gpio.PullUp, gpio.PullDown, gpio.PullNone} {
+		v, err := gpio.ParsePull(p.String())
+		assert.Nil(t, err, p)
+		assert.Equal(t, p, v)
+	}
+	assert.Equal(t, "up", gpio.PullUp.String())
+	assert.Equal(t, "unknown", gpio.PullUnknown.String())
+	_, err := gpio.ParsePull("unknown")
+	assert.NotNil(t, err)
+}
+
+func TestLevelNames(t *testing.T) {
+	assert.Equal(t, "high", gpio.High.String())
+	assert.Equal(t, "low", fmt.Sprint(gpio.Low))
+	for _, name := range []string{"high", "HI", "true", "1"} {
+		l, err := gpio.ParseLevel(name)
+		assert.Nil(t, err, name)
+		assert.Equal(t, gpio.High, l, name)
+	}
+	for _, name := range []string{"low", "Lo", "false", "0"} {
+		l, err := gpio.ParseLevel(name)
+		assert.Nil(t, err, name)
+		assert.Equal(t, gpio.Low, l, name)
+	}
+	_, err := gpio.ParseLevel("mid")
+	assert.NotNil(t, err)
+}
+
+func TestEdgeNames(t *testing.T) {
+	edges := []gpio.Edge{gpio.EdgeNone, gpio.EdgeRising, gpio.EdgeFalling, gpio.EdgeBoth}
+	for _, e := range edges {
+		v, err := gpio.ParseEdge(e.String())
+		assert.Nil(t, err, e)
+		assert.Equal(t, e, v)
+	}
+	e, err := gpio.ParseEdge("Rising")
+	assert.Nil(t, err)
+	assert.Equal(t, gpio.EdgeRising, e)
+	_, err = gpio.ParseEdge("edgy")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,98 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Pad control for DIO Pins.
+
+package gpio
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/warthog618/gpio/internal/periph"
+)
+
+// Pads control registers and bits.
+const (
+	padsGroup0 = 0x2c / 4
+	padsPasswd = 0x5a << 24
+	padsDrive  = 0x7
+	padsHyst   = 1 << 3
+	padsSlew   = 1 << 4
+)
+
+// SetDriveStrength sets the drive strength of the pin, in mA.
+//
+// The strength must be an even value from 2 to 16.
+//
+// The pads are controlled in groups, GPIO0-27, GPIO28-45 and GPIO46-53, so
+// this affects all the pins in the same group as the pin.
+// This requires root privileges.
+func (pin *Pin) SetDriveStrength(mA int) error {
+	if mA < 2 || mA > 16 || mA%2 != 0 {
+		return pinError("set drive strength", pin.pin, ErrInvalidDriveStrength)
+	}
+	return pin.updatePads(padsDrive, uint32(mA/2-1))
+}
+
+// SetHysteresis enables or disables the input hysteresis of the pin.
+//
+// The pads are controlled in groups, so this affects all the pins in the same
+// group as the pin.
+// This requires root privileges.
+func (pin *Pin) SetHysteresis(enable bool) error {
+	var v uint32
+	if enable {
+		v = padsHyst
+	}
+	return pin.updatePads(padsHyst, v)
+}
+
+// SetSlewLimited enables or disables limiting the output slew rate of the pin.
+//
+// The pads are controlled in groups, so this affects all the pins in the same
+// group as the pin.
+// This requires root privileges.
+func (pin *Pin) SetSlewLimited(limit bool) error {
+	var v uint32
+	if !limit {
+		v = padsSlew
+	}
+	return pin.updatePads(padsSlew, v)
+}
+
+// padslock covers read/modify/write access to the pads control registers.
+var padslock sync.Mutex
+
+// updatePads sets the masked bits of the pads control register for the pin's
+// group to the value.
+func (pin *Pin) updatePads(mask, value uint32) error {
+	pads, err := periph.Map(PadsOffset, 4096)
+	if err != nil {
+		return err
+	}
+	defer pads.Close()
+	padslock.Lock()
+	defer padslock.Unlock()
+	reg := padsGroup0 + padsGroup(pin.pin)
+	pads.Regs[reg] = padsPasswd | pads.Regs[reg]&^(mask|0xff000000) | value
+	return nil
+}
+
+// padsGroup returns the pads group containing the pin.
+func padsGroup(pin int) int {
+	switch {
+	case pin < 28:
+		return 0
+	case pin < 46:
+		return 1
+	}
+	return 2
+}
+
+var (
+	// ErrInvalidDriveStrength indicates the drive strength is not supported.
+	ErrInvalidDriveStrength = errors.New("invalid drive strength")
+)
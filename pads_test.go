@@ -0,0 +1,61 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && hardware
+// +build linux,hardware
+
+// Test suite for pads module.
+//
+// Tests use J8 pin 7, and require root privileges to access /dev/mem.
+package gpio_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warthog618/gpio"
+)
+
+func TestSetDriveStrengthInvalid(t *testing.T) {
+	setupDIO(t)
+	defer teardownDIO()
+	pin := gpio.NewPin(gpio.J8p7)
+	for _, mA := range []int{-2, 0, 1, 3, 17, 18} {
+		assert.ErrorIs(t, pin.SetDriveStrength(mA), gpio.ErrInvalidDriveStrength, mA)
+	}
+}
+
+func TestPads(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("requires root")
+	}
+	setupDIO(t)
+	defer teardownDIO()
+	pads, err := gpio.MapPeripheral(gpio.PadsOffset, 4096)
+	assert.Nil(t, err)
+	defer pads.Close()
+	// GPIO0-27
+	reg := 0x2c / 4
+	orig := pads.Reg(reg) & 0xff
+	defer pads.WriteReg(reg, 0x5a000000|orig)
+
+	pin := gpio.NewPin(gpio.J8p7)
+	assert.Nil(t, pin.SetDriveStrength(16))
+	assert.Equal(t, uint32(7), pads.Reg(reg)&0x7)
+	assert.Nil(t, pin.SetDriveStrength(2))
+	assert.Equal(t, uint32(0), pads.Reg(reg)&0x7)
+
+	assert.Nil(t, pin.SetHysteresis(false))
+	assert.Equal(t, uint32(0), pads.Reg(reg)&0x8)
+	assert.Nil(t, pin.SetHysteresis(true))
+	assert.Equal(t, uint32(0x8), pads.Reg(reg)&0x8)
+
+	assert.Nil(t, pin.SetSlewLimited(false))
+	assert.Equal(t, uint32(0x10), pads.Reg(reg)&0x10)
+	assert.Nil(t, pin.SetSlewLimited(true))
+	assert.Equal(t, uint32(0), pads.Reg(reg)&0x10)
+	assert.Equal(t, uint32(0), pads.Reg(reg)&0x7)
+}
@@ -0,0 +1,106 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package pcf8574 drives PCF8574 8-bit I2C I/O expanders, as used on the
+// common I2C backpacks for HD44780 LCDs.
+//
+// The expander pins are quasi-bidirectional - a pin written low is driven low,
+// and a pin written high is weakly pulled up, so may be read as an input.
+package pcf8574
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/warthog618/gpio"
+	"github.com/warthog618/gpio/i2c"
+)
+
+// DefaultAddr is the address of a PCF8574 with all its address pins high, as
+// on most LCD backpacks.  The PCF8574A variant has addresses 0x38 to 0x3f.
+const DefaultAddr = 0x27
+
+// PCF8574 is a PCF8574 on an I2C bus.
+type PCF8574 struct {
+	bus  *i2c.I2C
+	addr uint8
+
+	// Guards the following.
+	mu sync.Mutex
+	// the levels last written to the pins.
+	out byte
+}
+
+// New creates a PCF8574 at the address on the bus.
+//
+// The pins are initially assumed high, as at power on.
+func New(bus *i2c.I2C, addr uint8) *PCF8574 {
+	return &PCF8574{bus: bus, addr: addr, out: 0xff}
+}
+
+// Write sets the levels of all the pins, with pin 0 in the least significant
+// bit.
+func (p *PCF8574) Write(v byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.write(v)
+}
+
+// Read returns the levels of all the pins, with pin 0 in the least significant
+// bit.
+//
+// Only pins written high can be driven by external devices.
+func (p *PCF8574) Read() (byte, error) {
+	var v [1]byte
+	err := p.bus.Read(p.addr, v[:])
+	return v[0], err
+}
+
+// Shadow returns the levels last written to the pins.
+func (p *PCF8574) Shadow() byte {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.out
+}
+
+// WritePin sets the level of a single pin, from 0 to 7, leaving the others
+// unchanged.
+func (p *PCF8574) WritePin(pin int, level gpio.Level) error {
+	if pin < 0 || pin > 7 {
+		return ErrInvalidPin
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	v := p.out &^ (1 << uint(pin))
+	if level {
+		v |= 1 << uint(pin)
+	}
+	return p.write(v)
+}
+
+// ReadPin returns the level of a single pin, from 0 to 7.
+func (p *PCF8574) ReadPin(pin int) (gpio.Level, error) {
+	if pin < 0 || pin > 7 {
+		return gpio.Low, ErrInvalidPin
+	}
+	v, err := p.Read()
+	return v&(1<<uint(pin)) != 0, err
+}
+
+// write sets the levels of the pins.
+//
+// Assumes caller already holds the mu lock.
+func (p *PCF8574) write(v byte) error {
+	if err := p.bus.Write(p.addr, []byte{v}); err != nil {
+		return err
+	}
+	p.out = v
+	return nil
+}
+
+var (
+	// ErrInvalidPin indicates a pin is not in the range 0 to 7.
+	ErrInvalidPin = errors.New("invalid pin")
+)
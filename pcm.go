@@ -0,0 +1,234 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Bitstream output via the PCM/I2S peripheral.
+
+package gpio
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/warthog618/gpio/internal/periph"
+)
+
+// PCM registers and bits.
+const (
+	pcmOffset = 0x203000
+	pcmCS     = 0
+	pcmFIFO   = 1
+	pcmMODE   = 2
+	pcmTXC    = 4
+
+	pcmEN    = 1 << 0
+	pcmTXON  = 1 << 2
+	pcmTXCLR = 1 << 3
+	pcmTXERR = 1 << 15
+	pcmTXD   = 1 << 19
+	pcmTXE   = 1 << 21
+	pcmSYNC  = 1 << 24
+	pcmSTBY  = 1 << 25
+
+	// frame length of 32 clocks, with a single clock frame sync.
+	pcmFrame = 31<<10 | 1
+	// channel 1 enabled, 32 bits wide, at the start of the frame.
+	pcmTx32 = 1<<31 | 1<<30 | 8<<16
+
+	// the depth of the transmit FIFO, in words.
+	pcmFIFODepth = 64
+)
+
+// pcmPins are the pins carrying the PCM clock, frame sync and data out, all
+// selected by Alt0.
+var pcmPins = []int{GPIO18, GPIO19, GPIO21}
+
+// PCM clocks out bitstreams on PCM_DOUT (GPIO21), with the bit clock on
+// PCM_CLK (GPIO18) and a frame sync every 32 bits on PCM_FS (GPIO19).
+//
+// The bits are shifted out by the PCM peripheral, so the bitstream is free of
+// the jitter introduced by the Go scheduler and the kernel, as required for
+// driving DACs, or WS2812 LEDs by encoding each LED bit as several PCM bits.
+//
+// The PCM peripheral and its clock are accessed via /dev/mem, so root
+// privileges are required.  The PCM cannot be used while the kernel I2S
+// driver, or anything else, is using the peripheral.
+type PCM struct {
+	pins  []*Pin
+	modes []Mode
+	cm    *periph.Block
+	pcm   *periph.Block
+	rate  uint
+
+	// Guards the registers.
+	mu sync.Mutex
+}
+
+// NewPCM creates a PCM clocking out bits at the rate, in bits per second.
+//
+// The rate is generated by dividing a fixed clock source, so the actual rate
+// may differ from that requested and is returned by Rate.
+// The pins are requested until the PCM is closed.
+// Returns ErrInvalidFrequency if the rate cannot be generated, and
+// ErrProtected if any of the pins are protected.
+func NewPCM(rate uint) (*PCM, error) {
+	src, div, err := clockDivisor(rate)
+	if err != nil {
+		return nil, err
+	}
+	p := &PCM{}
+	for _, pin := range pcmPins {
+		if Protected(pin) {
+			return nil, pinError("pcm", pin, ErrProtected)
+		}
+		p.pins = append(p.pins, NewPin(pin))
+	}
+	if err = RequestPins("pcm", p.pins...); err != nil {
+		return nil, err
+	}
+	if p.cm, err = periph.Map(cmOffset, 4096); err != nil {
+		ReleasePins(p.pins...)
+		return nil, err
+	}
+	if p.pcm, err = periph.Map(pcmOffset, 4096); err != nil {
+		p.cm.Close()
+		ReleasePins(p.pins...)
+		return nil, err
+	}
+	cmStop(p.cm.Regs, cmPCMCTL)
+	cmStart(p.cm.Regs, cmPCMCTL, src, div)
+	p.rate = clockFrequency(src, div)
+
+	regs := p.pcm.Regs
+	regs[pcmCS] = pcmEN | pcmSTBY
+	regs[pcmMODE] = pcmFrame
+	regs[pcmTXC] = pcmTx32
+	p.clearTx()
+	for _, pin := range p.pins {
+		p.modes = append(p.modes, pin.Mode())
+		pin.SetMode(Alt0)
+	}
+	return p, nil
+}
+
+// Rate returns the actual rate of the bitstream, in bits per second.
+func (p *PCM) Rate() uint {
+	return p.rate
+}
+
+// Write clocks out the bits, most significant bit of each byte first, and
+// returns once the last bit has been clocked out.
+//
+// The bitstream is padded with zeros to a multiple of 32 bits, and the data
+// out is low between writes.
+// Returns ErrTimeout if the PCM stops accepting data, such as if its clock
+// has been stopped, and ErrUnderrun if the bitstream could not be supplied
+// fast enough, in which case zeros were inserted.
+// Returns ErrPCMClosed if the PCM has been closed.
+func (p *PCM) Write(data []byte) error {
+	words := pcmWords(data)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.pcm == nil {
+		return ErrPCMClosed
+	}
+	if len(words) == 0 {
+		return nil
+	}
+	regs := p.pcm.Regs
+	p.clearTx()
+	// prefill the FIFO, so the stream does not underrun on starting.
+	n := 0
+	for ; n < len(words) && n < pcmFIFODepth && regs[pcmCS]&pcmTXD != 0; n++ {
+		regs[pcmFIFO] = words[n]
+	}
+	regs[pcmCS] |= pcmTXON
+	// the time to clock out a full FIFO.
+	timeout := time.Duration(pcmFIFODepth*32)*time.Second/time.Duration(p.rate) + 10*time.Millisecond
+	deadline := time.Now().Add(timeout)
+	for n < len(words) {
+		if regs[pcmCS]&pcmTXD == 0 {
+			if time.Now().After(deadline) {
+				regs[pcmCS] &^= pcmTXON
+				return ErrTimeout
+			}
+			continue
+		}
+		regs[pcmFIFO] = words[n]
+		n++
+		deadline = time.Now().Add(timeout)
+	}
+	for regs[pcmCS]&pcmTXE == 0 {
+		if time.Now().After(deadline) {
+			regs[pcmCS] &^= pcmTXON
+			return ErrTimeout
+		}
+	}
+	// the last word is still being shifted out.
+	Delay(time.Duration(32) * time.Second / time.Duration(p.rate))
+	regs[pcmCS] &^= pcmTXON
+	// the FIFO always underruns after the final word, so the error is only
+	// meaningful if the FIFO had to be refilled.
+	if regs[pcmCS]&pcmTXERR != 0 && len(words) > pcmFIFODepth {
+		return ErrUnderrun
+	}
+	return nil
+}
+
+// clearTx stops transmission, clears the transmit FIFO and error, and waits
+// for the clear to take effect.
+//
+// The clear takes two PCM clocks, so is confirmed by waiting for the SYNC bit
+// to be echoed back.
+func (p *PCM) clearTx() {
+	regs := p.pcm.Regs
+	regs[pcmCS] = regs[pcmCS]&^pcmTXON | pcmTXCLR | pcmTXERR
+	sync := regs[pcmCS] & pcmSYNC
+	regs[pcmCS] ^= pcmSYNC
+	deadline := time.Now().Add(10 * time.Millisecond)
+	for regs[pcmCS]&pcmSYNC == sync && time.Now().Before(deadline) {
+	}
+}
+
+// Close stops the PCM and its clock, and restores the pins to their previous
+// modes.
+func (p *PCM) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.pcm == nil {
+		return nil
+	}
+	p.pcm.Regs[pcmCS] = 0
+	cmStop(p.cm.Regs, cmPCMCTL)
+	for i, pin := range p.pins {
+		pin.SetMode(p.modes[i])
+	}
+	ReleasePins(p.pins...)
+	err := p.pcm.Close()
+	if cerr := p.cm.Close(); err == nil {
+		err = cerr
+	}
+	p.pcm = nil
+	return err
+}
+
+// pcmWords packs the bitstream into FIFO words, most significant bit first,
+// padding the final word with zeros.
+func pcmWords(data []byte) []uint32 {
+	words := make([]uint32, (len(data)+3)/4)
+	for i, b := range data {
+		words[i/4] |= uint32(b) << uint(24-8*(i%4))
+	}
+	return words
+}
+
+var (
+	// ErrUnderrun indicates the bitstream could not be supplied to the PCM
+	// fast enough, so zeros were inserted into it.
+	ErrUnderrun = errors.New("underrun")
+
+	// ErrPCMClosed indicates the PCM has been closed.
+	ErrPCMClosed = errors.New("pcm closed")
+)
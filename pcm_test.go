@@ -0,0 +1,51 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && hardware
+// +build linux,hardware
+
+// Test suite for pcm module.
+//
+// Tests use J8 pins 12, 35 and 40, and require root privileges to access
+// /dev/mem.
+package gpio_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warthog618/gpio"
+)
+
+func TestNewPCMInvalid(t *testing.T) {
+	setupDIO(t)
+	defer teardownDIO()
+	p, err := gpio.NewPCM(0)
+	assert.Nil(t, p)
+	assert.Equal(t, gpio.ErrInvalidFrequency, err)
+}
+
+func TestPCM(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("requires root")
+	}
+	setupDIO(t)
+	defer teardownDIO()
+	pin := gpio.NewPin(gpio.GPIO21)
+	mode := pin.Mode()
+	p, err := gpio.NewPCM(2400000)
+	assert.Nil(t, err)
+	assert.Equal(t, gpio.Alt0, pin.Mode())
+	assert.InDelta(t, 2400000, p.Rate(), 1)
+	assert.ErrorIs(t, pin.Request("test"), gpio.ErrBusy)
+	assert.Nil(t, p.Write(make([]byte, 1024)))
+	assert.Nil(t, p.Write(nil))
+	assert.Nil(t, p.Close())
+	assert.Equal(t, mode, pin.Mode())
+	assert.Nil(t, pin.Request("test"))
+	pin.Release()
+	assert.Equal(t, gpio.ErrPCMClosed, p.Write([]byte{0xff}))
+}
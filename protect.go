@@ -0,0 +1,111 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Protection of system-critical pins.
+
+package gpio
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+var (
+	// protectedPins is a bitmap of the protected pins.
+	//
+	// By default this covers the HAT ID EEPROM (GPIO0 and GPIO1) and the UART
+	// console (GPIO14 and GPIO15).
+	protectedPins uint64 = 1<<0 | 1<<1 | 1<<GPIO14 | 1<<GPIO15
+
+	// dangerous is non-zero if protection is disabled.
+	dangerous int32
+)
+
+// sdPins are the pins connected to the eMMC on Compute Modules.
+var sdPins = []int{48, 49, 50, 51, 52, 53}
+
+// systemPins are the pins used by the HAT ID EEPROM (GPIO0 and GPIO1) and the
+// UART console (GPIO14 and GPIO15).
+var systemPins = []int{0, 1, GPIO14, GPIO15}
+
+// maxProtectedPin is the number of lines of the largest supported chipset, so
+// pins beyond the J8 header, such as the eMMC pins, can be protected.
+const maxProtectedPin = 58
+
+// Protect adds the pins to the set of protected pins.
+//
+// The pins used by the system, as per ProtectSystem, are protected by default.
+//
+// SetMode and Write silently ignore protected pins, other than logging the
+// attempt to the Logger, if any.  TrySetMode, TryWrite, and other functions
+// that return an error, such as ApplyConfig and NewClock, return
+// ErrProtected.
+func Protect(pins ...int) {
+	for {
+		old := atomic.LoadUint64(&protectedPins)
+		mask := old
+		for _, pin := range pins {
+			if pin >= 0 && pin < maxProtectedPin {
+				mask |= 1 << uint(pin)
+			}
+		}
+		if atomic.CompareAndSwapUint64(&protectedPins, old, mask) {
+			return
+		}
+	}
+}
+
+// Unprotect removes the pins from the set of protected pins.
+func Unprotect(pins ...int) {
+	for {
+		old := atomic.LoadUint64(&protectedPins)
+		mask := old
+		for _, pin := range pins {
+			if pin >= 0 && pin < maxProtectedPin {
+				mask &^= 1 << uint(pin)
+			}
+		}
+		if atomic.CompareAndSwapUint64(&protectedPins, old, mask) {
+			return
+		}
+	}
+}
+
+// AllowDangerous disables the protection of all pins, including the default
+// protection of the system pins, for the life of the process.
+func AllowDangerous() {
+	atomic.StoreInt32(&dangerous, 1)
+}
+
+// Protected returns true if the pin is protected.
+func Protected(pin int) bool {
+	if pin < 0 || pin >= maxProtectedPin || atomic.LoadInt32(&dangerous) != 0 {
+		return false
+	}
+	return atomic.LoadUint64(&protectedPins)&(1<<uint(pin)) != 0
+}
+
+// ProtectSystem protects the pins used by the system - the HAT ID EEPROM
+// (GPIO0 and GPIO1), the UART console (GPIO14 and GPIO15), and the eMMC
+// (GPIO48 to GPIO53) on Compute Modules.
+//
+// Those pins are protected by default, with the eMMC pins added by Open, so
+// this is only required to restore their protection after Unprotect.
+func ProtectSystem() {
+	Protect(systemPins...)
+	protectBoard()
+}
+
+// protectBoard adds the board specific pins to the protected pins.
+func protectBoard() {
+	if b, err := Board(); err == nil && b.Header == HeaderNone {
+		Protect(sdPins...)
+	}
+}
+
+var (
+	// ErrProtected indicates the pin is protected.
+	ErrProtected = errors.New("pin is protected")
+)
@@ -0,0 +1,68 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Test suite for protect module.
+//
+// Tests do not use any pins.
+package gpio
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProtect(t *testing.T) {
+	defer atomic.StoreUint64(&protectedPins, atomic.LoadUint64(&protectedPins))
+
+	// defaults
+	for _, pin := range []int{0, 1, GPIO14, GPIO15} {
+		assert.True(t, Protected(pin), pin)
+	}
+	for _, pin := range []int{GPIO4, 48, -1, maxProtectedPin} {
+		assert.False(t, Protected(pin), pin)
+	}
+
+	Unprotect(systemPins...)
+	for _, pin := range systemPins {
+		assert.False(t, Protected(pin), pin)
+	}
+
+	ProtectSystem()
+	assert.True(t, Protected(0))
+	assert.True(t, Protected(1))
+	assert.True(t, Protected(GPIO14))
+	assert.True(t, Protected(GPIO15))
+	assert.False(t, Protected(GPIO4))
+	assert.False(t, Protected(-1))
+	assert.False(t, Protected(maxProtectedPin))
+
+	Protect(GPIO4, maxProtectedPin, -1)
+	assert.True(t, Protected(GPIO4))
+	assert.False(t, Protected(maxProtectedPin))
+
+	// beyond the header
+	Protect(sdPins...)
+	for _, pin := range sdPins {
+		assert.True(t, Protected(pin), pin)
+	}
+	Unprotect(sdPins...)
+	assert.False(t, Protected(48))
+
+	Unprotect(GPIO4, GPIO14)
+	assert.False(t, Protected(GPIO4))
+	assert.False(t, Protected(GPIO14))
+	assert.True(t, Protected(GPIO15))
+}
+
+func TestAllowDangerous(t *testing.T) {
+	defer atomic.StoreInt32(&dangerous, 0)
+	defer atomic.StoreUint64(&protectedPins, atomic.LoadUint64(&protectedPins))
+	Protect(GPIO15)
+	assert.True(t, Protected(GPIO15))
+	AllowDangerous()
+	assert.False(t, Protected(GPIO15))
+}
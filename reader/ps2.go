@@ -0,0 +1,155 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package reader
+
+import (
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/warthog618/gpio"
+)
+
+// PS2 receives bytes, such as keyboard scan codes, from a PS/2 device.
+//
+// Each byte is sent in an 11 bit frame - a start bit, 8 data bits LSB first,
+// an odd parity bit and a stop bit - with the data sampled on the falling edge
+// of a 10 to 17kHz clock driven by the device.
+//
+// The clock is too fast for the interrupt latency of a Watcher, so the pins are
+// polled by a dedicated goroutine, locked to its own thread, that busy waits
+// until the PS2 is closed.
+type PS2 struct {
+	clock *gpio.Pin
+	data  *gpio.Pin
+	bytes chan byte
+
+	// accessed atomically.
+	errors uint64
+	stop   int32
+	// closed when the receiver exits.
+	doneCh chan struct{}
+}
+
+// ps2FrameTimeout is the clock period beyond which a partial frame is
+// discarded, as it is well in excess of the slowest PS/2 clock.
+const ps2FrameTimeout = time.Millisecond
+
+// NewPS2 creates a PS2 receiving from the clock and data pins, and starts
+// receiving.
+//
+// The pins are set to inputs, pulled up, and are requested until the PS2 is
+// closed.
+// The GPIO must be open.
+func NewPS2(clock, data int) (*PS2, error) {
+	p := &PS2{
+		clock:  gpio.NewPin(clock),
+		data:   gpio.NewPin(data),
+		bytes:  make(chan byte, 64),
+		doneCh: make(chan struct{}),
+	}
+	if p.clock == nil || p.data == nil {
+		return nil, ErrInvalidPin
+	}
+	if err := gpio.RequestPins("ps2", p.clock, p.data); err != nil {
+		return nil, err
+	}
+	for _, pin := range []*gpio.Pin{p.clock, p.data} {
+		pin.Input()
+		pin.PullUp()
+	}
+	go p.run()
+	return p, nil
+}
+
+// Data returns the channel of received bytes.
+//
+// The channel is closed when the PS2 is closed.
+func (p *PS2) Data() <-chan byte {
+	return p.bytes
+}
+
+// Errors returns the number of frames discarded due to framing or parity
+// errors, or because the channel was full.
+func (p *PS2) Errors() uint64 {
+	return atomic.LoadUint64(&p.errors)
+}
+
+// Close stops receiving and waits for the receiver to exit.
+func (p *PS2) Close() {
+	atomic.StoreInt32(&p.stop, 1)
+	<-p.doneCh
+	gpio.ReleasePins(p.clock, p.data)
+}
+
+func (p *PS2) run() {
+	defer close(p.doneCh)
+	defer close(p.bytes)
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	var frame uint16
+	n := 0
+	var last time.Time
+	prev := p.clock.Read()
+	for atomic.LoadInt32(&p.stop) == 0 {
+		level := p.clock.Read()
+		if level == prev {
+			continue
+		}
+		prev = level
+		if level == gpio.High {
+			continue
+		}
+		now := time.Now()
+		if n > 0 && now.Sub(last) > ps2FrameTimeout {
+			// resynchronise after a lost clock.
+			atomic.AddUint64(&p.errors, 1)
+			frame, n = 0, 0
+		}
+		last = now
+		if p.data.Read() {
+			frame |= 1 << uint(n)
+		}
+		n++
+		if n < 11 {
+			continue
+		}
+		if data, ok := decodePS2(frame); ok {
+			select {
+			case p.bytes <- data:
+			default:
+				atomic.AddUint64(&p.errors, 1)
+			}
+		} else {
+			atomic.AddUint64(&p.errors, 1)
+		}
+		frame, n = 0, 0
+	}
+}
+
+// decodePS2 returns the data in the frame, with the start bit in the least
+// significant bit, and false if the frame is invalid.
+func decodePS2(frame uint16) (byte, bool) {
+	data := byte(frame >> 1)
+	parity := frame&(1<<9) != 0
+	switch {
+	case frame&1 != 0, frame&(1<<10) == 0:
+		// bad start or stop bit.
+		return 0, false
+	case parity == (ones8(data)%2 == 1):
+		// parity is odd over the data and parity bit.
+		return 0, false
+	}
+	return data, true
+}
+
+func ones8(b byte) int {
+	n := 0
+	for ; b != 0; b &= b - 1 {
+		n++
+	}
+	return n
+}
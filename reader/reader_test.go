@@ -0,0 +1,72 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Test suite for reader module.
+//
+// Tests only decode frames, so can be run on any machine.
+package reader
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// bitsOf returns the n bits of raw, most significant bit first.
+func bitsOf(raw uint64, n int) []bool {
+	bits := make([]bool, n)
+	for i := range bits {
+		bits[i] = raw&(1<<uint(n-1-i)) != 0
+	}
+	return bits
+}
+
+func TestDecodeWiegand(t *testing.T) {
+	patterns := []struct {
+		name     string
+		raw      uint64
+		bits     int
+		expected WiegandFrame
+	}{
+		{"26", 0x2f623ae, 26, WiegandFrame{Bits: 26, Raw: 0x2f623ae, Facility: 123, Card: 4567, ParityOK: true}},
+		{"26 even parity", 0x0f623ae, 26, WiegandFrame{Bits: 26, Raw: 0x0f623ae, Facility: 123, Card: 4567}},
+		{"26 odd parity", 0x2f623af, 26, WiegandFrame{Bits: 26, Raw: 0x2f623af, Facility: 123, Card: 4567}},
+		{"26 data", 0x2f623be, 26, WiegandFrame{Bits: 26, Raw: 0x2f623be, Facility: 123, Card: 4575}},
+		{"34", 0x22469579b, 34, WiegandFrame{Bits: 34, Raw: 0x22469579b, Facility: 0x1234, Card: 0xabcd, ParityOK: true}},
+		{"34 even parity", 0x02469579b, 34, WiegandFrame{Bits: 34, Raw: 0x02469579b, Facility: 0x1234, Card: 0xabcd}},
+		{"34 odd parity", 0x22469579a, 34, WiegandFrame{Bits: 34, Raw: 0x22469579a, Facility: 0x1234, Card: 0xabcd}},
+		{"8", 0xa5, 8, WiegandFrame{Bits: 8, Raw: 0xa5}},
+		{"empty", 0, 0, WiegandFrame{}},
+	}
+	for _, p := range patterns {
+		t.Run(p.name, func(t *testing.T) {
+			f := decodeWiegand(bitsOf(p.raw, p.bits))
+			assert.Equal(t, p.expected, f)
+		})
+	}
+}
+
+func TestDecodePS2(t *testing.T) {
+	patterns := []struct {
+		name  string
+		frame uint16
+		data  byte
+		ok    bool
+	}{
+		{"data", 0x438, 0x1c, true},
+		{"zero", 0x600, 0x00, true},
+		{"ones", 0x7fe, 0xff, true},
+		{"parity", 0x638, 0, false},
+		{"start", 0x439, 0, false},
+		{"stop", 0x038, 0, false},
+	}
+	for _, p := range patterns {
+		t.Run(p.name, func(t *testing.T) {
+			data, ok := decodePS2(p.frame)
+			assert.Equal(t, p.ok, ok)
+			assert.Equal(t, p.data, data)
+		})
+	}
+}
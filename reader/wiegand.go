@@ -0,0 +1,221 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package reader decodes clock and data protocols received on a pair of pins,
+// such as the Wiegand protocol used by access control keypads and RFID
+// readers, and the PS/2 keyboard protocol.
+package reader
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/warthog618/gpio"
+)
+
+// WiegandFrame is a frame received from a Wiegand device.
+type WiegandFrame struct {
+	// The number of bits in the frame.
+	Bits int
+
+	// The bits of the frame, including any parity bits, with the last bit
+	// received in the least significant bit.
+	//
+	// Only the last 64 bits are retained for longer frames.
+	Raw uint64
+
+	// The facility code and card number, for 26 and 34 bit frames.
+	Facility uint32
+	Card     uint32
+
+	// true if the parity bits of a 26 or 34 bit frame are correct.
+	ParityOK bool
+}
+
+// Wiegand receives frames from a Wiegand device, which pulses its D0 line low
+// to send a 0 bit and its D1 line low to send a 1 bit.
+//
+// The pulses are detected by watching the pins, and ordered by their
+// timestamps, so the handling latency need only be less than the interval
+// between bits, typically 1 to 2ms.
+type Wiegand struct {
+	d0      *gpio.Pin
+	d1      *gpio.Pin
+	gap     time.Duration
+	watcher *gpio.Watcher
+	frames  chan WiegandFrame
+
+	// Guards the following.
+	mu     sync.Mutex
+	synced [2]bool
+	bits   []wiegandBit
+	timer  *time.Timer
+	closed bool
+}
+
+type wiegandBit struct {
+	time  time.Time
+	value bool
+}
+
+// WiegandOption modifies the configuration of a Wiegand.
+type WiegandOption func(*Wiegand)
+
+// WithGap sets the period without pulses that marks the end of a frame.
+//
+// The default is 25ms.
+func WithGap(d time.Duration) WiegandOption {
+	return func(w *Wiegand) {
+		w.gap = d
+	}
+}
+
+// frameQueueDepth is the number of frames buffered awaiting the reader.
+const frameQueueDepth = 16
+
+// NewWiegand creates a Wiegand receiving from the D0 and D1 pins.
+//
+// The pins are set to inputs, pulled up, and watched by a Watcher private to
+// the Wiegand, and are requested until the Wiegand is closed.
+// The GPIO must be open.
+func NewWiegand(d0, d1 int, options ...WiegandOption) (*Wiegand, error) {
+	w := &Wiegand{
+		d0:     gpio.NewPin(d0),
+		d1:     gpio.NewPin(d1),
+		gap:    25 * time.Millisecond,
+		frames: make(chan WiegandFrame, frameQueueDepth),
+	}
+	if w.d0 == nil || w.d1 == nil {
+		return nil, ErrInvalidPin
+	}
+	if err := gpio.RequestPins("wiegand", w.d0, w.d1); err != nil {
+		return nil, err
+	}
+	for _, option := range options {
+		option(w)
+	}
+	// serial dispatch ensures the initial event from each watch is delivered
+	// before any pulses.
+	watcher, err := gpio.NewWatcher(gpio.WithSerialDispatch())
+	if err != nil {
+		gpio.ReleasePins(w.d0, w.d1)
+		return nil, err
+	}
+	w.watcher = watcher
+	for i, pin := range []*gpio.Pin{w.d0, w.d1} {
+		pin.Input()
+		pin.PullUp()
+		i := i
+		if _, err = watcher.AddWatch(pin, gpio.EdgeFalling, func(evt gpio.Event) {
+			w.pulse(i, evt.Time)
+		}); err != nil {
+			watcher.Close()
+			gpio.ReleasePins(w.d0, w.d1)
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+// Frames returns the channel of received frames.
+//
+// Frames are discarded if the channel is full.
+func (w *Wiegand) Frames() <-chan WiegandFrame {
+	return w.frames
+}
+
+// Close stops receiving frames.
+func (w *Wiegand) Close() {
+	w.watcher.Close()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+}
+
+// pulse records a pulse on a data line.
+func (w *Wiegand) pulse(line int, t time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.synced[line] {
+		// ignore the initial event from the watch, which reports the idle
+		// level rather than a pulse.  The level of the events cannot be used
+		// to identify it, as a pulse may have ended before the level is read.
+		w.synced[line] = true
+		return
+	}
+	if w.closed {
+		return
+	}
+	w.bits = append(w.bits, wiegandBit{time: t, value: line == 1})
+	if w.timer == nil {
+		w.timer = time.AfterFunc(w.gap, w.endFrame)
+		return
+	}
+	w.timer.Reset(w.gap)
+}
+
+// endFrame decodes the frame once no pulses have been seen for the gap.
+func (w *Wiegand) endFrame() {
+	w.mu.Lock()
+	bits := w.bits
+	w.bits = nil
+	closed := w.closed
+	w.mu.Unlock()
+	if closed || len(bits) == 0 {
+		return
+	}
+	sort.Slice(bits, func(i, j int) bool { return bits[i].time.Before(bits[j].time) })
+	values := make([]bool, len(bits))
+	for i, b := range bits {
+		values[i] = b.value
+	}
+	select {
+	case w.frames <- decodeWiegand(values):
+	default:
+	}
+}
+
+// decodeWiegand decodes the bits of a frame.
+func decodeWiegand(bits []bool) WiegandFrame {
+	f := WiegandFrame{Bits: len(bits)}
+	for _, b := range bits {
+		f.Raw <<= 1
+		if b {
+			f.Raw |= 1
+		}
+	}
+	n := len(bits)
+	if n != 26 && n != 34 {
+		return f
+	}
+	// the leading parity bit is even parity over the first half of the data,
+	// and the trailing parity bit is odd parity over the second half.
+	half := (n - 2) / 2
+	f.ParityOK = ones(bits[:half+1])%2 == 0 && ones(bits[half+1:])%2 == 1
+	data := (f.Raw >> 1) & (1<<uint(n-2) - 1)
+	f.Card = uint32(data & 0xffff)
+	f.Facility = uint32(data >> 16)
+	return f
+}
+
+func ones(bits []bool) int {
+	n := 0
+	for _, b := range bits {
+		if b {
+			n++
+		}
+	}
+	return n
+}
+
+var (
+	// ErrInvalidPin indicates a pin is not a valid GPIO pin.
+	ErrInvalidPin = errors.New("invalid pin")
+)
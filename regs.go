@@ -0,0 +1,128 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Raw register access.
+
+package gpio
+
+import (
+	"sync"
+
+	"github.com/warthog618/gpio/internal/periph"
+)
+
+// Reg returns the value of the GPIO register at the offset.
+//
+// The offset is in 32-bit words from the start of the GPIO block, e.g. 13 for
+// GPLEV0.
+// Panics if the GPIO is not open or the offset is out of range.
+func Reg(offset int) uint32 {
+	return mem[offset]
+}
+
+// WriteReg sets the value of the GPIO register at the offset.
+//
+// This bypasses pin protection.
+// Panics if the GPIO is not open or the offset is out of range.
+func WriteReg(offset int, value uint32) {
+	memlock.Lock()
+	defer memlock.Unlock()
+	mem[offset] = value
+}
+
+// SetBits sets the bits in the mask in the GPIO register at the offset,
+// leaving the other bits unchanged.
+//
+// This bypasses pin protection.
+// Panics if the GPIO is not open or the offset is out of range.
+func SetBits(offset int, mask uint32) {
+	memlock.Lock()
+	defer memlock.Unlock()
+	mem[offset] |= mask
+}
+
+// ClearBits clears the bits in the mask in the GPIO register at the offset,
+// leaving the other bits unchanged.
+//
+// This bypasses pin protection.
+// Panics if the GPIO is not open or the offset is out of range.
+func ClearBits(offset int, mask uint32) {
+	memlock.Lock()
+	defer memlock.Unlock()
+	mem[offset] &^= mask
+}
+
+// Offsets of peripheral blocks from the peripheral base, for MapPeripheral.
+const (
+	PadsOffset  = 0x100000
+	ClockOffset = 0x101000
+	PCMOffset   = 0x203000
+	PWMOffset   = 0x20c000
+)
+
+// Peripheral provides raw access to the registers of a peripheral not covered
+// by /dev/gpiomem, such as the PCM, PWM or pads control.
+type Peripheral struct {
+	// The mu covers read/modify/write access to the block.
+	mu  sync.Mutex
+	blk *periph.Block
+}
+
+// MapPeripheral maps the registers at the offset from the peripheral base,
+// which is determined from the device-tree.
+//
+// The offset must be page aligned.
+// This requires root privileges.
+func MapPeripheral(offset, length int) (*Peripheral, error) {
+	blk, err := periph.Map(offset, length)
+	if err != nil {
+		return nil, err
+	}
+	return &Peripheral{blk: blk}, nil
+}
+
+// Close unmaps the registers.
+func (p *Peripheral) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.blk.Close()
+}
+
+// Reg returns the value of the register at the offset.
+//
+// The offset is in 32-bit words from the start of the mapped block.
+// Panics if the offset is out of range.
+func (p *Peripheral) Reg(offset int) uint32 {
+	return p.blk.Regs[offset]
+}
+
+// WriteReg sets the value of the register at the offset.
+//
+// Panics if the offset is out of range.
+func (p *Peripheral) WriteReg(offset int, value uint32) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.blk.Regs[offset] = value
+}
+
+// SetBits sets the bits in the mask in the register at the offset, leaving
+// the other bits unchanged.
+//
+// Panics if the offset is out of range.
+func (p *Peripheral) SetBits(offset int, mask uint32) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.blk.Regs[offset] |= mask
+}
+
+// ClearBits clears the bits in the mask in the register at the offset,
+// leaving the other bits unchanged.
+//
+// Panics if the offset is out of range.
+func (p *Peripheral) ClearBits(offset int, mask uint32) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.blk.Regs[offset] &^= mask
+}
@@ -0,0 +1,47 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && hardware
+// +build linux,hardware
+
+// Test suite for regs module.
+//
+// Tests use J8 pin 7.
+package gpio_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warthog618/gpio"
+)
+
+func TestReg(t *testing.T) {
+	setupDIO(t)
+	defer teardownDIO()
+	pin := gpio.NewPin(gpio.J8p7)
+	mask := uint32(1) << uint(gpio.J8p7)
+	// GPLEV0
+	assert.Equal(t, pin.Read() == gpio.High, gpio.Reg(13)&mask != 0)
+	assert.Panics(t, func() { gpio.Reg(-1) })
+}
+
+func TestSetClearBits(t *testing.T) {
+	setupDIO(t)
+	defer teardownDIO()
+	pin := gpio.NewPin(gpio.J8p7)
+	assert.Equal(t, gpio.Input, pin.Mode())
+	// GPFSEL0 - GPIO4 is bits 12-14
+	fsel := uint32(gpio.Output) << 12
+	gpio.SetBits(0, fsel)
+	assert.Equal(t, gpio.Output, pin.Mode())
+	gpio.ClearBits(0, fsel)
+	assert.Equal(t, gpio.Input, pin.Mode())
+	v := gpio.Reg(0)
+	gpio.WriteReg(0, v|fsel)
+	assert.Equal(t, gpio.Output, pin.Mode())
+	gpio.WriteReg(0, v)
+	assert.Equal(t, gpio.Input, pin.Mode())
+}
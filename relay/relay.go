@@ -0,0 +1,233 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package relay drives relays and solid-state outputs, with a logical on and
+// off independent of the active level of the output, minimum dwell times, and
+// interlocks that allow only one of a group of relays to be on at a time.
+package relay
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/warthog618/gpio"
+)
+
+// Relay is an output that is switched on and off.
+type Relay struct {
+	pin       *gpio.Pin
+	active    gpio.Level
+	minOn     time.Duration
+	minOff    time.Duration
+	interlock *Interlock
+
+	// Guards the following, and is shared by all relays in an interlock.
+	mu *sync.Mutex
+	on bool
+	// the time of the last switch, zero if never switched.
+	switched time.Time
+	closed   bool
+}
+
+// Interlock is a group of relays of which only one may be on at a time.
+type Interlock struct {
+	mu     sync.Mutex
+	relays []*Relay
+}
+
+// NewInterlock creates an empty Interlock.
+//
+// Relays are added to the interlock using the WithInterlock option.
+func NewInterlock() *Interlock {
+	return &Interlock{}
+}
+
+// On returns the relay in the interlock that is on, or nil if none are on.
+func (il *Interlock) On() *Relay {
+	il.mu.Lock()
+	defer il.mu.Unlock()
+	return il.on()
+}
+
+// on returns the relay in the interlock that is on.
+//
+// Assumes the caller holds the lock.
+func (il *Interlock) on() *Relay {
+	for _, r := range il.relays {
+		if r.on {
+			return r
+		}
+	}
+	return nil
+}
+
+// Option modifies the configuration of a Relay.
+type Option func(*Relay)
+
+// WithActiveLevel sets the level of the output that switches the relay on.
+//
+// The default is High.  Many relay modules are active low.
+func WithActiveLevel(level gpio.Level) Option {
+	return func(r *Relay) {
+		r.active = level
+	}
+}
+
+// WithMinOn sets the minimum time the relay must remain on before it can be
+// switched off.
+func WithMinOn(d time.Duration) Option {
+	return func(r *Relay) {
+		r.minOn = d
+	}
+}
+
+// WithMinOff sets the minimum time the relay must remain off before it can be
+// switched on again, such as to protect a compressor or motor.
+func WithMinOff(d time.Duration) Option {
+	return func(r *Relay) {
+		r.minOff = d
+	}
+}
+
+// WithInterlock adds the relay to the interlock, so it cannot be switched on
+// while another relay in the interlock is on.
+func WithInterlock(il *Interlock) Option {
+	return func(r *Relay) {
+		r.interlock = il
+	}
+}
+
+// New creates a Relay on the pin.
+//
+// The pin is set to an output, with the relay off, and is requested until the
+// relay is closed.
+// The GPIO must be open.
+func New(pin int, options ...Option) (*Relay, error) {
+	r := &Relay{
+		pin:    gpio.NewPin(pin),
+		active: gpio.High,
+	}
+	if r.pin == nil {
+		return nil, ErrInvalidPin
+	}
+	if err := r.pin.Request("relay"); err != nil {
+		return nil, err
+	}
+	for _, option := range options {
+		option(r)
+	}
+	r.pin.Write(!r.active)
+	r.pin.Output()
+	if r.interlock == nil {
+		r.mu = &sync.Mutex{}
+		return r, nil
+	}
+	r.mu = &r.interlock.mu
+	r.mu.Lock()
+	r.interlock.relays = append(r.interlock.relays, r)
+	r.mu.Unlock()
+	return r, nil
+}
+
+// Close switches the relay off, regardless of the minimum on time, and removes
+// it from any interlock.
+//
+// The pin is left as an output.
+func (r *Relay) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+	r.closed = true
+	r.pin.Write(!r.active)
+	r.pin.Release()
+	r.on = false
+	if r.interlock == nil {
+		return
+	}
+	relays := r.interlock.relays[:0:0]
+	for _, v := range r.interlock.relays {
+		if v != r {
+			relays = append(relays, v)
+		}
+	}
+	r.interlock.relays = relays
+}
+
+// On switches the relay on.
+//
+// Returns ErrInterlocked if another relay in the interlock is on, and ErrDwell
+// if the relay has not been off for the minimum off time.
+func (r *Relay) On() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return ErrClosed
+	}
+	if r.on {
+		return nil
+	}
+	if r.interlock != nil && r.interlock.on() != nil {
+		return ErrInterlocked
+	}
+	return r.set(true, r.minOff)
+}
+
+// Off switches the relay off.
+//
+// Returns ErrDwell if the relay has not been on for the minimum on time.
+func (r *Relay) Off() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return ErrClosed
+	}
+	if !r.on {
+		return nil
+	}
+	return r.set(false, r.minOn)
+}
+
+// IsOn returns true if the relay is on.
+func (r *Relay) IsOn() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.on
+}
+
+// set switches the relay if it has dwelt in its current state long enough.
+//
+// Assumes the caller holds the lock.
+func (r *Relay) set(on bool, dwell time.Duration) error {
+	now := time.Now()
+	if !r.switched.IsZero() && now.Sub(r.switched) < dwell {
+		return ErrDwell
+	}
+	level := r.active
+	if !on {
+		level = !level
+	}
+	r.pin.Write(level)
+	r.on = on
+	r.switched = now
+	return nil
+}
+
+var (
+	// ErrInvalidPin indicates the pin is not a valid GPIO pin.
+	ErrInvalidPin = errors.New("invalid pin")
+
+	// ErrInterlocked indicates another relay in the interlock is on.
+	ErrInterlocked = errors.New("interlocked")
+
+	// ErrDwell indicates the relay has not remained in its current state for
+	// the minimum time.
+	ErrDwell = errors.New("minimum dwell time not elapsed")
+
+	// ErrClosed indicates the relay has been closed.
+	ErrClosed = errors.New("relay closed")
+)
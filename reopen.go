@@ -0,0 +1,75 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Revalidation of pins across Close and Open.
+
+package gpio
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// openGen is incremented by the Close that releases the last reference to the
+// GPIO, so pins created before then can be identified as stale.
+var openGen uint32
+
+// Stale returns true if the GPIO has been closed since the pin was created or
+// last revalidated.
+//
+// The state cached in a stale pin, such as its shadow and pull, may no longer
+// reflect the hardware, and operations that return an error, such as
+// TryWrite, AddWatch and Request, return ErrNotOpen, while Read returns Low,
+// and SetMode, SetPull and Write have no effect.
+func (pin *Pin) Stale() bool {
+	return pin.gen != atomic.LoadUint32(&openGen)
+}
+
+// Revalidate refreshes the state cached in the pin from the hardware, so a
+// stale pin may be used again after the GPIO has been reopened.
+//
+// Returns ErrPinClosed if the pin has been closed, and ErrNotOpen if the GPIO
+// is not open.
+func (pin *Pin) Revalidate() error {
+	openlock.Lock()
+	defer openlock.Unlock()
+	if pin.Closed() {
+		return pinError("revalidate", pin.pin, ErrPinClosed)
+	}
+	if refs == 0 {
+		return pinError("revalidate", pin.pin, ErrNotOpen)
+	}
+	pin.shadow = pin.level()
+	pin.pull = PullUnknown
+	if chipset == BCM2711 {
+		pin.pull = pin.pull2711()
+	}
+	pin.gen = atomic.LoadUint32(&openGen)
+	if pin.tracked {
+		registerPin(pin)
+	}
+	return nil
+}
+
+// Reopen opens the GPIO, as per Open, and revalidates the pins, so long
+// running applications can continue to use their pins after reinitialising
+// the GPIO.
+func Reopen(pins ...*Pin) error {
+	if err := Open(); err != nil {
+		return err
+	}
+	for _, pin := range pins {
+		if err := pin.Revalidate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var (
+	// ErrNotOpen indicates the GPIO is not open, or the pin is stale as the
+	// GPIO has been closed since the pin was created.
+	ErrNotOpen = errors.New("gpio not open")
+)
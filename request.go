@@ -0,0 +1,163 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Exclusive ownership of pins within the process.
+
+package gpio
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Owner describes a pin that has been requested.
+type Owner struct {
+	// The BCM GPIO number of the pin.
+	Pin int
+
+	// The label provided by the requester.
+	Consumer string
+}
+
+type request struct {
+	pin      *Pin
+	consumer string
+}
+
+var (
+	// requestLock covers requests.
+	requestLock sync.Mutex
+	requests    = map[int]request{}
+)
+
+// Request claims exclusive ownership of the pin for the consumer, until it
+// is released by Release or Close.
+//
+// Ownership is advisory and only tracked within the process - the pin can
+// still be driven through other Pin objects, but any other Request for the
+// same pin fails with a *ConflictError, which matches ErrBusy, identifying
+// both consumers.
+// Requesting a pin already held by the same Pin object only updates the
+// consumer label.
+// Returns ErrProtected if the pin is protected, ErrNotOpen if the pin is
+// stale, and ErrPinClosed if the pin has been closed.
+func (pin *Pin) Request(consumer string) error {
+	requestLock.Lock()
+	defer requestLock.Unlock()
+	return pin.request(consumer)
+}
+
+// request claims the pin for the consumer.
+//
+// The requestLock must be held.
+func (pin *Pin) request(consumer string) error {
+	if err := pin.usable(); err != nil {
+		return pinError("request", pin.pin, err)
+	}
+	if Protected(pin.pin) {
+		return pinError("request", pin.pin, ErrProtected)
+	}
+	if r, ok := requests[pin.pin]; ok && r.pin != pin {
+		return pinError("request", pin.pin, &ConflictError{Owner: r.consumer, Consumer: consumer})
+	}
+	requests[pin.pin] = request{pin, consumer}
+	return nil
+}
+
+// RequestPins requests the pins for the consumer, as per Request.
+//
+// Either all the pins are requested, or, if any request fails, none are.
+// This allows drivers to claim all their pins when constructed, so drivers
+// that would otherwise share a pin fail with a *ConflictError rather than
+// corrupting each other's signals.
+func RequestPins(consumer string, pins ...*Pin) error {
+	requestLock.Lock()
+	defer requestLock.Unlock()
+	// the claims prior to this request, to be restored on failure.
+	prior := make(map[int]request, len(pins))
+	for _, pin := range pins {
+		if _, ok := prior[pin.pin]; !ok {
+			prior[pin.pin] = requests[pin.pin]
+		}
+		if err := pin.request(consumer); err != nil {
+			for p, r := range prior {
+				if r.pin == nil {
+					delete(requests, p)
+				} else {
+					requests[p] = r
+				}
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// ReleasePins releases the pins, as per Release.
+func ReleasePins(pins ...*Pin) {
+	for _, pin := range pins {
+		pin.Release()
+	}
+}
+
+// ConflictError indicates the pin could not be requested as it is already
+// owned by another consumer within the process.
+//
+// It matches ErrBusy when tested with errors.Is.
+type ConflictError struct {
+	// The label of the consumer that owns the pin.
+	Owner string
+
+	// The label of the consumer that requested the pin.
+	Consumer string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s by %q, requested by %q", ErrBusy, e.Owner, e.Consumer)
+}
+
+// Is returns true if target is ErrBusy.
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrBusy
+}
+
+// Release releases the ownership of the pin claimed by Request.
+//
+// Has no effect if the pin was not requested through this Pin object.
+func (pin *Pin) Release() {
+	requestLock.Lock()
+	defer requestLock.Unlock()
+	if r, ok := requests[pin.pin]; ok && r.pin == pin {
+		delete(requests, pin.pin)
+	}
+}
+
+// Consumer returns the label of the consumer that has requested the pin, or
+// an empty string if the pin has not been requested.
+func (pin *Pin) Consumer() string {
+	requestLock.Lock()
+	defer requestLock.Unlock()
+	return requests[pin.pin].consumer
+}
+
+// Requested returns the requested pins, in pin order.
+func Requested() []Owner {
+	requestLock.Lock()
+	defer requestLock.Unlock()
+	oo := make([]Owner, 0, len(requests))
+	for p, r := range requests {
+		oo = append(oo, Owner{Pin: p, Consumer: r.consumer})
+	}
+	sort.Slice(oo, func(i, j int) bool { return oo[i].Pin < oo[j].Pin })
+	return oo
+}
+
+// releaseAll releases all requested pins.
+func releaseAll() {
+	requestLock.Lock()
+	requests = map[int]request{}
+	requestLock.Unlock()
+}
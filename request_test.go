@@ -0,0 +1,64 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && hardware
+// +build linux,hardware
+
+// Test suite for request module.
+//
+// Tests use J8 pins 7 and 15.
+package gpio_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warthog618/gpio"
+)
+
+func TestRequest(t *testing.T) {
+	setupDIO(t)
+	defer teardownDIO()
+	p7 := gpio.NewPin(gpio.J8p7)
+	p15 := gpio.NewPin(gpio.J8p15)
+	assert.Equal(t, "", p7.Consumer())
+	assert.Empty(t, gpio.Requested())
+
+	assert.Nil(t, p15.Request("fan"))
+	assert.Nil(t, p7.Request("led"))
+	assert.Equal(t, "led", p7.Consumer())
+	assert.Equal(t, []gpio.Owner{
+		{Pin: gpio.J8p7, Consumer: "led"},
+		{Pin: gpio.J8p15, Consumer: "fan"},
+	}, gpio.Requested())
+
+	// relabel
+	assert.Nil(t, p7.Request("status"))
+	assert.Equal(t, "status", p7.Consumer())
+
+	// exclusive
+	other := gpio.NewPin(gpio.J8p7)
+	assert.ErrorIs(t, other.Request("button"), gpio.ErrBusy)
+	other.Release()
+	assert.Equal(t, "status", p7.Consumer())
+
+	p7.Release()
+	assert.Equal(t, "", p7.Consumer())
+	assert.Nil(t, other.Request("button"))
+	other.Release()
+
+	// protected by default
+	assert.ErrorIs(t, gpio.NewPin(gpio.GPIO14).Request("uart"), gpio.ErrProtected)
+}
+
+func TestRequestReleasedOnClose(t *testing.T) {
+	setupDIO(t)
+	assert.Nil(t, gpio.NewPin(gpio.J8p7).Request("led"))
+	teardownDIO()
+	setupDIO(t)
+	defer teardownDIO()
+	assert.Empty(t, gpio.Requested())
+	assert.Nil(t, gpio.NewPin(gpio.J8p7).Request("button"))
+}
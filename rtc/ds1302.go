@@ -0,0 +1,193 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtc
+
+import (
+	"sync"
+	"time"
+
+	"github.com/warthog618/gpio"
+)
+
+const (
+	// clock burst commands, reading or writing all the clock registers.
+	ds1302BurstRead  = 0xbf
+	ds1302BurstWrite = 0xbe
+	// write to the control register, which contains the write protect bit.
+	ds1302WriteControl = 0x8e
+	// the clock halt flag in the seconds register.
+	ds1302CH = 0x80
+)
+
+// DS1302 is a DS1302 real time clock on its 3-wire interface.
+//
+// The DS1302 keeps years from 2000 to 2099.
+type DS1302 struct {
+	ce   *gpio.Pin
+	sclk *gpio.Pin
+	io   *gpio.Pin
+	// time between clock edges (i.e. half the cycle time)
+	tclk time.Duration
+
+	// Guards the interface.
+	mu sync.Mutex
+}
+
+// NewDS1302 creates a DS1302 on the chip enable, clock and data pins.
+//
+// The chip enable and clock pins are set to outputs, and the data pin to an
+// input, except while writing.  The pins are requested until the DS1302 is
+// closed.
+// The GPIO must be open.
+func NewDS1302(ce, sclk, io int) (*DS1302, error) {
+	d := &DS1302{
+		ce:   gpio.NewPin(ce),
+		sclk: gpio.NewPin(sclk),
+		io:   gpio.NewPin(io),
+		// well within the 500kHz limit at 2V.
+		tclk: 2 * time.Microsecond,
+	}
+	if d.ce == nil || d.sclk == nil || d.io == nil {
+		return nil, ErrInvalidPin
+	}
+	if err := gpio.RequestPins("ds1302", d.ce, d.sclk, d.io); err != nil {
+		return nil, err
+	}
+	d.ce.Low()
+	d.ce.Output()
+	d.sclk.Low()
+	d.sclk.Output()
+	d.io.Input()
+	return d, nil
+}
+
+// Close returns the pins to inputs.
+func (d *DS1302) Close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.ce.Low()
+	d.ce.Input()
+	d.sclk.Input()
+	d.io.Input()
+	gpio.ReleasePins(d.ce, d.sclk, d.io)
+}
+
+// ReadTime returns the time kept by the clock.
+//
+// Returns ErrInvalidTime if the clock is halted, as it is until the time is
+// first set.
+func (d *DS1302) ReadTime() (time.Time, error) {
+	var regs [8]byte
+	d.mu.Lock()
+	d.begin()
+	d.writeCmd(ds1302BurstRead, true)
+	for i := range regs {
+		regs[i] = d.readByte()
+	}
+	d.end()
+	d.mu.Unlock()
+	if regs[0]&ds1302CH != 0 {
+		return time.Time{}, ErrInvalidTime
+	}
+	return date(2000+unbcd(regs[6]), unbcd(regs[4]&0x1f), unbcd(regs[3]&0x3f),
+		hour(regs[2]), unbcd(regs[1]&0x7f), unbcd(regs[0]&0x7f))
+}
+
+// SetTime sets the time kept by the clock, to the second, and starts the
+// clock if it is halted.
+//
+// The clock is set to 24 hour mode, and left write protected.
+func (d *DS1302) SetTime(t time.Time) error {
+	t = t.UTC()
+	year := t.Year() - 2000
+	if year < 0 || year > 99 {
+		return ErrInvalidTime
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	// clear the write protect bit.
+	d.begin()
+	d.writeCmd(ds1302WriteControl, false)
+	d.writeByte(0)
+	d.end()
+	d.begin()
+	d.writeCmd(ds1302BurstWrite, false)
+	for _, b := range []byte{
+		bcd(t.Second()),
+		bcd(t.Minute()),
+		bcd(t.Hour()),
+		bcd(t.Day()),
+		bcd(int(t.Month())),
+		byte(t.Weekday()) + 1,
+		bcd(year),
+		0x80, // control - write protect
+	} {
+		d.writeByte(b)
+	}
+	d.end()
+	return nil
+}
+
+// begin starts a transfer by raising chip enable.
+func (d *DS1302) begin() {
+	d.sclk.Low()
+	d.ce.High()
+	// the CE to clock setup time.
+	gpio.Delay(4 * time.Microsecond)
+}
+
+// end ends a transfer by dropping chip enable.
+func (d *DS1302) end() {
+	d.ce.Low()
+	d.io.Input()
+	// the CE inactive time.
+	gpio.Delay(4 * time.Microsecond)
+}
+
+// writeCmd clocks out a command byte.
+//
+// If read is true the data pin is released before the final falling clock
+// edge, on which the DS1302 drives the first bit of the data read.
+func (d *DS1302) writeCmd(cmd byte, read bool) {
+	d.io.Output()
+	for i := uint(0); i < 8; i++ {
+		d.io.Write(cmd&(1<<i) != 0)
+		gpio.Delay(d.tclk)
+		d.sclk.High() // DS1302 reads on the rising edge
+		gpio.Delay(d.tclk)
+		if read && i == 7 {
+			d.io.Input()
+		}
+		d.sclk.Low()
+	}
+}
+
+// writeByte clocks out a byte, LSB first.
+func (d *DS1302) writeByte(b byte) {
+	d.io.Output()
+	for i := uint(0); i < 8; i++ {
+		d.io.Write(b&(1<<i) != 0)
+		gpio.Delay(d.tclk)
+		d.sclk.High()
+		gpio.Delay(d.tclk)
+		d.sclk.Low()
+	}
+}
+
+// readByte clocks in a byte, LSB first.
+func (d *DS1302) readByte() byte {
+	var b byte
+	for i := uint(0); i < 8; i++ {
+		gpio.Delay(d.tclk)
+		if d.io.Read() {
+			b |= 1 << i
+		}
+		d.sclk.High()
+		gpio.Delay(d.tclk)
+		d.sclk.Low() // DS1302 writes on the falling edge
+	}
+	return b
+}
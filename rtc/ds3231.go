@@ -0,0 +1,92 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package rtc
+
+import (
+	"time"
+
+	"github.com/warthog618/gpio/i2c"
+)
+
+const (
+	// the I2C address of the DS3231.
+	ds3231Addr = 0x68
+	// the address of the status register.
+	ds3231Status = 0x0f
+	// the oscillator stop flag in the status register, set when the clock has
+	// stopped, e.g. due to a flat battery.
+	ds3231OSF = 0x80
+)
+
+// DS3231 is a DS3231 real time clock on an I2C bus.
+//
+// The DS3231 keeps years from 2000 to 2199.
+type DS3231 struct {
+	bus *i2c.I2C
+}
+
+// NewDS3231 creates a DS3231 on the bus.
+func NewDS3231(bus *i2c.I2C) *DS3231 {
+	return &DS3231{bus: bus}
+}
+
+// ReadTime returns the time kept by the clock.
+//
+// Returns ErrInvalidTime if the clock has stopped since the time was set.
+func (d *DS3231) ReadTime() (time.Time, error) {
+	var status [1]byte
+	if err := d.bus.Tx(ds3231Addr, []byte{ds3231Status}, status[:]); err != nil {
+		return time.Time{}, err
+	}
+	if status[0]&ds3231OSF != 0 {
+		return time.Time{}, ErrInvalidTime
+	}
+	var regs [7]byte
+	// registers from seconds, at address 0, to year.
+	if err := d.bus.Tx(ds3231Addr, []byte{0}, regs[:]); err != nil {
+		return time.Time{}, err
+	}
+	year := 2000 + unbcd(regs[6])
+	if regs[5]&0x80 != 0 {
+		// century
+		year += 100
+	}
+	return date(year, unbcd(regs[5]&0x1f), unbcd(regs[4]&0x3f),
+		hour(regs[2]), unbcd(regs[1]&0x7f), unbcd(regs[0]&0x7f))
+}
+
+// SetTime sets the time kept by the clock, to the second.
+//
+// The clock is set to 24 hour mode.
+func (d *DS3231) SetTime(t time.Time) error {
+	t = t.UTC()
+	year := t.Year() - 2000
+	if year < 0 || year > 199 {
+		return ErrInvalidTime
+	}
+	month := bcd(int(t.Month()))
+	if year > 99 {
+		month |= 0x80
+		year -= 100
+	}
+	if err := d.bus.Write(ds3231Addr, []byte{
+		0, // address of seconds
+		bcd(t.Second()),
+		bcd(t.Minute()),
+		bcd(t.Hour()),
+		byte(t.Weekday()) + 1,
+		bcd(t.Day()),
+		month,
+		bcd(year),
+	}); err != nil {
+		return err
+	}
+	var status [1]byte
+	if err := d.bus.Tx(ds3231Addr, []byte{ds3231Status}, status[:]); err != nil {
+		return err
+	}
+	return d.bus.Write(ds3231Addr, []byte{ds3231Status, status[0] &^ ds3231OSF})
+}
@@ -0,0 +1,86 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package rtc provides drivers for battery backed real time clocks, the
+// DS1302, over its 3-wire interface, and the DS3231, over I2C.
+//
+// The clocks are assumed to keep UTC, so the times read are in UTC, and times
+// set are converted to UTC.
+package rtc
+
+import (
+	"errors"
+	"time"
+)
+
+// Clock is a real time clock.
+type Clock interface {
+	// ReadTime returns the time kept by the clock.
+	ReadTime() (time.Time, error)
+
+	// SetTime sets the time kept by the clock, to the second.
+	SetTime(t time.Time) error
+}
+
+// SyncSystem sets the system time from the clock, as when booting without
+// network time.
+//
+// This requires root privileges.
+func SyncSystem(c Clock) error {
+	t, err := c.ReadTime()
+	if err != nil {
+		return err
+	}
+	return setSystemTime(t)
+}
+
+// bcd returns the binary coded decimal representation of a value from 0 to 99.
+func bcd(v int) byte {
+	return byte(v/10<<4 | v%10)
+}
+
+// unbcd returns the value of a binary coded decimal.
+func unbcd(b byte) int {
+	return int(b>>4)*10 + int(b&0x0f)
+}
+
+// hour returns the hour from a clock hours register, in either 12 or 24 hour
+// mode.
+func hour(b byte) int {
+	if b&0x40 == 0 {
+		// 24 hour mode.
+		return unbcd(b & 0x3f)
+	}
+	h := unbcd(b&0x1f) % 12
+	if b&0x20 != 0 {
+		// PM
+		h += 12
+	}
+	return h
+}
+
+// date returns the time from the clock registers, validating them.
+func date(year, month, day, h, m, s int) (time.Time, error) {
+	t := time.Date(year, time.Month(month), day, h, m, s, 0, time.UTC)
+	if t.Year() != year || int(t.Month()) != month || t.Day() != day ||
+		t.Hour() != h || t.Minute() != m || t.Second() != s {
+		// the clock has lost its time, e.g. due to a flat battery.
+		return time.Time{}, ErrInvalidTime
+	}
+	return t, nil
+}
+
+var (
+	// ErrInvalidTime indicates the time read from the clock is invalid, or the
+	// time being set is beyond the range of the clock.
+	ErrInvalidTime = errors.New("invalid time")
+
+	// ErrInvalidPin indicates a pin is not a valid GPIO pin.
+	ErrInvalidPin = errors.New("invalid pin")
+
+	// ErrNotSupported indicates the operation is not supported on the
+	// platform.
+	ErrNotSupported = errors.New("not supported")
+)
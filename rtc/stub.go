@@ -0,0 +1,17 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+// +build !linux
+
+package rtc
+
+import "time"
+
+// setSystemTime returns ErrNotSupported, as setting the system time is only
+// supported on Linux.
+func setSystemTime(t time.Time) error {
+	return ErrNotSupported
+}
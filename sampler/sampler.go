@@ -0,0 +1,356 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package sampler captures the levels of a set of pins at a fixed rate, as a
+// simple logic analyzer, by polling the GPIO level registers.
+//
+// Sampling is best effort - the sampler busy waits between samples on a
+// dedicated thread, but may still be delayed by the scheduler, so each sample
+// is timestamped.
+package sampler
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/warthog618/gpio"
+	"github.com/warthog618/gpio/capture"
+)
+
+// Sample is the levels of the pins at a point in time.
+type Sample struct {
+	// The time of the sample, relative to the start of the capture.
+	Time time.Duration
+
+	// The levels of all the pins, with GPIO0 in the least significant bit.
+	Levels uint64
+}
+
+// Level returns the level of the pin in the sample.
+func (s Sample) Level(pin int) gpio.Level {
+	return s.Levels&(1<<uint(pin)) != 0
+}
+
+// Sampler captures the levels of a set of pins into a ring buffer.
+type Sampler struct {
+	pins     []int
+	period   time.Duration
+	duration time.Duration
+	trigPin  int
+	trigEdge gpio.Edge
+	banks    int
+
+	// the ring buffer, which only holds the most recent samples if the
+	// capture overflows.
+	buf   []Sample
+	head  int
+	count int
+
+	// non-zero once the capture has been requested to stop.
+	stop int32
+
+	// Guards started.
+	mu      sync.Mutex
+	started bool
+
+	// closed when the capture completes.
+	doneCh chan struct{}
+}
+
+// Option modifies the configuration of a Sampler.
+type Option func(*Sampler)
+
+// WithRate sets the sample rate, in Hz.
+//
+// The default is 1MHz, which is best effort.
+func WithRate(hz float64) Option {
+	return func(s *Sampler) {
+		if hz > 0 {
+			s.period = time.Duration(float64(time.Second) / hz)
+		}
+	}
+}
+
+// WithDepth sets the number of samples held in the ring buffer.
+//
+// The default is 65536.
+func WithDepth(depth int) Option {
+	return func(s *Sampler) {
+		if depth > 0 {
+			s.buf = make([]Sample, depth)
+		}
+	}
+}
+
+// WithDuration stops the capture after the duration.
+//
+// By default the capture continues until Stop is called.
+func WithDuration(d time.Duration) Option {
+	return func(s *Sampler) {
+		s.duration = d
+	}
+}
+
+// WithTrigger delays the start of the capture until the edge is seen on the
+// pin.
+//
+// The pin need not be one of the pins being sampled.
+func WithTrigger(pin int, edge gpio.Edge) Option {
+	return func(s *Sampler) {
+		s.trigPin = pin
+		s.trigEdge = edge
+	}
+}
+
+// New creates a Sampler for the pins.
+//
+// The GPIO must be open.
+func New(pins []int, options ...Option) (*Sampler, error) {
+	if len(pins) == 0 {
+		return nil, ErrNoPins
+	}
+	s := &Sampler{
+		pins:     append([]int(nil), pins...),
+		period:   time.Microsecond,
+		trigEdge: gpio.EdgeNone,
+		doneCh:   make(chan struct{}),
+	}
+	for _, option := range options {
+		option(s)
+	}
+	if s.buf == nil {
+		s.buf = make([]Sample, 65536)
+	}
+	s.banks = 1
+	all := append([]int{s.trigPin}, s.pins...)
+	for _, pin := range all {
+		if gpio.NewPin(pin) == nil {
+			return nil, ErrInvalidPin
+		}
+		if pin >= 32 {
+			s.banks = 2
+		}
+	}
+	return s, nil
+}
+
+// Start starts the capture.
+//
+// Returns ErrStarted if the capture has already been started.
+func (s *Sampler) Start() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return ErrStarted
+	}
+	s.started = true
+	go s.run()
+	return nil
+}
+
+// Stop stops the capture and waits for it to complete.
+func (s *Sampler) Stop() {
+	atomic.StoreInt32(&s.stop, 1)
+	s.mu.Lock()
+	started := s.started
+	s.mu.Unlock()
+	if started {
+		<-s.doneCh
+	}
+}
+
+// Wait waits for the capture to complete.
+func (s *Sampler) Wait() {
+	<-s.doneCh
+}
+
+// Done returns a channel that is closed when the capture completes.
+func (s *Sampler) Done() <-chan struct{} {
+	return s.doneCh
+}
+
+// Samples returns the captured samples, oldest first.
+//
+// Returns nil if the capture has not completed.
+func (s *Sampler) Samples() []Sample {
+	select {
+	case <-s.doneCh:
+	default:
+		return nil
+	}
+	ss := make([]Sample, s.count)
+	first := s.head - s.count
+	if first < 0 {
+		first += len(s.buf)
+	}
+	for i := range ss {
+		ss[i] = s.buf[(first+i)%len(s.buf)]
+	}
+	return ss
+}
+
+func (s *Sampler) read() uint64 {
+	levels := uint64(gpio.ReadBank(0))
+	if s.banks > 1 {
+		levels |= uint64(gpio.ReadBank(1)) << 32
+	}
+	return levels
+}
+
+func (s *Sampler) stopped() bool {
+	return atomic.LoadInt32(&s.stop) != 0
+}
+
+func (s *Sampler) run() {
+	defer close(s.doneCh)
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	if !s.waitTrigger() {
+		return
+	}
+	start := time.Now()
+	next := start
+	for !s.stopped() {
+		now := time.Now()
+		for now.Before(next) {
+			now = time.Now()
+		}
+		s.buf[s.head] = Sample{Time: now.Sub(start), Levels: s.read()}
+		s.head = (s.head + 1) % len(s.buf)
+		if s.count < len(s.buf) {
+			s.count++
+		}
+		if s.duration > 0 && now.Sub(start) >= s.duration {
+			return
+		}
+		next = next.Add(s.period)
+		if next.Before(now) {
+			// overrun - resynchronise rather than trying to catch up.
+			next = now.Add(s.period)
+		}
+	}
+}
+
+// waitTrigger waits for the trigger edge, returning false if the capture is
+// stopped first.
+func (s *Sampler) waitTrigger() bool {
+	if s.trigEdge == gpio.EdgeNone {
+		return true
+	}
+	mask := uint64(1) << uint(s.trigPin)
+	prev := s.read() & mask
+	for !s.stopped() {
+		cur := s.read() & mask
+		if cur != prev {
+			switch s.trigEdge {
+			case gpio.EdgeBoth:
+				return true
+			case gpio.EdgeRising:
+				if cur != 0 {
+					return true
+				}
+			case gpio.EdgeFalling:
+				if cur == 0 {
+					return true
+				}
+			}
+		}
+		prev = cur
+	}
+	return false
+}
+
+// WriteCSV writes the captured samples to the writer as CSV, with a column
+// for the time, in nanoseconds, and for the level of each pin.
+func (s *Sampler) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	rec := make([]string, len(s.pins)+1)
+	rec[0] = "time"
+	for i, pin := range s.pins {
+		rec[i+1] = fmt.Sprintf("GPIO%d", pin)
+	}
+	cw.Write(rec)
+	for _, smp := range s.Samples() {
+		rec[0] = strconv.FormatInt(int64(smp.Time), 10)
+		for i, pin := range s.pins {
+			rec[i+1] = levelString(smp.Level(pin))
+		}
+		cw.Write(rec)
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Capture returns the captured samples as the changes in level of the
+// sampled pins.
+//
+// Returns nil if the capture has not completed.
+func (s *Sampler) Capture() *capture.Capture {
+	ss := s.Samples()
+	if ss == nil {
+		return nil
+	}
+	c := &capture.Capture{Pins: append([]int(nil), s.pins...)}
+	if len(ss) == 0 {
+		return c
+	}
+	c.Initial = ss[0].Levels
+	c.Length = ss[len(ss)-1].Time
+	prev := ss[0].Levels
+	for _, smp := range ss[1:] {
+		for _, pin := range s.pins {
+			if (smp.Levels^prev)&(1<<uint(pin)) != 0 {
+				c.Changes = append(c.Changes, capture.Change{Time: smp.Time, Pin: pin, Level: smp.Level(pin)})
+			}
+		}
+		prev = smp.Levels
+	}
+	return c
+}
+
+// WriteVCD writes the captured samples to the writer in Value Change Dump
+// format, as read by waveform viewers such as GTKWave and PulseView.
+func (s *Sampler) WriteVCD(w io.Writer) error {
+	c := s.Capture()
+	if c == nil {
+		c = &capture.Capture{Pins: s.pins}
+	}
+	return c.WriteVCD(w)
+}
+
+// WriteSigrok writes the captured samples to the writer as a sigrok session
+// file, at the sample rate of the Sampler.
+func (s *Sampler) WriteSigrok(w io.Writer) error {
+	c := s.Capture()
+	if c == nil {
+		c = &capture.Capture{Pins: s.pins}
+	}
+	return c.WriteSigrok(w, float64(time.Second)/float64(s.period))
+}
+
+func levelString(l gpio.Level) string {
+	if l {
+		return "1"
+	}
+	return "0"
+}
+
+var (
+	// ErrNoPins indicates no pins were provided to sample.
+	ErrNoPins = errors.New("no pins")
+
+	// ErrInvalidPin indicates a pin is not a valid GPIO pin.
+	ErrInvalidPin = errors.New("invalid pin")
+
+	// ErrStarted indicates the capture has already been started.
+	ErrStarted = errors.New("already started")
+)
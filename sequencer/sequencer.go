@@ -0,0 +1,180 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package sequencer steps a group of pins through a sequence of states, such
+// as an LED chaser, a stepper motor half-step table or a traffic light.
+package sequencer
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/warthog618/gpio"
+)
+
+// Sequencer writes a sequence of states to a PinGroup at a fixed rate.
+//
+// The sequence is repeated until the Sequencer is paused or stopped, or the
+// requested number of cycles is complete.
+type Sequencer struct {
+	group  *gpio.PinGroup
+	states []uint
+	cycles int
+
+	// Guards the following.
+	mu sync.Mutex
+	// the period between states.
+	period time.Duration
+	// the index of the next state to write.
+	index int
+	// the number of complete cycles through the states.
+	count int
+	// closed to stop the running goroutine.
+	stopCh chan struct{}
+	// closed when the running goroutine exits.
+	doneCh chan struct{}
+}
+
+// Option modifies the configuration of a Sequencer.
+type Option func(*Sequencer)
+
+// WithCycles sets the number of times the sequence is repeated before the
+// Sequencer stops.
+//
+// The default is 0, which repeats indefinitely.
+func WithCycles(n int) Option {
+	return func(s *Sequencer) {
+		s.cycles = n
+	}
+}
+
+// New creates a Sequencer that writes the states to the group, one state per
+// period.
+//
+// The Sequencer is initially stopped.  The pins in the group are not set to
+// outputs - that is left to the caller.
+//
+// Returns ErrInvalidPeriod if the period is not positive.
+func New(g *gpio.PinGroup, period time.Duration, states []uint, options ...Option) (*Sequencer, error) {
+	if period <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+	s := &Sequencer{
+		group:  g,
+		states: append([]uint(nil), states...),
+		period: period,
+	}
+	for _, option := range options {
+		option(s)
+	}
+	return s, nil
+}
+
+// Start starts, or resumes, stepping through the states.
+//
+// Has no effect if the Sequencer is already running.
+func (s *Sequencer) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopCh != nil || len(s.states) == 0 {
+		return
+	}
+	if s.cycles > 0 && s.count >= s.cycles {
+		s.count = 0
+	}
+	s.stopCh = make(chan struct{})
+	s.doneCh = make(chan struct{})
+	go s.run(s.stopCh, s.doneCh)
+}
+
+// Pause stops stepping through the states, leaving the pins in their current
+// state.
+//
+// A subsequent Start resumes from the next state.
+func (s *Sequencer) Pause() {
+	s.mu.Lock()
+	stopCh := s.stopCh
+	doneCh := s.doneCh
+	s.stopCh = nil
+	s.mu.Unlock()
+	if stopCh == nil {
+		return
+	}
+	close(stopCh)
+	<-doneCh
+}
+
+// Stop stops stepping through the states and resets the sequence so a
+// subsequent Start begins from the first state.
+func (s *Sequencer) Stop() {
+	s.Pause()
+	s.mu.Lock()
+	s.index = 0
+	s.count = 0
+	s.mu.Unlock()
+}
+
+// Running returns true if the Sequencer is stepping through the states.
+func (s *Sequencer) Running() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stopCh != nil
+}
+
+// SetPeriod sets the period between states.
+//
+// If the Sequencer is running, the change takes effect after the next state.
+// Returns ErrInvalidPeriod, and leaves the period unchanged, if the period is
+// not positive.
+func (s *Sequencer) SetPeriod(period time.Duration) error {
+	if period <= 0 {
+		return ErrInvalidPeriod
+	}
+	s.mu.Lock()
+	s.period = period
+	s.mu.Unlock()
+	return nil
+}
+
+// Index returns the index of the next state to be written.
+func (s *Sequencer) Index() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.index
+}
+
+func (s *Sequencer) run(stopCh <-chan struct{}, doneCh chan<- struct{}) {
+	defer close(doneCh)
+	next := time.Now()
+	for {
+		s.mu.Lock()
+		s.group.Write(s.states[s.index])
+		s.index++
+		if s.index >= len(s.states) {
+			s.index = 0
+			s.count++
+		}
+		done := s.cycles > 0 && s.count >= s.cycles
+		next = next.Add(s.period)
+		if done {
+			s.stopCh = nil
+		}
+		s.mu.Unlock()
+		if done {
+			return
+		}
+		select {
+		case <-time.After(time.Until(next)):
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+var (
+	// ErrInvalidPeriod indicates the period between states is not positive.
+	ErrInvalidPeriod = errors.New("invalid period")
+)
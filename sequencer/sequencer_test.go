@@ -0,0 +1,46 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Test suite for sequencer module.
+//
+// Tests only check the configuration, so can be run on any machine.
+package sequencer_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warthog618/gpio"
+	"github.com/warthog618/gpio/sequencer"
+)
+
+func TestNew(t *testing.T) {
+	// the group is not written until started, so need not be backed by pins.
+	g := &gpio.PinGroup{}
+	patterns := []struct {
+		name   string
+		period time.Duration
+		err    error
+	}{
+		{"negative", -time.Millisecond, sequencer.ErrInvalidPeriod},
+		{"zero", 0, sequencer.ErrInvalidPeriod},
+		{"positive", time.Millisecond, nil},
+	}
+	for _, p := range patterns {
+		t.Run(p.name, func(t *testing.T) {
+			s, err := sequencer.New(g, p.period, []uint{1, 2})
+			assert.Equal(t, p.err, err)
+			if p.err != nil {
+				assert.Nil(t, s)
+				return
+			}
+			assert.NotNil(t, s)
+			assert.Equal(t, sequencer.ErrInvalidPeriod, s.SetPeriod(0))
+			assert.Nil(t, s.SetPeriod(time.Second))
+			assert.False(t, s.Running())
+		})
+	}
+}
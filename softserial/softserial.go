@@ -0,0 +1,335 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package softserial provides a bit bashed asynchronous serial port, for use
+// when the hardware UARTs are unavailable.
+//
+// Transmission is played as a waveform, so is accurately timed.  Reception
+// polls the pin and is best effort - it is reliable at low baud rates, up to
+// around 9600, but may lose frames if the receiving thread is preempted.
+package softserial
+
+import (
+	"errors"
+	"io"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/warthog618/gpio"
+)
+
+// Parity is the parity bit added to each frame.
+type Parity int
+
+const (
+	// ParityNone indicates frames have no parity bit.
+	ParityNone Parity = iota
+
+	// ParityEven indicates the parity bit makes the number of set bits even.
+	ParityEven
+
+	// ParityOdd indicates the parity bit makes the number of set bits odd.
+	ParityOdd
+)
+
+// config is the framing common to transmitters and receivers.
+type config struct {
+	bit      time.Duration
+	dataBits int
+	parity   Parity
+	stopBits int
+}
+
+// Option modifies the framing of a Tx or Rx.
+type Option func(*config)
+
+// WithDataBits sets the number of data bits in each frame, from 5 to 8.
+//
+// The default is 8.
+func WithDataBits(n int) Option {
+	return func(c *config) {
+		if n >= 5 && n <= 8 {
+			c.dataBits = n
+		}
+	}
+}
+
+// WithParity sets the parity bit of each frame.
+//
+// The default is ParityNone.
+func WithParity(p Parity) Option {
+	return func(c *config) {
+		c.parity = p
+	}
+}
+
+// WithStopBits sets the number of stop bits in each frame, 1 or 2.
+//
+// The default is 1.
+func WithStopBits(n int) Option {
+	return func(c *config) {
+		if n == 1 || n == 2 {
+			c.stopBits = n
+		}
+	}
+}
+
+func newConfig(baud int, options []Option) (config, error) {
+	c := config{dataBits: 8, stopBits: 1}
+	if baud <= 0 {
+		return c, ErrInvalidBaud
+	}
+	c.bit = time.Second / time.Duration(baud)
+	for _, option := range options {
+		option(&c)
+	}
+	return c, nil
+}
+
+// parityBit returns the parity bit for the data.
+func (c config) parityBit(data byte) gpio.Level {
+	ones := 0
+	for i := 0; i < c.dataBits; i++ {
+		if data&(1<<uint(i)) != 0 {
+			ones++
+		}
+	}
+	return (ones%2 == 1) == (c.parity == ParityEven)
+}
+
+// Tx transmits serial data on a pin.
+type Tx struct {
+	config
+	pin *gpio.Pin
+
+	// Guards the pin.
+	mu sync.Mutex
+}
+
+// NewTx creates a Tx on the pin, at the baud rate.
+//
+// The pin is set to an output at the idle, high, level, and is requested
+// until the Tx is closed.
+// The GPIO must be open.
+func NewTx(pin, baud int, options ...Option) (*Tx, error) {
+	c, err := newConfig(baud, options)
+	if err != nil {
+		return nil, err
+	}
+	t := &Tx{config: c, pin: gpio.NewPin(pin)}
+	if t.pin == nil {
+		return nil, ErrInvalidPin
+	}
+	if err = t.pin.Request("softserial"); err != nil {
+		return nil, err
+	}
+	t.pin.High()
+	t.pin.Output()
+	return t, nil
+}
+
+// Write transmits the data, returning once it has been sent.
+func (t *Tx) Write(data []byte) (int, error) {
+	var steps []gpio.Step
+	for _, b := range data {
+		steps = t.appendFrame(steps, b)
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pin.PlayWave(steps, 1).Wait()
+	return len(data), nil
+}
+
+// Close returns the pin to an input.
+func (t *Tx) Close() {
+	t.mu.Lock()
+	t.pin.Input()
+	t.pin.Release()
+	t.mu.Unlock()
+}
+
+// appendFrame appends the waveform of the frame transmitting the data.
+func (t *Tx) appendFrame(steps []gpio.Step, data byte) []gpio.Step {
+	steps = appendBit(steps, gpio.Low, t.bit)
+	for i := 0; i < t.dataBits; i++ {
+		steps = appendBit(steps, data&(1<<uint(i)) != 0, t.bit)
+	}
+	if t.parity != ParityNone {
+		steps = appendBit(steps, t.parityBit(data), t.bit)
+	}
+	return appendBit(steps, gpio.High, time.Duration(t.stopBits)*t.bit)
+}
+
+// appendBit appends the bit to the waveform, merging it with the final step if
+// they have the same level.
+func appendBit(steps []gpio.Step, level gpio.Level, d time.Duration) []gpio.Step {
+	if n := len(steps); n > 0 && steps[n-1].Level == level {
+		steps[n-1].Duration += d
+		return steps
+	}
+	return append(steps, gpio.Step{Level: level, Duration: d})
+}
+
+// Rx receives serial data on a pin.
+type Rx struct {
+	config
+	pin *gpio.Pin
+
+	// received data.
+	data chan byte
+	// accessed atomically.
+	errors uint64
+	stop   int32
+	// closed when the receiver exits.
+	doneCh chan struct{}
+}
+
+// rxBufferSize is the number of received bytes buffered awaiting Read.
+const rxBufferSize = 256
+
+// NewRx creates an Rx on the pin, at the baud rate, and starts receiving.
+//
+// The pin is set to an input.  The pin is polled by a dedicated goroutine,
+// locked to its own thread, that busy waits until the Rx is closed.  The pin
+// is requested until then too.
+// The GPIO must be open.
+func NewRx(pin, baud int, options ...Option) (*Rx, error) {
+	c, err := newConfig(baud, options)
+	if err != nil {
+		return nil, err
+	}
+	r := &Rx{
+		config: c,
+		pin:    gpio.NewPin(pin),
+		data:   make(chan byte, rxBufferSize),
+		doneCh: make(chan struct{}),
+	}
+	if r.pin == nil {
+		return nil, ErrInvalidPin
+	}
+	if err = r.pin.Request("softserial"); err != nil {
+		return nil, err
+	}
+	r.pin.Input()
+	go r.run()
+	return r, nil
+}
+
+// Read reads received data, blocking until at least one byte is available.
+//
+// Returns io.EOF once the Rx is closed and the received data has been read.
+func (r *Rx) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	b, ok := <-r.data
+	if !ok {
+		return 0, io.EOF
+	}
+	p[0] = b
+	n := 1
+	for n < len(p) {
+		select {
+		case b, ok := <-r.data:
+			if !ok {
+				return n, nil
+			}
+			p[n] = b
+			n++
+		default:
+			return n, nil
+		}
+	}
+	return n, nil
+}
+
+// Errors returns the number of frames discarded due to framing or parity
+// errors, or because the receive buffer was full.
+func (r *Rx) Errors() uint64 {
+	return atomic.LoadUint64(&r.errors)
+}
+
+// Close stops receiving and waits for the receiver to exit.
+func (r *Rx) Close() {
+	atomic.StoreInt32(&r.stop, 1)
+	<-r.doneCh
+	r.pin.Release()
+}
+
+func (r *Rx) stopped() bool {
+	return atomic.LoadInt32(&r.stop) != 0
+}
+
+func (r *Rx) run() {
+	defer close(r.doneCh)
+	defer close(r.data)
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	prev := r.pin.Read()
+	for !r.stopped() {
+		level := r.pin.Read()
+		if level == prev {
+			continue
+		}
+		prev = level
+		if level == gpio.High {
+			continue
+		}
+		// falling edge - the start of a frame.
+		data, ok := r.frame(time.Now())
+		if !ok {
+			atomic.AddUint64(&r.errors, 1)
+			continue
+		}
+		select {
+		case r.data <- data:
+		default:
+			atomic.AddUint64(&r.errors, 1)
+		}
+		prev = gpio.High
+	}
+}
+
+// frame samples the bits of the frame that started at the time, in the middle
+// of each bit, returning false if the frame is invalid.
+func (r *Rx) frame(start time.Time) (byte, bool) {
+	sample := func(n int) gpio.Level {
+		deadline := start.Add(r.bit/2 + time.Duration(n)*r.bit)
+		for time.Now().Before(deadline) {
+		}
+		return r.pin.Read()
+	}
+	if sample(0) != gpio.Low {
+		// glitch rather than a start bit.
+		return 0, false
+	}
+	var data byte
+	for i := 0; i < r.dataBits; i++ {
+		if sample(i + 1) {
+			data |= 1 << uint(i)
+		}
+	}
+	n := r.dataBits + 1
+	if r.parity != ParityNone {
+		if sample(n) != r.parityBit(data) {
+			return 0, false
+		}
+		n++
+	}
+	if sample(n) != gpio.High {
+		return 0, false
+	}
+	return data, true
+}
+
+var (
+	// ErrInvalidBaud indicates the baud rate is not positive.
+	ErrInvalidBaud = errors.New("invalid baud rate")
+
+	// ErrInvalidPin indicates the pin is not a valid GPIO pin.
+	ErrInvalidPin = errors.New("invalid pin")
+)
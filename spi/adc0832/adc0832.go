@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/warthog618/gpio"
+	adcpkg "github.com/warthog618/gpio/adc"
 	"github.com/warthog618/gpio/spi"
 )
 
@@ -22,27 +23,85 @@ type ADC0832 struct {
 }
 
 // New creates a ADC0832.
-func New(tclk, tset time.Duration, clk, csz, di, do int) *ADC0832 {
-	return &ADC0832{*spi.New(tclk, clk, csz, di, do), tset}
+//
+// The data out pin is pulled up so a missing device, or a data out line stuck
+// low, can be detected.
+func New(tclk, tset time.Duration, clk, csz, di, do int, options ...spi.Option) *ADC0832 {
+	adc := &ADC0832{*spi.New(tclk, clk, csz, di, do, options...), tset}
+	adc.Miso.PullUp()
+	return adc
+}
+
+// Open creates a ADC0832, as per New, but returns an error if any of the
+// pins has already been requested by another driver.
+func Open(tclk, tset time.Duration, clk, csz, di, do int, options ...spi.Option) (*ADC0832, error) {
+	adc := &ADC0832{tset: tset}
+	if err := adc.Init(tclk, clk, csz, di, do, options...); err != nil {
+		return nil, err
+	}
+	adc.Miso.PullUp()
+	return adc, nil
+}
+
+// NewOnBus creates a ADC0832 with the chip select pin on a shared bus.
+func NewOnBus(bus *spi.Bus, tclk, tset time.Duration, csz int, options ...spi.Option) *ADC0832 {
+	adc := &ADC0832{*bus.Device(tclk, csz, options...), tset}
+	adc.Miso.PullUp()
+	return adc
+}
+
+// OpenOnBus creates a ADC0832 on a shared bus, as per NewOnBus, but returns
+// an error if the chip select pin has already been requested by another
+// driver.
+func OpenOnBus(bus *spi.Bus, tclk, tset time.Duration, csz int, options ...spi.Option) (*ADC0832, error) {
+	adc := &ADC0832{tset: tset}
+	if err := bus.InitDevice(&adc.SPI, tclk, csz, options...); err != nil {
+		return nil, err
+	}
+	adc.Miso.PullUp()
+	return adc, nil
+}
+
+// Bits returns the resolution of the ADC, 8 bits.
+func (adc *ADC0832) Bits() int {
+	return 8
+}
+
+// Channels returns the number of channels of the ADC, 2.
+func (adc *ADC0832) Channels() int {
+	return 2
 }
 
 // Read returns the value of a single channel read from the ADC.
-func (adc *ADC0832) Read(ch int) uint8 {
+//
+// Returns adc.ErrNoDevice if the ADC does not respond as expected.
+func (adc *ADC0832) Read(ch int) (uint16, error) {
 	return adc.read(ch, gpio.High)
 }
 
 // ReadDifferential returns the value of a differential pair read from the ADC.
-func (adc *ADC0832) ReadDifferential(ch int) uint8 {
+//
+// Channel 0 reads channel 0 relative to channel 1, and channel 1 the reverse.
+func (adc *ADC0832) ReadDifferential(ch int) (uint16, error) {
 	return adc.read(ch, gpio.Low)
 }
 
-func (adc *ADC0832) read(ch int, sgl gpio.Level) uint8 {
-	adc.Mu.Lock()
+func (adc *ADC0832) read(ch int, sgl gpio.Level) (uint16, error) {
+	if ch < 0 || ch >= adc.Channels() {
+		return 0, adcpkg.ErrInvalidChannel
+	}
+	adc.Lock()
+	defer adc.Unlock()
 	adc.Ssz.High()
 	adc.Sclk.Low()
+	adc.Delay(adc.Tclk)
+	if adc.Miso.Read() == gpio.Low {
+		// data out is tri-stated while deselected, so should be pulled up.
+		return 0, adcpkg.ErrNoDevice
+	}
 	adc.Mosi.High()
 	adc.Mosi.Output()
-	time.Sleep(adc.Tclk)
+	adc.Delay(adc.Tclk)
 	adc.Ssz.Low()
 
 	odd := gpio.Low
@@ -54,7 +113,12 @@ func (adc *ADC0832) read(ch int, sgl gpio.Level) uint8 {
 	adc.ClockOut(odd)       // ODD/Sign
 	// mux settling
 	adc.Mosi.Input()
-	time.Sleep(adc.tset)
+	adc.Delay(adc.tset)
+	if adc.Miso.Read() != gpio.Low {
+		// the device drives a leading zero during mux settling.
+		adc.Ssz.High()
+		return 0, adcpkg.ErrNoDevice
+	}
 	adc.Sclk.High()
 	// MSB first byte
 	var d uint8
@@ -67,6 +131,5 @@ func (adc *ADC0832) read(ch int, sgl gpio.Level) uint8 {
 	}
 	// ignore LSB bits - same as MSB just reversed order
 	adc.Ssz.High()
-	adc.Mu.Unlock()
-	return d
+	return uint16(d), nil
 }
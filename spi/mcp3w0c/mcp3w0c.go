@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/warthog618/gpio"
+	adcpkg "github.com/warthog618/gpio/adc"
 	"github.com/warthog618/gpio/spi"
 )
 
@@ -21,41 +22,141 @@ import (
 // The two data pins, di and do, may be tied and connected to a single GPIO pin.
 type MCP3w0c struct {
 	spi.SPI
-	width uint
+	width    uint
+	channels int
 }
 
 // New creates a MCP3w0c.
-func New(tclk time.Duration, clk, csz, di, do int, width uint) *MCP3w0c {
-	return &MCP3w0c{*spi.New(tclk, clk, csz, di, do), width}
+//
+// The device is assumed to have 8 channels.
+func New(tclk time.Duration, clk, csz, di, do int, width uint, options ...spi.Option) *MCP3w0c {
+	return pullUp(&MCP3w0c{*spi.New(tclk, clk, csz, di, do, options...), width, 8})
+}
+
+// Open creates a MCP3w0c, as per New, but returns an error if any of the
+// pins has already been requested by another driver.
+func Open(tclk time.Duration, clk, csz, di, do int, width uint, options ...spi.Option) (*MCP3w0c, error) {
+	adc := &MCP3w0c{width: width, channels: 8}
+	if err := adc.Init(tclk, clk, csz, di, do, options...); err != nil {
+		return nil, err
+	}
+	return pullUp(adc), nil
+}
+
+// NewMCP3004 creates a MCP3004.
+func NewMCP3004(tclk time.Duration, clk, csz, di, do int, options ...spi.Option) *MCP3w0c {
+	return pullUp(&MCP3w0c{*spi.New(tclk, clk, csz, di, do, options...), 10, 4})
 }
 
 // NewMCP3008 creates a MCP3008.
-func NewMCP3008(tclk time.Duration, clk, csz, di, do int) *MCP3w0c {
-	return &MCP3w0c{*spi.New(tclk, clk, csz, di, do), 10}
+func NewMCP3008(tclk time.Duration, clk, csz, di, do int, options ...spi.Option) *MCP3w0c {
+	return pullUp(&MCP3w0c{*spi.New(tclk, clk, csz, di, do, options...), 10, 8})
+}
+
+// NewMCP3204 creates a MCP3204.
+func NewMCP3204(tclk time.Duration, clk, csz, di, do int, options ...spi.Option) *MCP3w0c {
+	return pullUp(&MCP3w0c{*spi.New(tclk, clk, csz, di, do, options...), 12, 4})
 }
 
 // NewMCP3208 creates a MCP3208.
-func NewMCP3208(tclk time.Duration, clk, csz, di, do int) *MCP3w0c {
-	return &MCP3w0c{*spi.New(tclk, clk, csz, di, do), 12}
+func NewMCP3208(tclk time.Duration, clk, csz, di, do int, options ...spi.Option) *MCP3w0c {
+	return pullUp(&MCP3w0c{*spi.New(tclk, clk, csz, di, do, options...), 12, 8})
+}
+
+// NewOnBus creates a MCP3w0c with the chip select pin on a shared bus.
+func NewOnBus(bus *spi.Bus, tclk time.Duration, csz int, width uint, channels int, options ...spi.Option) *MCP3w0c {
+	return pullUp(&MCP3w0c{*bus.Device(tclk, csz, options...), width, channels})
+}
+
+// OpenOnBus creates a MCP3w0c on a shared bus, as per NewOnBus, but returns
+// an error if the chip select pin has already been requested by another
+// driver.
+func OpenOnBus(bus *spi.Bus, tclk time.Duration, csz int, width uint, channels int, options ...spi.Option) (*MCP3w0c, error) {
+	adc := &MCP3w0c{width: width, channels: channels}
+	if err := bus.InitDevice(&adc.SPI, tclk, csz, options...); err != nil {
+		return nil, err
+	}
+	return pullUp(adc), nil
+}
+
+// pullUp pulls up the data out pin so a missing device, or a data out line
+// stuck low, can be detected.
+func pullUp(adc *MCP3w0c) *MCP3w0c {
+	adc.Miso.PullUp()
+	return adc
+}
+
+// Bits returns the resolution of the ADC, 10 or 12 bits.
+func (adc *MCP3w0c) Bits() int {
+	return int(adc.width)
+}
+
+// Channels returns the number of channels of the ADC, 4 or 8.
+func (adc *MCP3w0c) Channels() int {
+	return adc.channels
 }
 
 // Read returns the value of a single channel read from the ADC.
-func (adc *MCP3w0c) Read(ch int) uint16 {
+//
+// Returns adc.ErrNoDevice if the ADC does not respond as expected.
+func (adc *MCP3w0c) Read(ch int) (uint16, error) {
 	return adc.read(ch, gpio.High)
 }
 
+// The differential pairs read by ReadDifferential, named for the positive
+// and negative inputs.
+//
+// The inputs are pseudo-differential, so the negative input must be within
+// 100mV of ground.  Only the first four pairs are available on 4 channel
+// devices.
+const (
+	CH0CH1 = iota
+	CH1CH0
+	CH2CH3
+	CH3CH2
+	CH4CH5
+	CH5CH4
+	CH6CH7
+	CH7CH6
+)
+
 // ReadDifferential returns the value of a differential pair read from the ADC.
-func (adc *MCP3w0c) ReadDifferential(ch int) uint16 {
-	return adc.read(ch, gpio.Low)
+//
+// The pair is one of the CHxCHy constants, and must be valid for the
+// number of channels of the device.
+func (adc *MCP3w0c) ReadDifferential(pair int) (uint16, error) {
+	return adc.read(pair, gpio.Low)
 }
 
-func (adc *MCP3w0c) read(ch int, sgl gpio.Level) uint16 {
-	adc.Mu.Lock()
+// ReadPair returns the value of the positive input relative to the negative
+// input read from the ADC.
+//
+// The inputs must be an adjacent pair, CH0 and CH1, CH2 and CH3 etc, in
+// either order.
+func (adc *MCP3w0c) ReadPair(pos, neg int) (uint16, error) {
+	if pos < 0 || pos^1 != neg {
+		return 0, adcpkg.ErrInvalidChannel
+	}
+	// the pairs are numbered by their positive input.
+	return adc.read(pos, gpio.Low)
+}
+
+func (adc *MCP3w0c) read(ch int, sgl gpio.Level) (uint16, error) {
+	if ch < 0 || ch >= adc.channels {
+		return 0, adcpkg.ErrInvalidChannel
+	}
+	adc.Lock()
+	defer adc.Unlock()
 	adc.Ssz.High()
 	adc.Sclk.Low()
+	adc.Delay(adc.Tclk)
+	if adc.Miso.Read() == gpio.Low {
+		// data out is tri-stated while deselected, so should be pulled up.
+		return 0, adcpkg.ErrNoDevice
+	}
 	adc.Mosi.High()
 	adc.Mosi.Output()
-	time.Sleep(adc.Tclk)
+	adc.Delay(adc.Tclk)
 	adc.Ssz.Low()
 
 	adc.ClockOut(gpio.High) // Start
@@ -69,9 +170,13 @@ func (adc *MCP3w0c) read(ch int, sgl gpio.Level) uint16 {
 	}
 	// mux settling
 	adc.Mosi.Input()
-	time.Sleep(adc.Tclk)
+	adc.Delay(adc.Tclk)
 	adc.Sclk.High()
-	adc.ClockIn() // null bit
+	if adc.ClockIn() != gpio.Low {
+		// the null bit is driven low by the device.
+		adc.Ssz.High()
+		return 0, adcpkg.ErrNoDevice
+	}
 	var d uint16
 	for i := uint(0); i < adc.width; i++ {
 		d = d << 1
@@ -80,6 +185,5 @@ func (adc *MCP3w0c) read(ch int, sgl gpio.Level) uint16 {
 		}
 	}
 	adc.Ssz.High()
-	adc.Mu.Unlock()
-	return d
+	return d, nil
 }
@@ -0,0 +1,174 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package spi
+
+import (
+	"runtime"
+	"sync/atomic"
+
+	"github.com/warthog618/gpio"
+)
+
+// Slave emulates an SPI device, clocking bits in on Mosi and out on Miso in
+// response to the clock and slave select driven by an external master.
+//
+// Data is transferred MSB first, a byte at a time.  The pins are polled by a
+// dedicated goroutine, locked to its own thread, that busy waits for the
+// whole life of the Slave, so the master clock must be slow enough for the
+// polling to keep up - typically no more than a few hundred kHz.
+type Slave struct {
+	sclk *gpio.Pin
+	ssz  *gpio.Pin
+	mosi *gpio.Pin
+	miso *gpio.Pin
+	// the idle level of the clock.
+	cpol gpio.Level
+	// true if data is sampled on the trailing edge of the clock.
+	cpha    bool
+	handler func(rx []byte) byte
+
+	// non-zero once the Slave has been requested to stop.
+	stop int32
+	// closed when the polling goroutine exits.
+	doneCh chan struct{}
+}
+
+// SlaveOption modifies the configuration of a Slave.
+type SlaveOption func(*Slave)
+
+// WithMode sets the SPI mode, from 0 to 3, which determines the idle level of
+// the clock and the edge on which data is sampled.
+//
+// The default is mode 0 - the clock idles low and data is sampled on the
+// rising edge, as per the SPI master.
+func WithMode(mode int) SlaveOption {
+	return func(s *Slave) {
+		s.cpol = mode&2 != 0
+		s.cpha = mode&1 != 0
+	}
+}
+
+// NewSlave creates a Slave and starts responding to the master.
+//
+// The handler is called at the start of each transaction, with no data, and
+// after each byte is received, with the data received so far in the
+// transaction, and returns the next byte to send to the master.
+//
+// The Sclk, Ssz and Mosi pins are set to inputs, and the Miso pin is only
+// driven while the Slave is selected.  The pins are requested, where
+// available, until the Slave is closed.  Pins already requested by other
+// drivers are skipped - use OpenSlave to detect such conflicts.
+func NewSlave(sclk, ssz, mosi, miso int, handler func(rx []byte) byte, options ...SlaveOption) *Slave {
+	s := newSlave(sclk, ssz, mosi, miso, handler, options...)
+	request(s.sclk, s.ssz, s.mosi, s.miso)
+	s.start()
+	return s
+}
+
+// OpenSlave creates a Slave, as per NewSlave, but returns an error if any of
+// the pins has already been requested by another driver.
+//
+// The error wraps a *gpio.ConflictError identifying both drivers.
+func OpenSlave(sclk, ssz, mosi, miso int, handler func(rx []byte) byte, options ...SlaveOption) (*Slave, error) {
+	s := newSlave(sclk, ssz, mosi, miso, handler, options...)
+	if err := requestPins(s.sclk, s.ssz, s.mosi, s.miso); err != nil {
+		return nil, err
+	}
+	s.start()
+	return s, nil
+}
+
+func newSlave(sclk, ssz, mosi, miso int, handler func(rx []byte) byte, options ...SlaveOption) *Slave {
+	s := &Slave{
+		sclk:    gpio.NewPin(sclk),
+		ssz:     gpio.NewPin(ssz),
+		mosi:    gpio.NewPin(mosi),
+		miso:    gpio.NewPin(miso),
+		handler: handler,
+		doneCh:  make(chan struct{}),
+	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// start sets the pins to inputs and starts polling them.
+func (s *Slave) start() {
+	s.sclk.Input()
+	s.ssz.Input()
+	s.mosi.Input()
+	s.miso.Input()
+	go s.run()
+}
+
+// Close stops responding to the master and waits for the polling goroutine to
+// exit.
+func (s *Slave) Close() {
+	atomic.StoreInt32(&s.stop, 1)
+	<-s.doneCh
+	gpio.ReleasePins(s.sclk, s.ssz, s.mosi, s.miso)
+}
+
+func (s *Slave) stopped() bool {
+	return atomic.LoadInt32(&s.stop) != 0
+}
+
+func (s *Slave) run() {
+	defer close(s.doneCh)
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	for !s.stopped() {
+		if s.ssz.Read() == gpio.Low {
+			s.transaction()
+		}
+	}
+}
+
+// transaction exchanges data with the master while the Slave is selected.
+func (s *Slave) transaction() {
+	var rx []byte
+	var tx, cur byte
+	nIn, nOut := 0, 0
+	shiftOut := func() {
+		if nOut%8 == 0 {
+			tx = s.handler(rx)
+		}
+		s.miso.Write(tx&(0x80>>uint(nOut%8)) != 0)
+		nOut++
+	}
+	sample := func() {
+		cur <<= 1
+		if s.mosi.Read() {
+			cur |= 1
+		}
+		nIn++
+		if nIn%8 == 0 {
+			rx = append(rx, cur)
+			cur = 0
+		}
+	}
+	if !s.cpha {
+		// the first bit must be ready before the first edge.
+		shiftOut()
+	}
+	s.miso.Output()
+	defer s.miso.Input()
+	clk := s.cpol
+	for !s.stopped() && s.ssz.Read() == gpio.Low {
+		level := s.sclk.Read()
+		if level == clk {
+			continue
+		}
+		clk = level
+		leading := level != s.cpol
+		if leading != s.cpha {
+			sample()
+		} else {
+			shiftOut()
+		}
+	}
+}
@@ -20,48 +20,269 @@ import (
 // interfaces using GPIO pins. It is not related to the SPI device drivers
 // provided by Linux.
 type SPI struct {
+	// Guards the SPI.
+	//
+	// Devices on a Bus are guarded by a lock shared by the bus instead, so
+	// drivers should use Lock and Unlock rather than Mu directly.
 	Mu sync.Mutex
 	// time between clock edges (i.e. half the cycle time)
 	Tclk time.Duration
-	Sclk *gpio.Pin
-	Ssz  *gpio.Pin
-	Mosi *gpio.Pin
-	Miso *gpio.Pin
+	// waits between clock edges, and for any other device timing.
+	Delay func(time.Duration)
+	Sclk  *gpio.Pin
+	Ssz   *gpio.Pin
+	Mosi  *gpio.Pin
+	Miso  *gpio.Pin
+	// the bus shared with other devices, if any.
+	bus *Bus
+}
+
+// Option modifies the configuration of a SPI.
+type Option func(*SPI)
+
+// WithDelay sets the function used to wait between clock edges.
+//
+// The default is gpio.Delay, which busy waits for short delays.
+// Alternatives are time.Sleep, which uses less CPU but has a granularity of
+// around 100µs, and NoDelay, which runs the bus as fast as the pins can be
+// driven.
+func WithDelay(delay func(time.Duration)) Option {
+	return func(spi *SPI) {
+		spi.Delay = delay
+	}
+}
+
+// NoDelay does not wait at all.
+func NoDelay(time.Duration) {
 }
 
 // New creates a SPI.
-func New(tclk time.Duration, sclk, ssz, mosi, miso int) *SPI {
-	spi := &SPI{
-		Tclk: tclk,
-		Sclk: gpio.NewPin(sclk),
-		Ssz:  gpio.NewPin(ssz),
-		Mosi: gpio.NewPin(mosi),
-		Miso: gpio.NewPin(miso),
+//
+// The pins are requested, where available, until the SPI is closed, so
+// drivers created later that use the pins fail with gpio.ErrBusy.
+// Pins already requested by other drivers are skipped - use Open to detect
+// such conflicts.
+func New(tclk time.Duration, sclk, ssz, mosi, miso int, options ...Option) *SPI {
+	spi := &SPI{}
+	spi.init(tclk, sclk, ssz, mosi, miso, options...)
+	request(spi.Sclk, spi.Ssz, spi.Mosi, spi.Miso)
+	spi.reset()
+	return spi
+}
+
+// Open creates a SPI, as per New, but returns an error if any of the pins
+// has already been requested by another driver.
+//
+// The error wraps a *gpio.ConflictError identifying both drivers, and none of
+// the pins are requested or changed.
+func Open(tclk time.Duration, sclk, ssz, mosi, miso int, options ...Option) (*SPI, error) {
+	spi := &SPI{}
+	if err := spi.Init(tclk, sclk, ssz, mosi, miso, options...); err != nil {
+		return nil, err
+	}
+	return spi, nil
+}
+
+// Init initialises a zero SPI, as per Open, so drivers can embed the SPI by
+// value.
+func (spi *SPI) Init(tclk time.Duration, sclk, ssz, mosi, miso int, options ...Option) error {
+	spi.init(tclk, sclk, ssz, mosi, miso, options...)
+	if err := requestPins(spi.Sclk, spi.Ssz, spi.Mosi, spi.Miso); err != nil {
+		return err
 	}
-	// hold SPI reset until needed...
+	spi.reset()
+	return nil
+}
+
+func (spi *SPI) init(tclk time.Duration, sclk, ssz, mosi, miso int, options ...Option) {
+	spi.Tclk = tclk
+	spi.Delay = gpio.Delay
+	spi.Sclk = gpio.NewPin(sclk)
+	spi.Ssz = gpio.NewPin(ssz)
+	spi.Mosi = gpio.NewPin(mosi)
+	spi.Miso = gpio.NewPin(miso)
+	for _, option := range options {
+		option(spi)
+	}
+}
+
+// reset holds the SPI reset until needed.
+func (spi *SPI) reset() {
 	spi.Sclk.Low()
 	spi.Sclk.Output()
 	spi.Ssz.High()
 	spi.Ssz.Output()
-	return spi
+}
+
+// Lock locks the SPI, or the bus for a device on a Bus, for a transaction.
+func (spi *SPI) Lock() {
+	spi.mutex().Lock()
+}
+
+// Unlock unlocks the SPI, or the bus for a device on a Bus.
+func (spi *SPI) Unlock() {
+	spi.mutex().Unlock()
+}
+
+func (spi *SPI) mutex() *sync.Mutex {
+	if spi.bus != nil {
+		return &spi.bus.mu
+	}
+	return &spi.Mu
 }
 
 // Close disables the output pins used to drive the SPI device.
+//
+// For a device on a Bus, only the slave select is affected, and it is left
+// high, so the device remains deselected while the bus is in use.
 func (spi *SPI) Close() {
-	spi.Mu.Lock()
+	spi.Lock()
+	defer spi.Unlock()
+	if spi.bus != nil {
+		spi.Ssz.High()
+		spi.Ssz.Release()
+		return
+	}
 	spi.Sclk.Input()
 	spi.Ssz.Input()
 	spi.Mosi.Input()
-	spi.Mu.Unlock()
+	gpio.ReleasePins(spi.Sclk, spi.Ssz, spi.Mosi, spi.Miso)
+}
+
+// Bus is an SPI bus shared by several devices, each with its own slave select.
+//
+// Transactions on the devices are serialised by a mutex shared by the devices.
+type Bus struct {
+	// Guards the bus, and is shared by all the devices on the bus.
+	mu   sync.Mutex
+	sclk *gpio.Pin
+	mosi *gpio.Pin
+	miso *gpio.Pin
+	// the options applied to all the devices on the bus.
+	options []Option
+}
+
+// NewBus creates a Bus.
+//
+// The options apply to all the devices on the bus.
+// The pins are requested, where available, until the Bus is closed.
+// Pins already requested by other drivers are skipped - use OpenBus to detect
+// such conflicts.
+func NewBus(sclk, mosi, miso int, options ...Option) *Bus {
+	b := newBus(sclk, mosi, miso, options...)
+	request(b.sclk, b.mosi, b.miso)
+	b.reset()
+	return b
+}
+
+// OpenBus creates a Bus, as per NewBus, but returns an error if any of the
+// pins has already been requested by another driver.
+//
+// The error wraps a *gpio.ConflictError identifying both drivers.
+func OpenBus(sclk, mosi, miso int, options ...Option) (*Bus, error) {
+	b := newBus(sclk, mosi, miso, options...)
+	if err := requestPins(b.sclk, b.mosi, b.miso); err != nil {
+		return nil, err
+	}
+	b.reset()
+	return b, nil
+}
+
+func newBus(sclk, mosi, miso int, options ...Option) *Bus {
+	return &Bus{
+		sclk:    gpio.NewPin(sclk),
+		mosi:    gpio.NewPin(mosi),
+		miso:    gpio.NewPin(miso),
+		options: options,
+	}
+}
+
+// reset holds the bus clock low until needed.
+func (b *Bus) reset() {
+	b.sclk.Low()
+	b.sclk.Output()
+}
+
+// Close disables the clock and data pins used to drive the bus.
+//
+// The devices on the bus should be closed first.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	b.sclk.Input()
+	b.mosi.Input()
+	gpio.ReleasePins(b.sclk, b.mosi, b.miso)
+	b.mu.Unlock()
+}
+
+// Device creates a SPI for the device with the slave select pin on the bus.
+//
+// The options apply only to the device.
+// The slave select pin is requested, where available, until the device is
+// closed.  A pin already requested by another driver is not requested - use
+// OpenDevice to detect such conflicts.
+func (b *Bus) Device(tclk time.Duration, ssz int, options ...Option) *SPI {
+	spi := &SPI{}
+	b.initDevice(spi, tclk, ssz, options...)
+	request(spi.Ssz)
+	spi.deselect()
+	return spi
+}
+
+// OpenDevice creates a SPI for the device, as per Device, but returns an
+// error if the slave select pin has already been requested by another
+// driver.
+//
+// The error wraps a *gpio.ConflictError identifying both drivers.
+func (b *Bus) OpenDevice(tclk time.Duration, ssz int, options ...Option) (*SPI, error) {
+	spi := &SPI{}
+	if err := b.InitDevice(spi, tclk, ssz, options...); err != nil {
+		return nil, err
+	}
+	return spi, nil
+}
+
+// InitDevice initialises a zero SPI as a device on the bus, as per
+// OpenDevice, so drivers can embed the SPI by value.
+func (b *Bus) InitDevice(spi *SPI, tclk time.Duration, ssz int, options ...Option) error {
+	b.initDevice(spi, tclk, ssz, options...)
+	if err := requestPins(spi.Ssz); err != nil {
+		return err
+	}
+	spi.deselect()
+	return nil
+}
+
+func (b *Bus) initDevice(spi *SPI, tclk time.Duration, ssz int, options ...Option) {
+	spi.Tclk = tclk
+	spi.Delay = gpio.Delay
+	spi.Sclk = b.sclk
+	spi.Ssz = gpio.NewPin(ssz)
+	spi.Mosi = b.mosi
+	spi.Miso = b.miso
+	spi.bus = b
+	for _, option := range b.options {
+		option(spi)
+	}
+	for _, option := range options {
+		option(spi)
+	}
+}
+
+// deselect holds the device deselected until needed.
+func (spi *SPI) deselect() {
+	spi.Lock()
+	spi.Ssz.High()
+	spi.Ssz.Output()
+	spi.Unlock()
 }
 
 // ClockIn clocks in a data bit from the SPI device on Miso.
 // Assumes clock starts high and ends with the rising edge of the next clock.
-// Assumes caller already holds the Mu lock.
+// Assumes caller already holds the lock, via Lock.
 func (spi *SPI) ClockIn() gpio.Level {
-	time.Sleep(spi.Tclk)
+	spi.Delay(spi.Tclk)
 	spi.Sclk.Low() // SPI device writes on the falling edge
-	time.Sleep(spi.Tclk)
+	spi.Delay(spi.Tclk)
 	b := spi.Miso.Read()
 	spi.Sclk.High()
 	return b
@@ -69,11 +290,37 @@ func (spi *SPI) ClockIn() gpio.Level {
 
 // ClockOut clocks out a data bit to the SPI device on Mosi.
 // Assumes clock starts low and ends with the falling edge of the next clock.
-// Assumes caller already holds the Mu lock.
+// Assumes caller already holds the lock, via Lock.
 func (spi *SPI) ClockOut(l gpio.Level) {
 	spi.Mosi.Write(l)
-	time.Sleep(spi.Tclk)
+	spi.Delay(spi.Tclk)
 	spi.Sclk.High() // SPI device reads on the rising edge
-	time.Sleep(spi.Tclk)
+	spi.Delay(spi.Tclk)
 	spi.Sclk.Low()
 }
+
+// request requests each of the pins that is not already requested.
+//
+// Pins already requested by other drivers are skipped, as are the data pins
+// when tied together.
+func request(pins ...*gpio.Pin) {
+	for _, pin := range pins {
+		pin.Request("spi")
+	}
+}
+
+// requestPins requests all of the pins, or none of them if any has already
+// been requested by another driver.
+//
+// Data pins tied together are only requested once.
+func requestPins(pins ...*gpio.Pin) error {
+	distinct := make([]*gpio.Pin, 0, len(pins))
+	seen := map[int]bool{}
+	for _, pin := range pins {
+		if !seen[pin.Pin()] {
+			seen[pin.Pin()] = true
+			distinct = append(distinct, pin)
+		}
+	}
+	return gpio.RequestPins("spi", distinct...)
+}
@@ -0,0 +1,76 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Stubs of the Linux specific system interfaces, for other platforms.
+//
+// These allow the package to be built, and code using it to be built and
+// unit tested, on other platforms.  Open returns ErrNotSupported so no pins
+// can be created, and NewWatcher similarly fails.
+
+//go:build !linux
+// +build !linux
+
+package gpio
+
+func mapMem(chip Chipset) error {
+	return ErrNotSupported
+}
+
+func unmapMem() error {
+	return nil
+}
+
+func raisePriority() {
+}
+
+func setRealtime(priority int) error {
+	return ErrNotSupported
+}
+
+func lockMemory() error {
+	return ErrNotSupported
+}
+
+func (w *Watcher) openPoller() error {
+	return ErrNotSupported
+}
+
+func (w *Watcher) watch() {
+	close(w.doneCh)
+}
+
+func (w *Watcher) process(block bool) bool {
+	return false
+}
+
+func (w *Watcher) wake() {
+}
+
+func (w *Watcher) kick() {
+}
+
+func (w *Watcher) closePoller() {
+}
+
+func (w *Watcher) releasePoller() {
+}
+
+func (w *Watcher) addSysfs(irq *interrupt) error {
+	return ErrNotSupported
+}
+
+func (w *Watcher) removeSysfs(irq *interrupt) {
+}
+
+func releaseSysfs(irq *interrupt) {
+}
+
+func sysfsAvailable() bool {
+	return false
+}
+
+func setEdge(p *Pin, edge Edge) error {
+	return ErrNotSupported
+}
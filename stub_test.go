@@ -0,0 +1,39 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+// +build !linux
+
+// Test suite for stub module.
+//
+// Tests do not use any pins.
+package gpio_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/warthog618/gpio"
+)
+
+func TestOpenNotSupported(t *testing.T) {
+	assert.Equal(t, gpio.ErrNotSupported, gpio.Open())
+	assert.Nil(t, gpio.Close())
+	assert.Panics(t, func() {
+		gpio.NewPin(gpio.J8p7)
+	})
+}
+
+func TestNewWatcherNotSupported(t *testing.T) {
+	w, err := gpio.NewWatcher()
+	assert.Nil(t, w)
+	assert.Equal(t, gpio.ErrNotSupported, err)
+}
+
+func TestMapPeripheralNotSupported(t *testing.T) {
+	p, err := gpio.MapPeripheral(gpio.PWMOffset, 4096)
+	assert.Nil(t, p)
+	assert.Equal(t, gpio.ErrNotSupported, err)
+}
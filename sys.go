@@ -0,0 +1,105 @@
+// Copyright © 2017 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Linux specific system interfaces.
+
+//go:build linux
+// +build linux
+
+package gpio
+
+import (
+	"fmt"
+	"os"
+	"unsafe"
+
+	"github.com/warthog618/gpio/internal/periph"
+	"golang.org/x/sys/unix"
+)
+
+// mapMem maps the GPIO registers of the chipset into mem.
+//
+// Assumes the caller holds the memlock.
+func mapMem(chip Chipset) (err error) {
+	file, offset, err := openGPIOMem(chip.Info().Base)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	// Memory map GPIO registers to byte array
+	mem8, err = unix.Mmap(
+		int(file.Fd()),
+		offset,
+		mapLength(chip),
+		unix.PROT_READ|unix.PROT_WRITE,
+		unix.MAP_SHARED)
+
+	if err != nil {
+		return
+	}
+	if mem, err = toRegs(mem8); err != nil {
+		unix.Munmap(mem8)
+		mem8 = nil
+	}
+	return
+}
+
+// unmapMem unmaps the GPIO registers.
+//
+// Assumes the caller holds the memlock.
+func unmapMem() error {
+	return unix.Munmap(mem8)
+}
+
+// openGPIOMem opens /dev/gpiomem, or /dev/mem if /dev/gpiomem does not exist,
+// and returns the offset of the GPIO registers, which are at base from the
+// peripheral base, within the opened file.
+func openGPIOMem(base int) (*os.File, int64, error) {
+	file, err := os.OpenFile("/dev/gpiomem", os.O_RDWR|os.O_SYNC, 0)
+	if err == nil || !os.IsNotExist(err) {
+		return file, 0, err
+	}
+	pbase, berr := periph.Base()
+	if berr != nil {
+		return nil, 0, err
+	}
+	file, err = os.OpenFile("/dev/mem", os.O_RDWR|os.O_SYNC, 0)
+	return file, int64(pbase) + int64(base), err
+}
+
+// raisePriority gives the calling thread the highest scheduling priority, if
+// the process has the privilege to do so.
+func raisePriority() {
+	// best effort - requires CAP_SYS_NICE.
+	unix.Setpriority(unix.PRIO_PROCESS, unix.Gettid(), -20)
+}
+
+// schedFIFO is the SCHED_FIFO scheduling policy.
+const schedFIFO = 1
+
+// setRealtime applies the SCHED_FIFO scheduling policy, at the priority, to
+// the calling thread.
+func setRealtime(priority int) error {
+	if priority < 1 || priority > 99 {
+		return ErrInvalidPriority
+	}
+	param := struct{ priority int32 }{int32(priority)}
+	_, _, errno := unix.Syscall(unix.SYS_SCHED_SETSCHEDULER, 0, schedFIFO,
+		uintptr(unsafe.Pointer(&param)))
+	if errno != 0 {
+		return fmt.Errorf("unable to set SCHED_FIFO priority: %w", errno)
+	}
+	return nil
+}
+
+// lockMemory locks the current memory of the process, faulting in any pages
+// not yet resident.
+func lockMemory() error {
+	if err := unix.Mlockall(unix.MCL_CURRENT); err != nil {
+		return fmt.Errorf("unable to lock memory: %w", err)
+	}
+	return nil
+}
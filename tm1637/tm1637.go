@@ -0,0 +1,297 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package tm1637 drives 4 digit 7-segment LED displays controlled by a TM1637.
+//
+// The TM1637 uses a 2-wire clock and data protocol, similar to I2C but without
+// addressing, in which the device acknowledges each byte by pulling the data
+// line low.  Both lines are open drain, so are driven low by setting the pin
+// to an output, and released high, by the pull-ups on the display module, by
+// setting the pin to an input.
+package tm1637
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/warthog618/gpio"
+)
+
+// Blank is a digit with no segments lit.
+const Blank = -1
+
+// Digits is the number of digits on the display.
+const Digits = 4
+
+// segments maps hexadecimal digits to their segments, with segment A in bit 0
+// through to segment G in bit 6.
+var segments = [16]byte{
+	0x3f, 0x06, 0x5b, 0x4f, 0x66, 0x6d, 0x7d, 0x07,
+	0x7f, 0x6f, 0x77, 0x7c, 0x39, 0x5e, 0x79, 0x71,
+}
+
+const (
+	// data command - write with auto-incrementing address.
+	cmdData = 0x40
+	// address command - setting the address of the first digit.
+	cmdAddress = 0xc0
+	// display control command - on with the brightness in the lower 3 bits.
+	cmdDisplay = 0x88
+	// the bit of the second digit that lights the colon.
+	colonBit = 0x80
+)
+
+// TM1637 drives a display controlled by a TM1637.
+type TM1637 struct {
+	clk *gpio.Pin
+	dio *gpio.Pin
+	// time between clock edges (i.e. half the cycle time)
+	tclk time.Duration
+	// waits between clock edges.
+	delay func(time.Duration)
+
+	// Guards the following.
+	mu         sync.Mutex
+	segs       [Digits]byte
+	colon      bool
+	brightness int
+}
+
+// Option modifies the configuration of a TM1637.
+type Option func(*TM1637)
+
+// WithDelay sets the function used to wait between clock edges.
+//
+// The default is gpio.Delay, which busy waits for short delays.
+func WithDelay(delay func(time.Duration)) Option {
+	return func(t *TM1637) {
+		t.delay = delay
+	}
+}
+
+// WithClockPeriod sets the period of the clock.
+//
+// The default is 10µs, well within the 250kHz limit of the TM1637 even
+// with the slow edges caused by the capacitors fitted to many modules.
+func WithClockPeriod(d time.Duration) Option {
+	return func(t *TM1637) {
+		if d > 0 {
+			t.tclk = d / 2
+		}
+	}
+}
+
+// New creates a TM1637 with the clock and data pins, and clears the display.
+//
+// The display is set to the maximum brightness.
+// The pins are requested until the TM1637 is closed.
+// The GPIO must be open.
+func New(clk, dio int, options ...Option) (*TM1637, error) {
+	t := &TM1637{
+		clk:        gpio.NewPin(clk),
+		dio:        gpio.NewPin(dio),
+		tclk:       5 * time.Microsecond,
+		delay:      gpio.Delay,
+		brightness: 7,
+	}
+	if t.clk == nil || t.dio == nil {
+		return nil, ErrInvalidPin
+	}
+	if err := gpio.RequestPins("tm1637", t.clk, t.dio); err != nil {
+		return nil, err
+	}
+	for _, option := range options {
+		option(t)
+	}
+	for _, pin := range []*gpio.Pin{t.clk, t.dio} {
+		// released, and only ever driven low.
+		pin.Input()
+		pin.PullUp()
+		pin.Low()
+	}
+	if err := t.update(); err != nil {
+		gpio.ReleasePins(t.clk, t.dio)
+		return nil, err
+	}
+	return t, nil
+}
+
+// Close releases the pins.
+//
+// The display continues to show its last contents.
+func (t *TM1637) Close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.clk.Input()
+	t.dio.Input()
+	gpio.ReleasePins(t.clk, t.dio)
+}
+
+// SetDigits displays the hexadecimal digits, from 0 to 15, or Blank, starting
+// at the leftmost digit.
+//
+// Digits beyond the digits provided are blanked, and those beyond the display
+// are ignored.
+func (t *TM1637) SetDigits(digits ...int) error {
+	var segs [Digits]byte
+	for i := 0; i < len(digits) && i < Digits; i++ {
+		d := digits[i]
+		switch {
+		case d == Blank:
+		case d < 0 || d >= len(segments):
+			return ErrInvalidDigit
+		default:
+			segs[i] = segments[d]
+		}
+	}
+	return t.SetSegments(segs[:]...)
+}
+
+// SetSegments displays the raw segments, starting at the leftmost digit, with
+// segment A in bit 0 through to segment G in bit 6, and the decimal point, if
+// present, in bit 7.
+//
+// Digits beyond the segments provided are blanked, and those beyond the
+// display are ignored.
+func (t *TM1637) SetSegments(segs ...byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.segs = [Digits]byte{}
+	copy(t.segs[:], segs)
+	return t.update()
+}
+
+// SetColon sets whether the colon between the middle digits is lit.
+func (t *TM1637) SetColon(on bool) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.colon = on
+	return t.update()
+}
+
+// SetBrightness sets the brightness of the display, from 0 (dimmest) to 7
+// (brightest).
+func (t *TM1637) SetBrightness(b int) error {
+	if b < 0 || b > 7 {
+		return ErrInvalidBrightness
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.brightness = b
+	return t.update()
+}
+
+// Clear blanks all the digits and the colon.
+func (t *TM1637) Clear() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.segs = [Digits]byte{}
+	t.colon = false
+	return t.update()
+}
+
+// update writes the segments and brightness to the display.
+//
+// Assumes caller already holds the mu lock.
+func (t *TM1637) update() error {
+	data := make([]byte, 0, Digits+1)
+	data = append(data, cmdAddress)
+	data = append(data, t.segs[:]...)
+	if t.colon {
+		data[2] |= colonBit
+	}
+	for _, cmd := range [][]byte{{cmdData}, data, {cmdDisplay | byte(t.brightness)}} {
+		if err := t.write(cmd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// write writes a command, and any data that follows it, to the display.
+func (t *TM1637) write(cmd []byte) error {
+	t.start()
+	defer t.stop()
+	for _, b := range cmd {
+		if !t.writeByte(b) {
+			return ErrNoAck
+		}
+	}
+	return nil
+}
+
+// release allows the pin to be pulled high.
+func release(pin *gpio.Pin) {
+	pin.Input()
+}
+
+// drive pulls the pin low.
+func drive(pin *gpio.Pin) {
+	pin.Output()
+}
+
+// start signals the start of a command - a falling data edge while the clock
+// is high.
+func (t *TM1637) start() {
+	release(t.clk)
+	release(t.dio)
+	t.delay(t.tclk)
+	drive(t.dio)
+	t.delay(t.tclk)
+	drive(t.clk)
+}
+
+// stop signals the end of a command - a rising data edge while the clock is
+// high.
+func (t *TM1637) stop() {
+	drive(t.clk)
+	drive(t.dio)
+	t.delay(t.tclk)
+	release(t.clk)
+	t.delay(t.tclk)
+	release(t.dio)
+	t.delay(t.tclk)
+}
+
+// writeByte clocks out the byte, LSB first, and returns true if the display
+// acknowledged it.
+//
+// Assumes the clock starts low, and leaves it low.
+func (t *TM1637) writeByte(b byte) bool {
+	for i := uint(0); i < 8; i++ {
+		if b&(1<<i) != 0 {
+			release(t.dio)
+		} else {
+			drive(t.dio)
+		}
+		t.delay(t.tclk)
+		release(t.clk)
+		t.delay(t.tclk)
+		drive(t.clk)
+	}
+	// the display pulls data low to acknowledge on the ninth clock.
+	release(t.dio)
+	t.delay(t.tclk)
+	release(t.clk)
+	t.delay(t.tclk)
+	ack := t.dio.Read() == gpio.Low
+	drive(t.clk)
+	return ack
+}
+
+var (
+	// ErrInvalidBrightness indicates the brightness is outside the range 0-7.
+	ErrInvalidBrightness = errors.New("invalid brightness")
+
+	// ErrInvalidDigit indicates a digit is not a hexadecimal digit or Blank.
+	ErrInvalidDigit = errors.New("invalid digit")
+
+	// ErrInvalidPin indicates a pin is not a valid GPIO pin.
+	ErrInvalidPin = errors.New("invalid pin")
+
+	// ErrNoAck indicates the display did not acknowledge a command.
+	ErrNoAck = errors.New("no acknowledgement from display")
+)
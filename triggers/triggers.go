@@ -0,0 +1,194 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package triggers provides a debounced input that triggers a pulse on an
+// output, such as a doorbell button triggering a chime or a door strike.
+package triggers
+
+import (
+	"sync"
+	"time"
+
+	"github.com/warthog618/gpio"
+)
+
+// Trigger pulses an output pin in response to an edge on an input pin.
+//
+// The input is debounced, and the output is not retriggered until the
+// cooldown period has passed since the start of the previous pulse.
+type Trigger struct {
+	in       *gpio.Pin
+	out      *gpio.Pin
+	pulse    time.Duration
+	edge     gpio.Edge
+	active   gpio.Level
+	debounce time.Duration
+	cooldown time.Duration
+	handler  func(time.Time)
+
+	// Guards the following.
+	mu sync.Mutex
+	// the level of the input when last seen.
+	level gpio.Level
+	timer *time.Timer
+	// ends the current pulse, if any.
+	end     *time.Timer
+	pulsing bool
+	last    time.Time
+	closed  bool
+}
+
+// Option modifies the configuration of a Trigger.
+type Option func(*Trigger)
+
+// WithEdge sets the edge on the input that triggers the pulse.
+//
+// Only EdgeRising and EdgeFalling are meaningful.
+// The default is EdgeFalling, as for a button pulled up and shorted to ground.
+func WithEdge(edge gpio.Edge) Option {
+	return func(t *Trigger) {
+		t.edge = edge
+	}
+}
+
+// WithDebounce sets the period the input must remain at the triggered level
+// before the pulse is triggered.
+//
+// The default is 10ms.
+func WithDebounce(d time.Duration) Option {
+	return func(t *Trigger) {
+		t.debounce = d
+	}
+}
+
+// WithCooldown sets the minimum period between the start of pulses.
+//
+// The default is the pulse length.  A pulse is never retriggered before it
+// has completed, even if the cooldown is shorter than the pulse.
+func WithCooldown(d time.Duration) Option {
+	return func(t *Trigger) {
+		t.cooldown = d
+	}
+}
+
+// WithActiveLevel sets the level of the output during the pulse.
+//
+// The default is High.
+func WithActiveLevel(l gpio.Level) Option {
+	return func(t *Trigger) {
+		t.active = l
+	}
+}
+
+// WithHandler sets a function to be called each time the pulse is triggered.
+//
+// The handler is passed the time the pulse started, and is called after the
+// pulse has completed.
+func WithHandler(h func(time.Time)) Option {
+	return func(t *Trigger) {
+		t.handler = h
+	}
+}
+
+// New creates a Trigger that pulses the out pin for the pulse duration when
+// the in pin sees a triggering edge.
+//
+// The in pin is set to an input, and the out pin to an output at its inactive
+// level.  The pins are requested until the Trigger is closed.
+func New(in, out int, pulse time.Duration, options ...Option) (*Trigger, error) {
+	t := &Trigger{
+		in:       gpio.NewPin(in),
+		out:      gpio.NewPin(out),
+		pulse:    pulse,
+		edge:     gpio.EdgeFalling,
+		active:   gpio.High,
+		debounce: 10 * time.Millisecond,
+		cooldown: pulse,
+	}
+	for _, option := range options {
+		option(t)
+	}
+	if err := gpio.RequestPins("triggers", t.in, t.out); err != nil {
+		return nil, err
+	}
+	t.out.Write(!t.active)
+	t.out.Output()
+	t.in.Input()
+	t.level = t.in.Read()
+	// all edges are watched, so bounces restart the debounce period.
+	if _, err := t.in.AddWatch(gpio.EdgeBoth, t.edgeHandler); err != nil {
+		gpio.ReleasePins(t.in, t.out)
+		return nil, err
+	}
+	return t, nil
+}
+
+// Close removes the watch on the input and returns the output to its inactive
+// level.
+func (t *Trigger) Close() {
+	t.in.Unwatch()
+	t.mu.Lock()
+	t.closed = true
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+	if t.end != nil {
+		t.end.Stop()
+	}
+	t.out.Write(!t.active)
+	gpio.ReleasePins(t.in, t.out)
+	t.mu.Unlock()
+}
+
+func (t *Trigger) edgeHandler(evt gpio.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed || evt.Level == t.level {
+		// no change, such as the initial event from the watch.
+		return
+	}
+	t.level = evt.Level
+	if t.timer == nil {
+		t.timer = time.AfterFunc(t.debounce, t.fire)
+		return
+	}
+	t.timer.Reset(t.debounce)
+}
+
+func (t *Trigger) fire() {
+	triggered := gpio.Low
+	if t.edge == gpio.EdgeRising {
+		triggered = gpio.High
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	if t.closed || t.pulsing ||
+		t.in.Read() != triggered ||
+		(!t.last.IsZero() && now.Sub(t.last) < t.cooldown) {
+		return
+	}
+	t.last = now
+	t.pulsing = true
+	t.out.Write(t.active)
+	// the lock is not held for the pulse, so edges and Close are not blocked.
+	t.end = time.AfterFunc(t.pulse, func() { t.endPulse(now) })
+}
+
+// endPulse returns the output to its inactive level at the end of the pulse
+// started at start.
+func (t *Trigger) endPulse(start time.Time) {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return
+	}
+	t.out.Write(!t.active)
+	t.pulsing = false
+	t.mu.Unlock()
+	if t.handler != nil {
+		t.handler(start)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Waveform output for DIO Pins.
+
+package gpio
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Step is a single step in a waveform played on a Pin.
+type Step struct {
+	// The level of the pin for the step.
+	Level Level
+
+	// The time the level is held before the next step.
+	Duration time.Duration
+}
+
+// GroupStep is a single step in a waveform played on a PinGroup.
+type GroupStep struct {
+	// The value written to the group for the step.
+	Value uint
+
+	// The time the value is held before the next step.
+	Duration time.Duration
+}
+
+// Wave is a waveform being played on one or more pins.
+type Wave struct {
+	stopOnce sync.Once
+	// closed to stop the player.
+	stopCh chan struct{}
+	// closed when the player exits.
+	doneCh chan struct{}
+}
+
+// spinPeriod is the time before the end of a step that the player stops
+// sleeping and busy waits, as sleeps are not precise enough for short steps.
+const spinPeriod = 100 * time.Microsecond
+
+// PlayWave plays the waveform on the pin.
+//
+// The steps are played repeat times, or indefinitely if repeat is 0.
+// The pin is not set to an output - that is left to the caller.
+//
+// The waveform is played by a dedicated goroutine locked to its own thread,
+// which is given the highest scheduling priority if the process has the
+// privilege to do so.
+func (p *Pin) PlayWave(steps []Step, repeat int) *Wave {
+	steps = append([]Step(nil), steps...)
+	durations := make([]time.Duration, len(steps))
+	for i, s := range steps {
+		durations[i] = s.Duration
+	}
+	return playWave(durations, repeat, func(i int) {
+		p.Write(steps[i].Level)
+	})
+}
+
+// PlayWave plays the waveform on the group.
+//
+// The steps are played repeat times, or indefinitely if repeat is 0.
+// The pins are not set to outputs - that is left to the caller.
+func (g *PinGroup) PlayWave(steps []GroupStep, repeat int) *Wave {
+	steps = append([]GroupStep(nil), steps...)
+	durations := make([]time.Duration, len(steps))
+	for i, s := range steps {
+		durations[i] = s.Duration
+	}
+	return playWave(durations, repeat, func(i int) {
+		g.Write(steps[i].Value)
+	})
+}
+
+func playWave(durations []time.Duration, repeat int, write func(i int)) *Wave {
+	w := &Wave{
+		stopCh: make(chan struct{}),
+		doneCh: make(chan struct{}),
+	}
+	go w.play(durations, repeat, write)
+	return w
+}
+
+func (w *Wave) play(durations []time.Duration, repeat int, write func(i int)) {
+	defer close(w.doneCh)
+	if len(durations) == 0 {
+		return
+	}
+	// never unlocked, so the thread, and the priority applied to it, is
+	// discarded when the wave ends, rather than returned to the scheduler.
+	runtime.LockOSThread()
+	raisePriority()
+	next := time.Now()
+	for count := 0; repeat == 0 || count < repeat; count++ {
+		for i, d := range durations {
+			write(i)
+			next = next.Add(d)
+			if !w.wait(next) {
+				return
+			}
+		}
+	}
+}
+
+// wait waits until the deadline, returning false if the Wave is stopped.
+func (w *Wave) wait(deadline time.Time) bool {
+	if d := time.Until(deadline) - spinPeriod; d > 0 {
+		select {
+		case <-time.After(d):
+		case <-w.stopCh:
+			return false
+		}
+	}
+	for time.Now().Before(deadline) {
+	}
+	select {
+	case <-w.stopCh:
+		return false
+	default:
+	}
+	return true
+}
+
+// Stop stops playing the waveform, leaving the pins at their current levels,
+// and waits for the player to exit.
+func (w *Wave) Stop() {
+	w.stopOnce.Do(func() { close(w.stopCh) })
+	<-w.doneCh
+}
+
+// Wait waits for the waveform to complete.
+//
+// A waveform that repeats indefinitely only completes when stopped.
+func (w *Wave) Wait() {
+	<-w.doneCh
+}
+
+// Done returns a channel that is closed when the waveform completes.
+func (w *Wave) Done() <-chan struct{} {
+	return w.doneCh
+}
@@ -0,0 +1,64 @@
+// Copyright © 2026 Kent Gibson <warthog618@gmail.com>.
+//
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build linux && hardware
+// +build linux,hardware
+
+// Test suite for wave module.
+//
+// Tests use Raspberry Pi J8 pins 15 and 16 which must be jumpered together.
+package gpio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Looped tests require a jumper across Raspberry Pi J8 pins 15 and 16.
+func TestPlayWaveLooped(t *testing.T) {
+	pinIn, pinOut, watcher := setupIntr(t)
+	defer teardownIntr(pinIn, pinOut, watcher)
+	steps := []Step{
+		{High, 2 * time.Millisecond},
+		{Low, 3 * time.Millisecond},
+	}
+	start := time.Now()
+	w := pinOut.PlayWave(steps, 4)
+	time.Sleep(time.Millisecond)
+	assert.Equal(t, High, pinIn.Read())
+	w.Wait()
+	assert.InDelta(t, 20*time.Millisecond, time.Since(start), float64(time.Millisecond))
+	assert.Equal(t, Low, pinIn.Read())
+	select {
+	case <-w.Done():
+	default:
+		t.Error("not done")
+	}
+	// repeat indefinitely
+	w = pinOut.PlayWave(steps, 0)
+	time.Sleep(20 * time.Millisecond)
+	select {
+	case <-w.Done():
+		t.Error("done before stop")
+	default:
+	}
+	w.Stop()
+	// and again just for coverage.
+	w.Stop()
+}
+
+// Looped tests require a jumper across Raspberry Pi J8 pins 15 and 16.
+func TestGroupPlayWaveLooped(t *testing.T) {
+	pinIn, pinOut, watcher := setupIntr(t)
+	defer teardownIntr(pinIn, pinOut, watcher)
+	g := NewPinGroup(J8p16)
+	w := g.PlayWave([]GroupStep{{1, 5 * time.Millisecond}, {0, time.Millisecond}}, 1)
+	time.Sleep(time.Millisecond)
+	assert.Equal(t, High, pinIn.Read())
+	w.Wait()
+	assert.Equal(t, Low, pinIn.Read())
+}